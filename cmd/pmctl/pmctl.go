@@ -14,6 +14,7 @@ import (
 
 	"github.com/vmware/pmd-next-gen/pkg/validator"
 	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/networkd"
 )
 
 func main() {
@@ -440,6 +441,23 @@ func main() {
 			Name:    "network",
 			Aliases: []string{"n"},
 			Usage:   "Network device configuration",
+			Flags: []cli.Flag{
+				&cli.UintFlag{Name: "wait", Usage: "Wait up to the given seconds for the link to come up"},
+				&cli.StringFlag{Name: "wait-state", Usage: "Operational state to wait for {routable|degraded|carrier...}"},
+				&cli.BoolFlag{Name: "auto-revert", Usage: "Revert the configuration when the link does not come up"},
+				&cli.StringFlag{Name: "mgmt-link", Usage: "Link which must stay routable while applying"},
+				&cli.StringFlag{Name: "netns", Usage: "Network namespace name or pid the netlink commands act in"},
+			},
+			Before: func(c *cli.Context) error {
+				networkWait = networkd.WaitSection{
+					TimeoutSec:       c.Uint("wait"),
+					OperationalState: c.String("wait-state"),
+					AutoRevert:       c.Bool("auto-revert"),
+					ManagementLink:   c.String("mgmt-link"),
+				}
+				networkNetNs = c.String("netns")
+				return nil
+			},
 			Subcommands: []*cli.Command{
 				{
 					Name:        "set-dhcp",
@@ -488,7 +506,7 @@ func main() {
 				},
 				{
 					Name:        "add-route",
-					UsageText:   "add-route dev [LINK] gw [STRING] gwonlink [STRING] dest [STRING] src [STRING] prefsrc [STRING] table [STRING] scope [STRING]",
+					UsageText:   "add-route dev [LINK] gw [STRING] gwonlink [STRING] dest [STRING] src [STRING] prefsrc [STRING] table [STRING] scope [STRING] metric [NUMBER] proto [STRING] type [STRING] ipv6pref {low|medium|high} mtu [NUMBER] initcwnd [NUMBER] initrwnd [NUMBER] quickack [BOOLEAN] fastopennocookie [BOOLEAN] multipath [ADDRESS@LINK WEIGHT]... nexthop [NUMBER]",
 					Description: "Configure network route.",
 
 					Action: func(c *cli.Context) error {
@@ -501,6 +519,161 @@ func main() {
 						return nil
 					},
 				},
+				{
+					Name:        "add-runtime-route",
+					UsageText:   "add-runtime-route dst {default|ADDRESS} gw [ADDRESS] dev [LINK] src [ADDRESS] table {main|NAME|NUMBER} metric [NUMBER] protocol {static|NAME|NUMBER} scope {global|link|host} type {unicast|blackhole|unreachable|prohibit|throw|local} tos [NUMBER] mtu [NUMBER] onlink [BOOLEAN] nexthop [GW@LINK:WEIGHT] encap-mpls [LABEL/LABEL] encap-seg6 [ADDRESS,ADDRESS] encap-seg6-mode {encap|inline}",
+					Description: "Adds route with netlink. Not persistent, lost when the link is reconfigured.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddRuntimeRoute(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "replace-runtime-route",
+					UsageText:   "replace-runtime-route dst {default|ADDRESS} gw [ADDRESS] dev [LINK] src [ADDRESS] table {main|NAME|NUMBER} metric [NUMBER] protocol {static|NAME|NUMBER} scope {global|link|host} type {unicast|blackhole|unreachable|prohibit|throw|local} tos [NUMBER] mtu [NUMBER] onlink [BOOLEAN] nexthop [GW@LINK:WEIGHT] encap-mpls [LABEL/LABEL] encap-seg6 [ADDRESS,ADDRESS] encap-seg6-mode {encap|inline}",
+					Description: "Replaces route with netlink. Not persistent, lost when the link is reconfigured.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkReplaceRuntimeRoute(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-runtime-route",
+					UsageText:   "remove-runtime-route dst {default|ADDRESS} gw [ADDRESS] dev [LINK] table {main|NAME|NUMBER} metric [NUMBER] type [TYPE]",
+					Description: "Removes route with netlink.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveRuntimeRoute(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-routes",
+					UsageText:   "show-routes table {main|all|NAME|NUMBER} family {ipv4|ipv6} dev [LINK] protocol {NAME|NUMBER}",
+					Description: "Shows routes.",
+
+					Action: func(c *cli.Context) error {
+						acquireRoutes(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "get-route",
+					UsageText:   "get-route [ADDRESS]",
+					Description: "Shows the route used to reach the address.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						acquireRouteGet(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-nexthop",
+					UsageText:   "add-nexthop dev [LINK] id [NUMBER] gw [ADDRESS] family {ipv4|ipv6} onlink [BOOLEAN] blackhole [BOOLEAN] group [ID:WEIGHT,...]",
+					Description: "Add a nexthop object, routes refer to it with nexthop [ID]",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddNextHop(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-nexthop",
+					UsageText:   "remove-nexthop dev [LINK] id [NUMBER]",
+					Description: "Remove a nexthop object",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveNextHop(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-nexthops",
+					UsageText:   "show-nexthops",
+					Description: "Show the nexthop objects of the kernel",
+
+					Action: func(c *cli.Context) error {
+						acquireNextHops(c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-qdisc",
+					UsageText:   "add-qdisc dev [LINK] kind {fq|fq_codel|tbf|cake|netem|htb|htb-class|clsact|ingress} parent {root|clsact|ingress|MAJOR:MINOR} handle [MAJOR:] [KEY] [VALUE]...",
+					Description: "Configure a qdisc or htb class, keys are the networkd options of the kind e.g. rate 10M latencysec 50ms",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddQDisc(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-qdisc",
+					UsageText:   "remove-qdisc dev [LINK] kind [KIND] parent [PARENT] | classid [MAJOR:MINOR]",
+					Description: "Remove a qdisc by parent or an htb class by classid",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveQDisc(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-qdisc",
+					UsageText:   "show-qdisc [LINK]",
+					Description: "Show the qdiscs and classes of the link with statistics",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						acquireTrafficControl(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
 				{
 					Name:        "add-dns",
 					UsageText:   "dev [LINK] dns [DNS]",
@@ -561,6 +734,105 @@ func main() {
 						return nil
 					},
 				},
+				{
+					Name:        "set-runtime-dns",
+					UsageText:   "dev [LINK] dns [ADDRESS,...] domains [DOMAIN,...] default-route [BOOLEAN] llmnr {yes|no|resolve} mdns {yes|no|resolve} dnssec {yes|no|allow-downgrade} dns-over-tls {yes|no|opportunistic}",
+					Description: "Configure Link DNS in systemd-resolved at runtime. Domains prefixed with ~ are routing-only. Not persistent.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureRuntimeDns(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "revert-runtime-dns",
+					UsageText:   "[LINK]",
+					Description: "Drop runtime DNS configuration of Link in systemd-resolved",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRevertRuntimeDns(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-resolved-conf",
+					UsageText:   "fallback-dns [ADDRESS,...] dnssec {yes|no|allow-downgrade} dns-over-tls {yes|no|opportunistic} llmnr {yes|no|resolve} mdns {yes|no|resolve} cache {yes|no|no-negative} stub-listener {yes|no|udp|tcp} stub-listener-extra [ADDRESS,...] read-etc-hosts [BOOLEAN] single-label [BOOLEAN]",
+					Description: "Configure systemd-resolved options in a resolved.conf drop-in",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureResolvedConfig(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "reset-resolved-conf",
+					Description: "Remove the resolved.conf drop-in",
+
+					Action: func(c *cli.Context) error {
+						networkResetResolvedConfig(c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-resolved-conf",
+					Description: "Show configured and effective systemd-resolved options",
+
+					Action: func(c *cli.Context) error {
+						networkShowResolvedConfig(c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-dns-statistics",
+					Description: "Show systemd-resolved transaction, cache and DNSSEC statistics",
+
+					Action: func(c *cli.Context) error {
+						networkShowResolvedStatistics(c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "reset-dns-statistics",
+					Description: "Reset systemd-resolved statistics counters",
+
+					Action: func(c *cli.Context) error {
+						networkResolvedAction("statistics/reset", "reset resolved statistics", c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "flush-dns-caches",
+					Description: "Flush systemd-resolved caches",
+
+					Action: func(c *cli.Context) error {
+						networkResolvedAction("flushcaches", "flush resolved caches", c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "reset-dns-server-features",
+					Description: "Have systemd-resolved probe the feature levels of DNS servers again",
+
+					Action: func(c *cli.Context) error {
+						networkResolvedAction("resetserverfeatures", "reset resolved server features", c.String("url"), token)
+						return nil
+					},
+				},
 				{
 					Name:        "add-ntp",
 					UsageText:   "dev [LINK] ntp [NTP]",
@@ -641,315 +913,1013 @@ func main() {
 					UsageText:   "set-dhcpv4-use dev [LINK] usedns [BOOLEAN] usentp [BOOLEAN] usesip [BOOLEAN] usemtu [BOOLEAN] usehostname [BOOLEAN] usedomains [BOOLEAN] useroutes [BOOLEAN] usegateway [BOOLEAN] usetimezone [BOOLEAN]",
 					Description: "Configures Link DHCPv4 enable the use of recevied DNS, MTU, DOMAINS etc",
 
-					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
-							fmt.Printf("Too few arguments.\n")
-							return nil
-						}
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureDHCPv4UseOption(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-dhcpv6",
+					UsageText:   "set-dhcpv6 dev [LINK] mudurl [STRING] userclass [STRING] vendorclass [STRING] prefixhint [STRING] withoutra [STRING]",
+					Description: "Configures Link DHCPv6 url, class, prefix hint and withoutRA.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureDHCPv6(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-dhcpv6-id",
+					UsageText:   "set-dhcpv6-id dev [LINK] iaid [STRING] duidtype [STRING] duidrawdata [STRING]",
+					Description: "Configures Link DHCPv6 identifiers.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureDHCPv6Id(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-dhcpv6-use",
+					UsageText:   "set-dhcpv6-use dev [LINK] useaddr [BOOLEAN] useprefix [BOOLEAN] usedns [BOOLEAN] usentp [BOOLEAN] usehostname [BOOLEAN] usedomains [BOOLEAN]",
+					Description: "Configures Link DHCPv6 enable the use of Address, Prefix, DNS, NTP, DOMAINS etc",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureDHCPv6Use(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-dhcpv6-option",
+					UsageText:   "set-dhcpv6-option dev [LINK] reqopt [STRING] sendopt [STRING] sendvendoropt [STRING]",
+					Description: "Configures Link DHCPv6 Options",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureDHCPv6Option(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-lease",
+					UsageText:   "show-lease [LINK]",
+					Description: "Show the DHCP lease of the link",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						acquireDHCPLease(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "renew-lease",
+					UsageText:   "renew-lease [LINK]",
+					Description: "Renew the DHCP lease of the link",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRenewDHCPLease(c.Args().First(), false, c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "force-renew-lease",
+					UsageText:   "force-renew-lease [LINK]",
+					Description: "Force the DHCP server to renew the lease of the link",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRenewDHCPLease(c.Args().First(), true, c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-dhcpv4-server",
+					UsageText:   "add-dhcpv4-server dev [LINK] pool-offset [NUMBER] pool-size [NUMBER] default-lease-time-sec [NUMBER] max-lease-time-sec [NUMBER] dns [STRING] emit-dns [BOOLEAN] emit-ntp [BOOLEAN] emit-router [BOOLEAN] server-address [ADDRESS] uplink [LINK] ntp [STRING] emit-timezone [BOOLEAN] timezone [STRING] boot-server-address [ADDRESS] boot-server-name [STRING] boot-filename [STRING] relay-target [ADDRESS] relay-circuit-id [STRING] relay-remote-id [STRING]",
+					Description: "Confifure the DHCPv4 Server",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureAddDHCPv4Server(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-dhcpv4-server",
+					UsageText:   "remove-dhcpv4-server [LINK]",
+					Description: "Remove the DHCPv4 Server",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureRemoveDHCPv4Server(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-dhcpv4-server-static-lease",
+					UsageText:   "add-dhcpv4-server-static-lease dev [LINK] mac [MACADDRESS] address [ADDRESS]",
+					Description: "Reserve an address for a client of the DHCPv4 Server",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 6 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddDHCPServerStaticLease(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-dhcpv4-server-static-lease",
+					UsageText:   "remove-dhcpv4-server-static-lease dev [LINK] mac [MACADDRESS]",
+					Description: "Remove an address reservation of the DHCPv4 Server",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveDHCPServerStaticLease(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-dhcpv4-server-leases",
+					UsageText:   "show-dhcpv4-server-leases [LINK]",
+					Description: "Show the leases handed out by the DHCPv4 Server",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						acquireDHCPServerLeases(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "reset-network-dropin",
+					UsageText:   "reset-network-dropin [LINK]",
+					Description: "Remove the managed drop-in of the link's .network file",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkResetDropin(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-ipv6ra",
+					UsageText:   "add-ipv6ra dev [LINK] rt-pref [STRING] emit-dns [STRING] dns [STRING] emit-domains [STRING] domains [STRING] dns-lifetime-sec [INTEGER] prefix [STRING] pref-lifetime-sec [INTEGER] valid-lifetime-sec [INTEGER] assign [STRING] route [STRING] lifetime-sec [INTEGER]",
+					Description: "Configure IPv6 Router Advertisement (RA) send ona link for the interface.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureIPv6SendRA(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-ipv6ra",
+					UsageText:   "remove-ipv6ra [LINK]",
+					Description: "Remove IPV6SendRA, IPv6Prefix and IPv6RoutePrefix",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureRemoveIPv6SendRA(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-mtu",
+					UsageText:   "set-mtu [LINK] [MTU NUMBER]",
+					Description: "Configures Link MTU.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						if !validator.IsUint32(c.Args().Get(1)) {
+							fmt.Printf("MTU must be a valid value.\n")
+							return nil
+						}
+
+						networkConfigureMTU(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-mac",
+					UsageText:   "set-mac [LINK] [MAC]",
+					Description: "Configures Link MAC address.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						if validator.IsNotMAC(c.Args().Get(1)) {
+							fmt.Printf("Invalid MAC address: %v\n", c.Args().Get(1))
+							return nil
+						}
+
+						networkConfigureMAC(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-group",
+					UsageText:   "set-group [LINK] [GROUP INTEGER]",
+					Description: "Configures Link Group.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureLinkGroup(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-rf-online",
+					UsageText:   "set-rf-online [LINK] [FAMILY STRING]",
+					Description: "Configures Link RequiredFamilyForOnline.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureLinkRequiredFamilyForOnline(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-active-policy",
+					UsageText:   "set-active-policy [LINK] [POLICY STRING]",
+					Description: "Configures Link ActivationPolicy.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureLinkActivationPolicy(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-link-mode",
+					UsageText:   "set-link-mode [LINK] mode [BOOLEAN] arp [BOOLEAN] mc [BOOLEAN] amc [BOOLEAN] pcs [BOOLEAN] rfo [BOOLEAN]",
+					Description: "Set Link mode,arp,multicast,allmulticast,promiscuous and requiredforonline.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureMode(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "set-runtime-link",
+					UsageText:   "set-runtime-link [LINK] {up|down} mtu [MTU] txqlen [LENGTH] alias [ALIAS] mac [ADDRESS] promisc [BOOLEAN] master [LINK] nomaster enslave [LINK,LINK...] netns [NAME]",
+					Description: "Configures link in place with netlink. Not persistent.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureRuntimeLink(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-link-address",
+					UsageText:   "add-link-address [LINK] address [ADDRESS] peer [ADDRESS] label [NUMBER] scope {global|link|host|NUMBER} broadcast [ADDRESS|BOOLEAN] preferred-lifetime {forever|infinity|0} dad {ipv4|ipv6|both|none} home-address [BOOLEAN] manage-temp-address [BOOLEAN] prefix-route [BOOLEAN] auto-join [BOOLEAN] route-metric [NUMBER] netlabel [STRING]",
+					Description: "Configures Link Address.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkConfigureAddress(c.Args().First(), c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-runtime-address",
+					UsageText:   "add-runtime-address dev [LINK] address [ADDRESS] peer [ADDRESS] label [STRING] scope {global|site|link|host} broadcast [ADDRESS] preferred-lft {forever|NUMBER} valid-lft {forever|NUMBER} flags [nodad,optimistic,homeaddress,mngtmpaddr,noprefixroute,autojoin]",
+					Description: "Adds address to a link with netlink. Not persistent, lost when the link is reconfigured.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddRuntimeAddress(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-runtime-address",
+					UsageText:   "remove-runtime-address dev [LINK] address [ADDRESS]",
+					Description: "Removes address from a link with netlink. Not persistent, networkd puts configured addresses back on reconfigure.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveRuntimeAddress(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-addresses",
+					UsageText:   "show-addresses [LINK]",
+					Description: "Shows addresses of link.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						acquireLinkAddresses(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-sriov",
+					UsageText:   "add-sriov dev [LINK MASTER] vf [NUMBER] vlanid [NUMBER] qos [NUMBER] vlanproto [STRING] macsfc [BOOLEAN] qrss [BOOLEAN] trust [BOOLEAN] linkstate [STRING] macaddr [ADDRESS]",
+					Description: "Add SR-IOV.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddSRIOV(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-rule",
+					UsageText:   "add-rule dev [LINK MASTER] tos [NUMBER] from [ADDRESS] to [ADDRESS] fwmark [STRING] table [STRING] prio [NUMBER] iif [STRING] oif [STRING] srcport [STRING] destport [STRING] ipproto [STRING] invertrule [STRING] family [STRING] usr [STRING] suppressprefixlen [NUMBER] suppressifgrp [NUMBER] type [STRING]",
+					Description: "Add RoutingPolicyRule.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddRoutingPolicyRule(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "delete-rule",
+					UsageText:   "delete-rule dev [LINK MASTER] tos [NUMBER] from [ADDRESS] to [ADDRESS] fwmark [STRING] table [STRING] prio [NUMBER] iif [STRING] oif [STRING] srcport [STRING] destport [STRING] ipproto [STRING] invertrule [STRING] family [STRING] usr [STRING] suppressprefixlen [NUMBER] suppressifgrp [NUMBER] type [STRING]",
+					Description: "Delete RoutingPolicyRule.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveRoutingPolicyRule(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-runtime-rule",
+					UsageText:   "add-runtime-rule tos [NUMBER] from [ADDRESS] to [ADDRESS] fwmark [MARK/MASK] table [NAME|NUMBER] goto [NUMBER] prio [NUMBER] iif [STRING] oif [STRING] srcport [STRING] destport [STRING] ipproto [STRING] invertrule [BOOLEAN] family {ipv4|ipv6|both} usr [STRING] suppressprefixlen [NUMBER] suppressifgrp [NUMBER] type {blackhole|unreachable|prohibit|nop} protocol [NAME|NUMBER]",
+					Description: "Adds routing policy rule with netlink. Not persistent.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddRuntimeRule(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-runtime-rule",
+					UsageText:   "remove-runtime-rule tos [NUMBER] from [ADDRESS] to [ADDRESS] fwmark [MARK/MASK] table [NAME|NUMBER] goto [NUMBER] prio [NUMBER] iif [STRING] oif [STRING] srcport [STRING] destport [STRING] ipproto [STRING] invertrule [BOOLEAN] family {ipv4|ipv6|both} usr [STRING] suppressprefixlen [NUMBER] suppressifgrp [NUMBER] type {blackhole|unreachable|prohibit|nop} protocol [NAME|NUMBER]",
+					Description: "Removes routing policy rule with netlink.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 2 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveRuntimeRule(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-rules",
+					UsageText:   "show-rules [ipv4|ipv6]",
+					Description: "Shows routing policy rules.",
+
+					Action: func(c *cli.Context) error {
+						acquireRules(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-bridge-port",
+					UsageText:   "add-bridge-port dev [LINK] bridge [BRIDGE] cost [NUMBER] prio [NUMBER] hairpin [BOOLEAN] isolated [BOOLEAN] usebpdu [BOOLEAN] learning [BOOLEAN]",
+					Description: "Attach link to bridge with port options.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddBridgePort(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-bridge-vlan",
+					UsageText:   "add-bridge-vlan dev [LINK] vlan [ID|RANGE] pvid [ID] untagged [ID|RANGE]",
+					Description: "Add bridge VLAN membership of link.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddBridgeVLAN(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-bridge-vlan",
+					UsageText:   "remove-bridge-vlan dev [LINK] vlan [ID|RANGE]",
+					Description: "Remove bridge VLAN membership of link.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveBridgeVLAN(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-bridge-fdb",
+					Description: "Show bridge forwarding database.",
+
+					Action: func(c *cli.Context) error {
+						acquireBridgeFDB(c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-bridge-fdb",
+					UsageText:   "add-bridge-fdb dev [LINK] mac [ADDRESS] vlan [NUMBER] dst [ADDRESS] vni [NUMBER] state {permanent|static|dynamic} flags [self,master,router]",
+					Description: "Adds bridge or vxlan FDB entry with netlink. Not persistent.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddBridgeFDB(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-bridge-fdb",
+					UsageText:   "remove-bridge-fdb dev [LINK] mac [ADDRESS] vlan [NUMBER] dst [ADDRESS] vni [NUMBER]",
+					Description: "Removes bridge or vxlan FDB entry with netlink.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveBridgeFDB(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-neighbor",
+					UsageText:   "add-neighbor dev [LINK] address [ADDRESS] mac [ADDRESS] state {PERMANENT|NOARP|REACHABLE|STALE} router [BOOLEAN] proxy [BOOLEAN]",
+					Description: "Adds or replaces ARP/NDP neighbor entry with netlink. Not persistent.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddNeighbor(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-neighbor",
+					UsageText:   "remove-neighbor dev [LINK] address [ADDRESS] proxy [BOOLEAN]",
+					Description: "Removes ARP/NDP neighbor entry with netlink.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 4 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveNeighbor(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "flush-neighbors",
+					UsageText:   "flush-neighbors [LINK] [permanent]",
+					Description: "Flushes learned ARP/NDP neighbor entries of link. With permanent static entries are flushed too.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkFlushNeighbors(c.Args().First(), c.Args().Get(1) == "permanent", c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-neighbors",
+					UsageText:   "show-neighbors dev [LINK] family {ipv4|ipv6} proxy",
+					Description: "Shows ARP/NDP neighbor entries.",
+
+					Action: func(c *cli.Context) error {
+						acquireNeighbors(c.Args(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "add-netns",
+					UsageText:   "add-netns [NAME]",
+					Description: "Creates named network namespace under /run/netns.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkAddNamespace(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-netns",
+					UsageText:   "remove-netns [NAME]",
+					Description: "Removes named network namespace from /run/netns.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveNamespace(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-netns",
+					Description: "Shows named network namespaces.",
+
+					Action: func(c *cli.Context) error {
+						acquireNamespaces(c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "diag",
+					Description: "Runs connectivity diagnostics.",
+					Subcommands: []*cli.Command{
+						{
+							Name:        "ping",
+							UsageText:   "ping [ADDRESS] count [NUMBER] interval [MSEC] timeout [SEC] size [NUMBER] dev [LINK] family {ipv4|ipv6}",
+							Description: "Sends ICMP/ICMPv6 echo requests.",
+
+							Action: func(c *cli.Context) error {
+								if c.NArg() < 1 {
+									fmt.Printf("Too few arguments.\n")
+									return nil
+								}
+
+								networkDiagPing(c.Args(), c.String("url"), token)
+								return nil
+							},
+						},
+						{
+							Name:        "traceroute",
+							UsageText:   "traceroute [ADDRESS] protocol {udp|icmp} max-hops [NUMBER] probes [NUMBER] timeout [SEC] dev [LINK] family {ipv4|ipv6}",
+							Description: "Traces the route packets take to the address.",
+
+							Action: func(c *cli.Context) error {
+								if c.NArg() < 1 {
+									fmt.Printf("Too few arguments.\n")
+									return nil
+								}
+
+								networkDiagTraceroute(c.Args(), c.String("url"), token)
+								return nil
+							},
+						},
+						{
+							Name:        "lookup",
+							UsageText:   "lookup [NAME] type {A|AAAA|MX|TXT|SRV|...} dev [LINK] family {ipv4|ipv6}",
+							Description: "Resolves name or records through systemd-resolved.",
+
+							Action: func(c *cli.Context) error {
+								if c.NArg() < 1 {
+									fmt.Printf("Too few arguments.\n")
+									return nil
+								}
 
-						networkConfigureDHCPv4UseOption(c.Args(), c.String("url"), token)
-						return nil
-					},
-				},
-				{
-					Name:        "set-dhcpv6",
-					UsageText:   "set-dhcpv6 dev [LINK] mudurl [STRING] userclass [STRING] vendorclass [STRING] prefixhint [STRING] withoutra [STRING]",
-					Description: "Configures Link DHCPv6 url, class, prefix hint and withoutRA.",
+								networkDiagLookup(c.Args(), c.String("url"), token)
+								return nil
+							},
+						},
+						{
+							Name:        "probe",
+							UsageText:   "probe [ADDRESS] port [PORT] timeout [SEC] dev [LINK] family {ipv4|ipv6}",
+							Description: "Checks whether a TCP connection to the port can be established.",
 
-					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
-							fmt.Printf("Too few arguments.\n")
-							return nil
-						}
+							Action: func(c *cli.Context) error {
+								if c.NArg() < 3 {
+									fmt.Printf("Too few arguments.\n")
+									return nil
+								}
 
-						networkConfigureDHCPv6(c.Args(), c.String("url"), token)
-						return nil
+								networkDiagPortProbe(c.Args(), c.String("url"), token)
+								return nil
+							},
+						},
 					},
 				},
 				{
-					Name:        "set-dhcpv6-id",
-					UsageText:   "set-dhcpv6-id dev [LINK] iaid [STRING] duidtype [STRING] duidrawdata [STRING]",
-					Description: "Configures Link DHCPv6 identifiers.",
+					Name:        "show-bridge-vlan",
+					Description: "Show bridge VLAN table.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
-							fmt.Printf("Too few arguments.\n")
-							return nil
-						}
-
-						networkConfigureDHCPv6Id(c.Args(), c.String("url"), token)
+						acquireBridgeVLAN(c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-dhcpv6-use",
-					UsageText:   "set-dhcpv6-use dev [LINK] useaddr [BOOLEAN] useprefix [BOOLEAN] usedns [BOOLEAN] usentp [BOOLEAN] usehostname [BOOLEAN] usedomains [BOOLEAN]",
-					Description: "Configures Link DHCPv6 enable the use of Address, Prefix, DNS, NTP, DOMAINS etc",
+					Name:        "create-vlan",
+					UsageText:   "create-vlan [VLAN name] dev [LINK MASTER] id [ID INTEGER]",
+					Description: "Create vlan.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
+						if c.NArg() < 5 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureDHCPv6Use(c.Args(), c.String("url"), token)
+						networkCreateVLan(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-dhcpv6-option",
-					UsageText:   "set-dhcpv6-option dev [LINK] reqopt [STRING] sendopt [STRING] sendvendoropt [STRING]",
-					Description: "Configures Link DHCPv6 Options",
+					Name:        "create-bond",
+					UsageText:   "create-bond [BOND name] dev [LINK MASTER] mode [STRING] thp [STRING] ltr [string] mms [STRING]",
+					Description: "Create bond.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureDHCPv6Option(c.Args(), c.String("url"), token)
+						networkCreateBond(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "add-dhcpv4-server",
-					UsageText:   "add-dhcpv4-server dev [LINK] pool-offset [NUMBER] pool-size [NUMBER] default-lease-time-sec [NUMBER] max-lease-time-sec [NUMBER] dns [STRING] emit-dns [BOOLEAN] emit-ntp [BOOLEAN] emit-router [BOOLEAN]",
-					Description: "Confifure the DHCPv4 Server",
+					Name:        "create-bridge",
+					UsageText:   "create-bridge [BRIDGE name] dev [LINK MASTER]",
+					Description: "Create bridge.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureAddDHCPv4Server(c.Args(), c.String("url"), token)
+						networkCreateBridge(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "remove-dhcpv4-server",
-					UsageText:   "remove-dhcpv4-server [LINK]",
-					Description: "Remove the DHCPv4 Server",
+					Name:        "create-macvlan",
+					UsageText:   "create-macvlan [MACVLAN name] dev [LINK MASTER] mode [STRING]",
+					Description: "Create macvlan.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 1 {
+						if c.NArg() < 5 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureRemoveDHCPv4Server(c.Args().First(), c.String("url"), token)
+						networkCreateMacVLan(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "add-ipv6ra",
-					UsageText:   "add-ipv6ra dev [LINK] rt-pref [STRING] emit-dns [STRING] dns [STRING] emit-domains [STRING] domains [STRING] dns-lifetime-sec [INTEGER] prefix [STRING] pref-lifetime-sec [INTEGER] valid-lifetime-sec [INTEGER] assign [STRING] route [STRING] lifetime-sec [INTEGER]",
-					Description: "Configure IPv6 Router Advertisement (RA) send ona link for the interface.",
+					Name:        "create-ipvlan",
+					UsageText:   "create-ipvlan [IPVLAN name] dev [LINK MASTER] mode [STRING] flags [STRING]",
+					Description: "Create ipvlan.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 2 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureIPv6SendRA(c.Args(), c.String("url"), token)
+						networkCreateIpVLan(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "remove-ipv6ra",
-					UsageText:   "remove-ipv6ra [LINK]",
-					Description: "Remove IPV6SendRA, IPv6Prefix and IPv6RoutePrefix",
+					Name:        "create-vxlan",
+					UsageText:   "create-vxlan [VXLAN name] dev [LINK MASTER] vni [INTEGER] remote [STRING] local [STRING] group [STRING] destport [INTEGER] independent [BOOLEAN]",
+					Description: "Create vxlan.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 1 {
+						if c.NArg() < 5 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureRemoveIPv6SendRA(c.Args().First(), c.String("url"), token)
+						networkCreateVxLan(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-mtu",
-					UsageText:   "set-mtu [LINK] [MTU NUMBER]",
-					Description: "Configures Link MTU.",
+					Name:        "create-wg",
+					UsageText:   "create-wg [WIREGUARD name] dev [LINK MASTER] skey [STRING] port [string] pkey [STRING] psk [STRING|BOOLEAN] ips [string] endpoint [STRING] keepalive [NUMBER]",
+					Description: "Create wg(wireguard). A private key is generated when skey is not given.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 2 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						if !validator.IsUint32(c.Args().Get(1)) {
-							fmt.Printf("MTU must be a valid value.\n")
-							return nil
-						}
-
-						networkConfigureMTU(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						networkCreateWireGuard(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-mac",
-					UsageText:   "set-mac [LINK] [MAC]",
-					Description: "Configures Link MAC address.",
+					Name:        "add-wg-peer",
+					UsageText:   "add-wg-peer [WIREGUARD name] pkey [STRING] psk [STRING|BOOLEAN] ips [string] endpoint [STRING] keepalive [NUMBER]",
+					Description: "Add or replace a wg(wireguard) peer.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 2 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						if validator.IsNotMAC(c.Args().Get(1)) {
-							fmt.Printf("Invalid MAC address: %v\n", c.Args().Get(1))
-							return nil
-						}
-
-						networkConfigureMAC(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						networkAddWireGuardPeer(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-group",
-					UsageText:   "set-group [LINK] [GROUP INTEGER]",
-					Description: "Configures Link Group.",
+					Name:        "remove-wg-peer",
+					UsageText:   "remove-wg-peer [WIREGUARD name] pkey [STRING]",
+					Description: "Remove a wg(wireguard) peer.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 2 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureLinkGroup(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						networkRemoveWireGuardPeer(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-rf-online",
-					UsageText:   "set-rf-online [LINK] [FAMILY STRING]",
-					Description: "Configures Link RequiredFamilyForOnline.",
+					Name:        "show-wg",
+					UsageText:   "show-wg [WIREGUARD name]",
+					Description: "Show wg(wireguard) status and peers.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 2 {
+						if c.NArg() < 1 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureLinkRequiredFamilyForOnline(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						acquireWireGuardStatus(c.Args().First(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-active-policy",
-					UsageText:   "set-active-policy [LINK] [POLICY STRING]",
-					Description: "Configures Link ActivationPolicy.",
+					Name:        "create-tun",
+					UsageText:   "create-tun [Tun name] dev [LINK MASTER] mq [STRING] pktinfo [STRING] vnet-hdr [string] usr [string] grp [STRING] kc [STRING]",
+					Description: "Create tun(Tun).",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 2 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureLinkActivationPolicy(c.Args().First(), c.Args().Get(1), c.String("url"), token)
+						networkCreateTunOrTap(c.Args(), c.String("tun"), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "set-link-mode",
-					UsageText:   "set-link-mode [LINK] mode [BOOLEAN] arp [BOOLEAN] mc [BOOLEAN] amc [BOOLEAN] pcs [BOOLEAN] rfo [BOOLEAN]",
-					Description: "Set Link mode,arp,multicast,allmulticast,promiscuous and requiredforonline.",
+					Name:        "create-tap",
+					UsageText:   "create-tap [Tap name] dev [LINK MASTER] mq [STRING] pktinfo [STRING] vnet-hdr [string] usr [string] grp [STRING] kc [STRING]",
+					Description: "Create tap(Tap).",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureMode(c.Args(), c.String("url"), token)
+						networkCreateTunOrTap(c.Args(), c.String("tap"), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "add-link-address",
-					UsageText:   "add-link-address [LINK] address [ADDRESS] peer [ADDRESS] label [NUMBER] scope {global|link|host|NUMBER}]",
-					Description: "Configures Link Address.",
+					Name:        "create-vrf",
+					UsageText:   "create-vrf [VRF name] dev [LINK MASTER] table [NUMBER]",
+					Description: "Create vrf.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 2 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkConfigureAddress(c.Args().First(), c.Args(), c.String("url"), token)
+						networkCreateVRF(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "add-sriov",
-					UsageText:   "add-sriov dev [LINK MASTER] vf [NUMBER] vlanid [NUMBER] qos [NUMBER] vlanproto [STRING] macsfc [BOOLEAN] qrss [BOOLEAN] trust [BOOLEAN] linkstate [STRING] macaddr [ADDRESS]",
-					Description: "Add SR-IOV.",
+					Name:        "create-veth",
+					UsageText:   "create-veth [VETH name] peer [PEER name] peermac [ADDRESS]",
+					Description: "Create veth pair.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkAddSRIOV(c.Args(), c.String("url"), token)
+						networkCreateVeth(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "add-rule",
-					UsageText:   "add-rule dev [LINK MASTER] tos [NUMBER] from [ADDRESS] to [ADDRESS] fwmark [STRING] table [STRING] prio [NUMBER] iif [STRING] oif [STRING] srcport [STRING] destport [STRING] ipproto [STRING] invertrule [STRING] family [STRING] usr [STRING] suppressprefixlen [NUMBER] suppressifgrp [NUMBER] type [STRING]",
-					Description: "Add RoutingPolicyRule.",
+					Name:        "create-dummy",
+					UsageText:   "create-dummy [DUMMY name]",
+					Description: "Create dummy.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
+						if c.NArg() < 1 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkAddRoutingPolicyRule(c.Args(), c.String("url"), token)
+						networkCreateDummy(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "delete-rule",
-					UsageText:   "delete-rule dev [LINK MASTER] tos [NUMBER] from [ADDRESS] to [ADDRESS] fwmark [STRING] table [STRING] prio [NUMBER] iif [STRING] oif [STRING] srcport [STRING] destport [STRING] ipproto [STRING] invertrule [STRING] family [STRING] usr [STRING] suppressprefixlen [NUMBER] suppressifgrp [NUMBER] type [STRING]",
-					Description: "Delete RoutingPolicyRule.",
+					Name:        "create-geneve",
+					UsageText:   "create-geneve [GENEVE name] id [NUMBER] remote [ADDRESS] tos [NUMBER] ttl [NUMBER] udpcsum [BOOLEAN] destport [PORT] flowlabel [NUMBER] df [STRING]",
+					Description: "Create geneve.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 4 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkRemoveRoutingPolicyRule(c.Args(), c.String("url"), token)
+						networkCreateGeneve(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-vlan",
-					UsageText:   "create-vlan [VLAN name] dev [LINK MASTER] id [ID INTEGER]",
-					Description: "Create vlan.",
+					Name:        "create-macsec",
+					UsageText:   "create-macsec [MACSEC name] dev [LINK MASTER] port [NUMBER] encrypt [BOOLEAN] txpn [NUMBER] txkeyid [NUMBER] txkey [HEX] txkeyfile [PATH] txactivate [BOOLEAN] encoding [BOOLEAN] rxport [NUMBER] rxmac [ADDRESS] rxpn [NUMBER] rxkeyid [NUMBER] rxkey [HEX] rxkeyfile [PATH] rxactivate [BOOLEAN]",
+					Description: "Create macsec.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 5 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkCreateVLan(c.Args(), c.String("url"), token)
+						networkCreateMACsec(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-bond",
-					UsageText:   "create-bond [BOND name] dev [LINK MASTER] mode [STRING] thp [STRING] ltr [string] mms [STRING]",
-					Description: "Create bond.",
+					Name:        "create-ipip",
+					UsageText:   "create-ipip [IPIP name] dev [LINK MASTER] local [ADDRESS] remote [ADDRESS] tos [NUMBER] ttl [NUMBER] key [KEY] ikey [KEY] okey [KEY] pmtudisc [BOOLEAN] independent [BOOLEAN] loopback [BOOLEAN]",
+					Description: "Create ipip tunnel.",
 
 					Action: func(c *cli.Context) error {
 						if c.NArg() < 3 {
@@ -957,14 +1927,14 @@ func main() {
 							return nil
 						}
 
-						networkCreateBond(c.Args(), c.String("url"), token)
+						networkCreateTunnel(c.Args(), "ipip", c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-bridge",
-					UsageText:   "create-bridge [BRIDGE name] dev [LINK MASTER]",
-					Description: "Create bridge.",
+					Name:        "create-gre",
+					UsageText:   "create-gre [GRE name] dev [LINK MASTER] local [ADDRESS] remote [ADDRESS] tos [NUMBER] ttl [NUMBER] key [KEY] ikey [KEY] okey [KEY] pmtudisc [BOOLEAN] independent [BOOLEAN] loopback [BOOLEAN]",
+					Description: "Create gre tunnel.",
 
 					Action: func(c *cli.Context) error {
 						if c.NArg() < 3 {
@@ -972,29 +1942,29 @@ func main() {
 							return nil
 						}
 
-						networkCreateBridge(c.Args(), c.String("url"), token)
+						networkCreateTunnel(c.Args(), "gre", c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-macvlan",
-					UsageText:   "create-macvlan [MACVLAN name] dev [LINK MASTER] mode [STRING]",
-					Description: "Create macvlan.",
+					Name:        "create-gretap",
+					UsageText:   "create-gretap [GRETAP name] dev [LINK MASTER] local [ADDRESS] remote [ADDRESS] tos [NUMBER] ttl [NUMBER] key [KEY] ikey [KEY] okey [KEY] pmtudisc [BOOLEAN] independent [BOOLEAN] loopback [BOOLEAN]",
+					Description: "Create gretap tunnel.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 5 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkCreateMacVLan(c.Args(), c.String("url"), token)
+						networkCreateTunnel(c.Args(), "gretap", c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-ipvlan",
-					UsageText:   "create-ipvlan [IPVLAN name] dev [LINK MASTER] mode [STRING] flags [STRING]",
-					Description: "Create ipvlan.",
+					Name:        "create-ip6gre",
+					UsageText:   "create-ip6gre [IP6GRE name] dev [LINK MASTER] local [ADDRESS] remote [ADDRESS] tos [NUMBER] ttl [NUMBER] key [KEY] ikey [KEY] okey [KEY] pmtudisc [BOOLEAN] independent [BOOLEAN] loopback [BOOLEAN]",
+					Description: "Create ip6gre tunnel.",
 
 					Action: func(c *cli.Context) error {
 						if c.NArg() < 3 {
@@ -1002,59 +1972,59 @@ func main() {
 							return nil
 						}
 
-						networkCreateIpVLan(c.Args(), c.String("url"), token)
+						networkCreateTunnel(c.Args(), "ip6gre", c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-vxlan",
-					UsageText:   "create-vxlan [VXLAN name] dev [LINK MASTER] vni [INTEGER] remote [STRING] local [STRING] group [STRING] destport [INTEGER] independent [BOOLEAN]",
-					Description: "Create vxlan.",
+					Name:        "create-sit",
+					UsageText:   "create-sit [SIT name] dev [LINK MASTER] local [ADDRESS] remote [ADDRESS] tos [NUMBER] ttl [NUMBER] key [KEY] ikey [KEY] okey [KEY] pmtudisc [BOOLEAN] independent [BOOLEAN] loopback [BOOLEAN]",
+					Description: "Create sit tunnel.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 5 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkCreateVxLan(c.Args(), c.String("url"), token)
+						networkCreateTunnel(c.Args(), "sit", c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-wg",
-					UsageText:   "create-wg [WIREGUARD name] dev [LINK MASTER] skey [STRING] pkey [STRING] port [string] ips [string] endpoint [STRING]",
-					Description: "Create wg(wireguard).",
+					Name:        "create-vti",
+					UsageText:   "create-vti [VTI name] dev [LINK MASTER] local [ADDRESS] remote [ADDRESS] tos [NUMBER] ttl [NUMBER] key [KEY] ikey [KEY] okey [KEY] pmtudisc [BOOLEAN] independent [BOOLEAN] loopback [BOOLEAN]",
+					Description: "Create vti tunnel.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 9 {
+						if c.NArg() < 3 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkCreateWireGuard(c.Args(), c.String("url"), token)
+						networkCreateTunnel(c.Args(), "vti", c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-tun",
-					UsageText:   "create-tun [Tun name] dev [LINK MASTER] mq [STRING] pktinfo [STRING] vnet-hdr [string] usr [string] grp [STRING] kc [STRING]",
-					Description: "Create tun(Tun).",
+					Name:        "show-netdev",
+					UsageText:   "show-netdev [NETDEV name]",
+					Description: "Show netdev configuration and live state.",
 
 					Action: func(c *cli.Context) error {
-						if c.NArg() < 3 {
+						if c.NArg() < 1 {
 							fmt.Printf("Too few arguments.\n")
 							return nil
 						}
 
-						networkCreateTunOrTap(c.Args(), c.String("tun"), c.String("url"), token)
+						acquireNetDev(c.Args().First(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
-					Name:        "create-tap",
-					UsageText:   "create-tap [Tap name] dev [LINK MASTER] mq [STRING] pktinfo [STRING] vnet-hdr [string] usr [string] grp [STRING] kc [STRING]",
-					Description: "Create tap(Tap).",
+					Name:        "set-netdev",
+					UsageText:   "set-netdev [NETDEV name] add-dev [LINK,..] remove-dev [LINK,..] desc [STRING] mtu [NUMBER] thp [STRING] ltr [STRING] mms [STRING] stp [BOOLEAN]",
+					Description: "Update an existing netdev in place and attach or detach its links.",
 
 					Action: func(c *cli.Context) error {
 						if c.NArg() < 3 {
@@ -1062,13 +2032,13 @@ func main() {
 							return nil
 						}
 
-						networkCreateTunOrTap(c.Args(), c.String("tap"), c.String("url"), token)
+						networkPatchNetDev(c.Args(), c.String("url"), token)
 						return nil
 					},
 				},
 				{
 					Name:        "remove-netdev",
-					UsageText:   "remove-netdev [NETDEV name] kind [KIND {vlan|bridge|bond|vxlan|macvlan|macvtap|ipvlan|ipvtap|vrf|veth|ipip|sit|vti|gre|wg|tun]",
+					UsageText:   "remove-netdev [NETDEV name] kind [KIND {vlan|bridge|bond|vxlan|macvlan|macvtap|ipvlan|ipvtap|vrf|veth|dummy|geneve|macsec|ipip|sit|vti|gre|gretap|ip6gre|wg|tun]",
 					Description: "Removes .netdev and .network files.",
 
 					Action: func(c *cli.Context) error {
@@ -1428,6 +2398,46 @@ func main() {
 						return nil
 					},
 				},
+				{
+					Name:        "show-link-files",
+					UsageText:   "show-link-files",
+					Description: "Show all .link files and the links udev applied them to.",
+
+					Action: func(c *cli.Context) error {
+						acquireLinkFiles(c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "show-link-config",
+					UsageText:   "show-link-config [LINK]",
+					Description: "Show the .link file udev applied to the link.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						acquireLinkConfig(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
+				{
+					Name:        "remove-link-config",
+					UsageText:   "remove-link-config [LINK]",
+					Description: "Remove the .link file of the link.",
+
+					Action: func(c *cli.Context) error {
+						if c.NArg() < 1 {
+							fmt.Printf("Too few arguments.\n")
+							return nil
+						}
+
+						networkRemoveLinkConfig(c.Args().First(), c.String("url"), token)
+						return nil
+					},
+				},
 				{
 					Name:        "set-link",
 					UsageText:   "set-link dev [LINK] alias,desc... [string]",
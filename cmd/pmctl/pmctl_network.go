@@ -51,6 +51,18 @@ type ResolveDescribe struct {
 	Errors  string            `json:"errors"`
 }
 
+type DHCPLeaseStats struct {
+	Success bool               `json:"success"`
+	Message networkd.DHCPLease `json:"message"`
+	Errors  string             `json:"errors"`
+}
+
+type DHCPServerLeasesStats struct {
+	Success bool                      `json:"success"`
+	Message networkd.DHCPServerLeases `json:"message"`
+	Errors  string                    `json:"errors"`
+}
+
 func displayInterfaces(i *Interface) {
 	for _, n := range i.Message {
 		fmt.Printf("            %v %v\n", color.HiBlueString("Name:"), n.Name)
@@ -150,6 +162,11 @@ func displayOneLinkRoutes(ifIndex int, linkRoutes []route.RouteInfo) {
 		if rt.LinkIndex == ifIndex && rt.Gw != "" {
 			gws.Add(rt.Gw)
 		}
+		for _, nh := range rt.NextHops {
+			if nh.LinkIndex == ifIndex && nh.Gw != "" {
+				gws.Add(nh.Gw)
+			}
+		}
 	}
 
 	if gws.Length() > 0 {
@@ -356,16 +373,31 @@ func acquireNetworkStatus(cmd string, host string, ifName string, token map[stri
 	}
 }
 
+// Set from the network command's --wait flags
+var networkWait networkd.WaitSection
+
+type LinkWaitStats struct {
+	Success bool                   `json:"success"`
+	Message networkd.LinkWaitState `json:"message"`
+	Errors  string                 `json:"errors"`
+}
+
 func networkConfigure(network *networkd.Network, host string, token map[string]string) {
 	var resp []byte
 	var err error
 
+	network.Wait = networkWait
 	resp, err = web.DispatchSocket(http.MethodPost, host, "/api/v1/network/networkd/network/configure", token, *network)
 	if err != nil {
 		fmt.Printf("Failed to configure network: %v\n", err)
 		return
 	}
 
+	if network.Wait != (networkd.WaitSection{}) {
+		displayLinkWaitState(resp)
+		return
+	}
+
 	m := web.JSONResponseMessage{}
 	if err := json.Unmarshal(resp, &m); err != nil {
 		fmt.Printf("Failed to decode json message: %v\n", err)
@@ -377,6 +409,31 @@ func networkConfigure(network *networkd.Network, host string, token map[string]s
 	}
 }
 
+func displayLinkWaitState(resp []byte) {
+	m := LinkWaitStats{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure network: %v\n", m.Errors)
+		return
+	}
+
+	s := m.Message
+	fmt.Printf("%v %v\n", color.HiBlueString("             Link:"), s.Link)
+	fmt.Printf("%v %v\n", color.HiBlueString("      Setup State:"), s.SetupState)
+	fmt.Printf("%v %v\n", color.HiBlueString("Operational State:"), s.OperationalState)
+	fmt.Printf("%v %v\n", color.HiBlueString("    Address State:"), s.AddressState)
+
+	if s.Reverted {
+		fmt.Printf("Link did not come up, configuration reverted\n")
+	} else if !s.Reached {
+		fmt.Printf("Link did not come up in time\n")
+	}
+}
+
 func networkConfigureDHCP(link string, dhcp string, host string, token map[string]string) {
 	n := networkd.Network{
 		Link: link,
@@ -471,6 +528,73 @@ func networkConfigureRoute(args cli.Args, host string, token map[string]string)
 				return
 			}
 			r.Scope = argStrings[i+1]
+		case "metric":
+			if !validator.IsUint32(argStrings[i+1]) {
+				fmt.Printf("Failed to parse metric='%s'\n", argStrings[i+1])
+				return
+			}
+			r.Metric = argStrings[i+1]
+		case "proto":
+			if !validator.IsRouteProtocol(argStrings[i+1]) {
+				fmt.Printf("Failed to parse proto='%s'\n", argStrings[i+1])
+				return
+			}
+			r.Protocol = argStrings[i+1]
+		case "type":
+			if !validator.IsRouteType(argStrings[i+1]) {
+				fmt.Printf("Failed to parse type='%s'\n", argStrings[i+1])
+				return
+			}
+			r.Type = argStrings[i+1]
+		case "ipv6pref":
+			if !validator.IsRouteIPv6Preference(argStrings[i+1]) {
+				fmt.Printf("Failed to parse ipv6pref='%s'\n", argStrings[i+1])
+				return
+			}
+			r.IPv6Preference = argStrings[i+1]
+		case "mtu":
+			if !validator.IsLinkMtu(argStrings[i+1]) {
+				fmt.Printf("Failed to parse mtu='%s'\n", argStrings[i+1])
+				return
+			}
+			r.MTUBytes = argStrings[i+1]
+		case "initcwnd":
+			if !validator.IsUint32(argStrings[i+1]) {
+				fmt.Printf("Failed to parse initcwnd='%s'\n", argStrings[i+1])
+				return
+			}
+			r.InitialCongestionWindow = argStrings[i+1]
+		case "initrwnd":
+			if !validator.IsUint32(argStrings[i+1]) {
+				fmt.Printf("Failed to parse initrwnd='%s'\n", argStrings[i+1])
+				return
+			}
+			r.InitialAdvertisedReceiveWindow = argStrings[i+1]
+		case "quickack":
+			if !validator.IsBool(argStrings[i+1]) {
+				fmt.Printf("Failed to parse quickack='%s'\n", argStrings[i+1])
+				return
+			}
+			r.QuickAck = validator.BoolToString(argStrings[i+1])
+		case "fastopennocookie":
+			if !validator.IsBool(argStrings[i+1]) {
+				fmt.Printf("Failed to parse fastopennocookie='%s'\n", argStrings[i+1])
+				return
+			}
+			r.FastOpenNoCookie = validator.BoolToString(argStrings[i+1])
+		case "multipath":
+			// Repeat for every next hop, "10.0.0.1@eth0 10"
+			if !validator.IsRouteMultiPath(argStrings[i+1]) {
+				fmt.Printf("Failed to parse multipath='%s'\n", argStrings[i+1])
+				return
+			}
+			r.MultiPathRoute = append(r.MultiPathRoute, argStrings[i+1])
+		case "nexthop":
+			if !validator.IsNextHopId(argStrings[i+1]) {
+				fmt.Printf("Failed to parse nexthop='%s'\n", argStrings[i+1])
+				return
+			}
+			r.NextHop = argStrings[i+1]
 		}
 	}
 
@@ -672,6 +796,51 @@ func networkConfigureAddDHCPv4Server(args cli.Args, host string, token map[strin
 				return
 			}
 			n.DHCPv4ServerSection.EmitRouter = validator.BoolToString(argStrings[i+1])
+		case "server-address":
+			if !validator.IsIP(argStrings[i+1]) {
+				fmt.Printf("Invalid server-address=%s\n", argStrings[i+1])
+				return
+			}
+			n.DHCPv4ServerSection.ServerAddress = argStrings[i+1]
+		case "uplink":
+			n.DHCPv4ServerSection.UplinkInterface = argStrings[i+1]
+		case "ntp":
+			ntplist := strings.Split(argStrings[i+1], ",")
+			for _, ntp := range ntplist {
+				if !validator.IsIP(ntp) {
+					fmt.Printf("Invalid ntp=%s\n", ntp)
+					return
+				}
+			}
+			n.DHCPv4ServerSection.NTP = ntplist
+		case "emit-timezone":
+			if !validator.IsBool(argStrings[i+1]) {
+				fmt.Printf("Invalid emit-timezone=%s\n", argStrings[i+1])
+				return
+			}
+			n.DHCPv4ServerSection.EmitTimezone = validator.BoolToString(argStrings[i+1])
+		case "timezone":
+			n.DHCPv4ServerSection.Timezone = argStrings[i+1]
+		case "boot-server-address":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				fmt.Printf("Invalid boot-server-address=%s\n", argStrings[i+1])
+				return
+			}
+			n.DHCPv4ServerSection.BootServerAddress = argStrings[i+1]
+		case "boot-server-name":
+			n.DHCPv4ServerSection.BootServerName = argStrings[i+1]
+		case "boot-filename":
+			n.DHCPv4ServerSection.BootFilename = argStrings[i+1]
+		case "relay-target":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				fmt.Printf("Invalid relay-target=%s\n", argStrings[i+1])
+				return
+			}
+			n.DHCPv4ServerSection.RelayTarget = argStrings[i+1]
+		case "relay-circuit-id":
+			n.DHCPv4ServerSection.RelayAgentCircuitId = argStrings[i+1]
+		case "relay-remote-id":
+			n.DHCPv4ServerSection.RelayAgentRemoteId = argStrings[i+1]
 		}
 	}
 
@@ -707,6 +876,125 @@ func networkConfigureRemoveDHCPv4Server(link string, host string, token map[stri
 	}
 }
 
+func parseDHCPServerStaticLease(args cli.Args) (*networkd.Network, error) {
+	argStrings := args.Slice()
+
+	n := networkd.Network{}
+	l := networkd.DHCPServerStaticLeaseSection{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Link = argStrings[i+1]
+		case "mac":
+			if validator.IsNotMAC(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid mac=%s\n", argStrings[i+1])
+			}
+			l.MACAddress = argStrings[i+1]
+		case "address":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid address=%s\n", argStrings[i+1])
+			}
+			l.Address = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(n.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+	if validator.IsEmpty(l.MACAddress) {
+		return nil, fmt.Errorf("Missing mac\n")
+	}
+
+	n.DHCPServerStaticLeases = []networkd.DHCPServerStaticLeaseSection{l}
+	return &n, nil
+}
+
+func networkAddDHCPServerStaticLease(args cli.Args, host string, token map[string]string) {
+	n, err := parseDHCPServerStaticLease(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	networkConfigure(n, host, token)
+}
+
+func networkRemoveDHCPServerStaticLease(args cli.Args, host string, token map[string]string) {
+	n, err := parseDHCPServerStaticLease(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(http.MethodDelete, host, "/api/v1/network/networkd/network/remove", token, n)
+	if err != nil {
+		fmt.Printf("Failed to remove dhcp server static lease: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to remove dhcp server static lease: %v\n", m.Errors)
+	}
+}
+
+func acquireDHCPServerLeases(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/networkd/"+link+"/dhcpserver/leases", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire dhcp server leases: %v\n", err)
+		return
+	}
+
+	l := DHCPServerLeasesStats{}
+	if err := json.Unmarshal(resp, &l); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !l.Success {
+		fmt.Printf("Failed to acquire dhcp server leases: %v\n", l.Errors)
+		return
+	}
+
+	for _, d := range l.Message.Leases {
+		fmt.Printf("%v %v", color.HiBlueString("Address:"), d.Address)
+		fmt.Printf(" %v %v", color.HiBlueString("MACAddress:"), d.MACAddress)
+		if !validator.IsEmpty(d.ClientId) {
+			fmt.Printf(" %v %v", color.HiBlueString("ClientId:"), d.ClientId)
+		}
+		if !validator.IsEmpty(d.Expiration) {
+			fmt.Printf(" %v %v", color.HiBlueString("Expires:"), d.Expiration)
+		}
+		if d.Static {
+			fmt.Printf(" Static")
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func networkResetDropin(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodDelete, host, "/api/v1/network/networkd/network/"+link+"/dropin", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to reset network drop-in: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to reset network drop-in: %v\n", m.Errors)
+	}
+}
+
 func networkConfigureMTU(link string, mtu string, host string, token map[string]string) {
 	n := networkd.Network{
 		Link: link,
@@ -853,6 +1141,48 @@ func networkConfigureAddress(link string, args cli.Args, host string, token map[
 				fmt.Printf("Invalid scope: %s", a.Scope)
 				return
 			}
+		case "broadcast":
+			a.Broadcast = argStrings[i+1]
+			if !validator.IsBool(a.Broadcast) && !validator.IsValidIP(a.Broadcast) {
+				fmt.Printf("Invalid broadcast: %s\n", a.Broadcast)
+				return
+			}
+		case "preferred-lifetime":
+			a.PreferredLifetime = argStrings[i+1]
+			if !validator.IsAddressPreferredLifetime(a.PreferredLifetime) {
+				fmt.Printf("Invalid preferred-lifetime: %s\n", a.PreferredLifetime)
+				return
+			}
+		case "dad":
+			a.DuplicateAddressDetection = argStrings[i+1]
+			if !validator.IsAddressDuplicateAddressDetection(a.DuplicateAddressDetection) {
+				fmt.Printf("Invalid dad: %s\n", a.DuplicateAddressDetection)
+				return
+			}
+		case "home-address", "manage-temp-address", "prefix-route", "auto-join":
+			if !validator.IsBool(argStrings[i+1]) {
+				fmt.Printf("Invalid %s: %s\n", argStrings[i], argStrings[i+1])
+				return
+			}
+			v := validator.BoolToString(argStrings[i+1])
+			switch argStrings[i] {
+			case "home-address":
+				a.HomeAddress = v
+			case "manage-temp-address":
+				a.ManageTemporaryAddress = v
+			case "prefix-route":
+				a.AddPrefixRoute = v
+			case "auto-join":
+				a.AutoJoin = v
+			}
+		case "route-metric":
+			a.RouteMetric = argStrings[i+1]
+			if !validator.IsUint32(a.RouteMetric) {
+				fmt.Printf("Invalid route-metric: %s\n", a.RouteMetric)
+				return
+			}
+		case "netlabel":
+			a.NetLabel = argStrings[i+1]
 		default:
 		}
 		i++
@@ -1724,3 +2054,91 @@ func networkConfigureDHCPv6Option(args cli.Args, host string, token map[string]s
 	// Dispatch Request.
 	networkConfigure(&n, host, token)
 }
+
+func acquireDHCPLease(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/networkd/"+link+"/lease", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire DHCP lease: %v\n", err)
+		return
+	}
+
+	l := DHCPLeaseStats{}
+	if err := json.Unmarshal(resp, &l); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !l.Success {
+		fmt.Printf("Failed to acquire DHCP lease: %v\n", l.Errors)
+		return
+	}
+
+	fmt.Printf("%v %v\n", color.HiBlueString("              Link:"), l.Message.Link)
+	if validator.IsEmpty(l.Message.Address) && len(l.Message.DHCPv6Prefixes) == 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("             Lease:"), "none")
+		return
+	}
+	if !validator.IsEmpty(l.Message.Address) {
+		fmt.Printf("%v %v\n", color.HiBlueString("           Address:"), l.Message.Address)
+		fmt.Printf("%v %v\n", color.HiBlueString("           Netmask:"), l.Message.Netmask)
+	}
+	if len(l.Message.Router) > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("            Router:"), strings.Join(l.Message.Router, " "))
+	}
+	if !validator.IsEmpty(l.Message.ServerAddress) {
+		fmt.Printf("%v %v\n", color.HiBlueString("         Server Id:"), l.Message.ServerAddress)
+	}
+	if len(l.Message.DNS) > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("               DNS:"), strings.Join(l.Message.DNS, " "))
+	}
+	if len(l.Message.NTP) > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("               NTP:"), strings.Join(l.Message.NTP, " "))
+	}
+	if !validator.IsEmpty(l.Message.DomainName) {
+		fmt.Printf("%v %v\n", color.HiBlueString("       Domain Name:"), l.Message.DomainName)
+	}
+	if len(l.Message.DomainSearchList) > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("Domain Search List:"), strings.Join(l.Message.DomainSearchList, " "))
+	}
+	if !validator.IsEmpty(l.Message.Hostname) {
+		fmt.Printf("%v %v\n", color.HiBlueString("          Hostname:"), l.Message.Hostname)
+	}
+	if !validator.IsEmpty(l.Message.MTU) {
+		fmt.Printf("%v %v\n", color.HiBlueString("               MTU:"), l.Message.MTU)
+	}
+	if l.Message.LifetimeSec > 0 {
+		fmt.Printf("%v %vs\n", color.HiBlueString("          Lifetime:"), l.Message.LifetimeSec)
+	}
+	if l.Message.T1Sec > 0 {
+		fmt.Printf("%v %vs\n", color.HiBlueString("                T1:"), l.Message.T1Sec)
+	}
+	if l.Message.T2Sec > 0 {
+		fmt.Printf("%v %vs\n", color.HiBlueString("                T2:"), l.Message.T2Sec)
+	}
+	for _, p := range l.Message.DHCPv6Prefixes {
+		fmt.Printf("%v %v (preferred %vs valid %vs)\n", color.HiBlueString("    DHCPv6 Prefix:"), p.Prefix, p.PreferredLifetimeUSec/1000000, p.ValidLifetimeUSec/1000000)
+	}
+}
+
+func networkRenewDHCPLease(link string, force bool, host string, token map[string]string) {
+	action := "renew"
+	if force {
+		action = "force-renew"
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, host, "/api/v1/network/networkd/"+link+"/lease/"+action, token, nil)
+	if err != nil {
+		fmt.Printf("Failed to renew DHCP lease: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to renew DHCP lease: %v\n", m.Errors)
+	}
+}
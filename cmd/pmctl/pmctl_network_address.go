@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/address"
+)
+
+type LinkAddressStats struct {
+	Success bool                `json:"success"`
+	Message address.AddressInfo `json:"message"`
+	Errors  string              `json:"errors"`
+}
+
+func parseLifetime(s string) (int, error) {
+	if s == "forever" || s == "infinity" {
+		return 0, nil
+	}
+
+	return validator.IsInt(s)
+}
+
+func parseRuntimeAddress(args cli.Args) (*address.AddressAction, error) {
+	argStrings := args.Slice()
+
+	a := address.AddressAction{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			a.Link = argStrings[i+1]
+		case "address":
+			if !validator.IsIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid address=%s\n", argStrings[i+1])
+			}
+			a.Address.IP = argStrings[i+1]
+		case "peer":
+			if !validator.IsIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid peer=%s\n", argStrings[i+1])
+			}
+			a.Address.Peer = argStrings[i+1]
+		case "broadcast":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid broadcast=%s\n", argStrings[i+1])
+			}
+			a.Address.Broadcast = argStrings[i+1]
+		case "label":
+			a.Address.Label = argStrings[i+1]
+		case "scope":
+			a.Address.ScopeName = argStrings[i+1]
+		case "preferred-lft":
+			lft, err := parseLifetime(argStrings[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid preferred-lft=%s\n", argStrings[i+1])
+			}
+			a.Address.PreferedLft = lft
+		case "valid-lft":
+			lft, err := parseLifetime(argStrings[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid valid-lft=%s\n", argStrings[i+1])
+			}
+			a.Address.ValidLft = lft
+		case "flags":
+			a.Address.FlagNames = strings.Split(argStrings[i+1], ",")
+		}
+	}
+
+	if validator.IsEmpty(a.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+	if validator.IsEmpty(a.Address.IP) {
+		return nil, fmt.Errorf("Missing address\n")
+	}
+
+	return &a, nil
+}
+
+func networkRuntimeAddress(method string, args cli.Args, host string, token map[string]string) {
+	a, err := parseRuntimeAddress(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(method, host, netnsPath("/api/v1/network/netlink/address/"+a.Link), token, a)
+	if err != nil {
+		fmt.Printf("Failed to configure address: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure address: %v\n", m.Errors)
+	}
+}
+
+func networkAddRuntimeAddress(args cli.Args, host string, token map[string]string) {
+	networkRuntimeAddress(http.MethodPost, args, host, token)
+}
+
+func networkRemoveRuntimeAddress(args cli.Args, host string, token map[string]string) {
+	networkRuntimeAddress(http.MethodDelete, args, host, token)
+}
+
+func acquireLinkAddresses(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath("/api/v1/network/netlink/address/"+link), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire addresses: %v\n", err)
+		return
+	}
+
+	a := LinkAddressStats{}
+	if err := json.Unmarshal(resp, &a); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !a.Success {
+		fmt.Printf("Failed to acquire addresses: %v\n", a.Errors)
+		return
+	}
+
+	for _, addr := range a.Message.Addresses {
+		fmt.Printf("%v %v/%v", color.HiBlueString("Address:"), addr.IP, addr.Mask)
+		if !validator.IsEmpty(addr.Peer) {
+			fmt.Printf(" %v %v", color.HiBlueString("Peer:"), addr.Peer)
+		}
+		if !validator.IsEmpty(addr.Broadcast) {
+			fmt.Printf(" %v %v", color.HiBlueString("Broadcast:"), addr.Broadcast)
+		}
+		if !validator.IsEmpty(addr.Label) {
+			fmt.Printf(" %v %v", color.HiBlueString("Label:"), addr.Label)
+		}
+		fmt.Printf(" %v %v", color.HiBlueString("Scope:"), addr.ScopeName)
+		if len(addr.FlagNames) > 0 {
+			fmt.Printf(" %v %v", color.HiBlueString("Flags:"), strings.Join(addr.FlagNames, " "))
+		}
+		fmt.Printf("\n")
+	}
+}
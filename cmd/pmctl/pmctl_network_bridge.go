@@ -0,0 +1,320 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/bridge"
+	"github.com/vmware/pmd-next-gen/plugins/network/networkd"
+)
+
+type BridgeFDBStats struct {
+	Success bool              `json:"success"`
+	Message []bridge.FDBEntry `json:"message"`
+	Errors  string            `json:"errors"`
+}
+
+type BridgeVLANStats struct {
+	Success bool               `json:"success"`
+	Message []bridge.PortVLANs `json:"message"`
+	Errors  string             `json:"errors"`
+}
+
+func parseBridgePort(args cli.Args) (*networkd.Network, error) {
+	argStrings := args.Slice()
+	n := networkd.Network{}
+
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Link = argStrings[i+1]
+		case "bridge":
+			n.NetworkSection.Bridge = argStrings[i+1]
+		case "cost":
+			if !validator.IsBridgePortCost(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid cost=%s\n", argStrings[i+1])
+			}
+			n.BridgePortSection.Cost = argStrings[i+1]
+		case "prio":
+			if !validator.IsBridgePortPriority(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid prio=%s\n", argStrings[i+1])
+			}
+			n.BridgePortSection.Priority = argStrings[i+1]
+		case "hairpin":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid hairpin=%s\n", argStrings[i+1])
+			}
+			n.BridgePortSection.HairPin = validator.BoolToString(argStrings[i+1])
+		case "isolated":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid isolated=%s\n", argStrings[i+1])
+			}
+			n.BridgePortSection.Isolated = validator.BoolToString(argStrings[i+1])
+		case "usebpdu":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid usebpdu=%s\n", argStrings[i+1])
+			}
+			n.BridgePortSection.UseBPDU = validator.BoolToString(argStrings[i+1])
+		case "learning":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid learning=%s\n", argStrings[i+1])
+			}
+			n.BridgePortSection.Learning = validator.BoolToString(argStrings[i+1])
+		}
+	}
+
+	if validator.IsEmpty(n.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+
+	return &n, nil
+}
+
+func parseBridgeVLAN(args cli.Args) (*networkd.Network, error) {
+	argStrings := args.Slice()
+	link := ""
+
+	v := networkd.BridgeVLANSection{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			link = argStrings[i+1]
+		case "vlan":
+			if !validator.IsVLANIdRange(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid vlan=%s\n", argStrings[i+1])
+			}
+			v.VLAN = argStrings[i+1]
+		case "pvid":
+			if !validator.IsVLANId(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid pvid=%s\n", argStrings[i+1])
+			}
+			v.PVID = argStrings[i+1]
+		case "untagged":
+			if !validator.IsVLANIdRange(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid untagged=%s\n", argStrings[i+1])
+			}
+			v.EgressUntagged = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+
+	n := networkd.Network{
+		Link: link,
+		BridgeVLANSections: []networkd.BridgeVLANSection{
+			v,
+		},
+	}
+
+	return &n, nil
+}
+
+func networkAddBridgePort(args cli.Args, host string, token map[string]string) {
+	n, err := parseBridgePort(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	networkConfigure(n, host, token)
+}
+
+func networkAddBridgeVLAN(args cli.Args, host string, token map[string]string) {
+	n, err := parseBridgeVLAN(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	networkConfigure(n, host, token)
+}
+
+func networkRemoveBridgeVLAN(args cli.Args, host string, token map[string]string) {
+	n, err := parseBridgeVLAN(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(http.MethodDelete, host, "/api/v1/network/networkd/network/remove", token, n)
+	if err != nil {
+		fmt.Printf("Failed to remove bridge vlan: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to remove bridge vlan: %v\n", m.Errors)
+	}
+}
+
+func parseBridgeFDB(args cli.Args) (*bridge.FDBEntry, error) {
+	argStrings := args.Slice()
+
+	e := bridge.FDBEntry{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			e.Link = argStrings[i+1]
+		case "mac":
+			if validator.IsNotMAC(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid mac=%s\n", argStrings[i+1])
+			}
+			e.MACAddress = argStrings[i+1]
+		case "vlan":
+			vlan, err := strconv.Atoi(argStrings[i+1])
+			if err != nil || !validator.IsVLANId(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid vlan=%s\n", argStrings[i+1])
+			}
+			e.VLAN = vlan
+		case "dst":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid dst=%s\n", argStrings[i+1])
+			}
+			e.Destination = argStrings[i+1]
+		case "vni":
+			vni, err := strconv.Atoi(argStrings[i+1])
+			if err != nil || vni < 1 || vni > 16777215 {
+				return nil, fmt.Errorf("Invalid vni=%s\n", argStrings[i+1])
+			}
+			e.VNI = vni
+		case "state":
+			e.State = argStrings[i+1]
+		case "flags":
+			e.Flags = strings.Split(argStrings[i+1], ",")
+		}
+	}
+
+	if validator.IsEmpty(e.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+	if validator.IsEmpty(e.MACAddress) {
+		return nil, fmt.Errorf("Missing mac\n")
+	}
+
+	return &e, nil
+}
+
+func networkBridgeFDB(method string, args cli.Args, host string, token map[string]string) {
+	e, err := parseBridgeFDB(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(method, host, netnsPath("/api/v1/network/netlink/bridge/fdb"), token, e)
+	if err != nil {
+		fmt.Printf("Failed to configure bridge fdb: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure bridge fdb: %v\n", m.Errors)
+	}
+}
+
+func networkAddBridgeFDB(args cli.Args, host string, token map[string]string) {
+	networkBridgeFDB(http.MethodPost, args, host, token)
+}
+
+func networkRemoveBridgeFDB(args cli.Args, host string, token map[string]string) {
+	networkBridgeFDB(http.MethodDelete, args, host, token)
+}
+
+func acquireBridgeFDB(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath("/api/v1/network/netlink/bridge/fdb"), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire bridge fdb: %v\n", err)
+		return
+	}
+
+	b := BridgeFDBStats{}
+	if err := json.Unmarshal(resp, &b); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !b.Success {
+		fmt.Printf("Failed to acquire bridge fdb: %v\n", b.Errors)
+		return
+	}
+
+	for _, f := range b.Message {
+		fmt.Printf("%v %v", color.HiBlueString("MACAddress:"), f.MACAddress)
+		fmt.Printf(" %v %v", color.HiBlueString("Link:"), f.Link)
+		if f.VLAN > 0 {
+			fmt.Printf(" %v %v", color.HiBlueString("VLAN:"), f.VLAN)
+		}
+		if !validator.IsEmpty(f.Master) {
+			fmt.Printf(" %v %v", color.HiBlueString("Master:"), f.Master)
+		}
+		if !validator.IsEmpty(f.Destination) {
+			fmt.Printf(" %v %v", color.HiBlueString("Destination:"), f.Destination)
+		}
+		if f.VNI > 0 {
+			fmt.Printf(" %v %v", color.HiBlueString("VNI:"), f.VNI)
+		}
+		fmt.Printf(" %v %v", color.HiBlueString("State:"), f.State)
+		if len(f.Flags) > 0 {
+			fmt.Printf(" %v %v", color.HiBlueString("Flags:"), strings.Join(f.Flags, " "))
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func acquireBridgeVLAN(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath("/api/v1/network/netlink/bridge/vlan"), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire bridge vlan: %v\n", err)
+		return
+	}
+
+	b := BridgeVLANStats{}
+	if err := json.Unmarshal(resp, &b); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !b.Success {
+		fmt.Printf("Failed to acquire bridge vlan: %v\n", b.Errors)
+		return
+	}
+
+	for _, p := range b.Message {
+		fmt.Printf("%v %v\n", color.HiBlueString("Link:"), p.Link)
+		for _, v := range p.VLANs {
+			fmt.Printf("     %v %v", color.HiBlueString("VLAN:"), v.Id)
+			if v.PVID {
+				fmt.Printf(" PVID")
+			}
+			if v.EgressUntagged {
+				fmt.Printf(" Egress Untagged")
+			}
+			fmt.Printf("\n")
+		}
+	}
+}
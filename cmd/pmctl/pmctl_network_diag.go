@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/diag"
+)
+
+type PingStats struct {
+	Success bool                `json:"success"`
+	Message diag.PingStatistics `json:"message"`
+	Errors  string              `json:"errors"`
+}
+
+type TracerouteStats struct {
+	Success bool                  `json:"success"`
+	Message diag.TracerouteResult `json:"message"`
+	Errors  string                `json:"errors"`
+}
+
+type LookupStats struct {
+	Success bool              `json:"success"`
+	Message diag.LookupResult `json:"message"`
+	Errors  string            `json:"errors"`
+}
+
+type PortProbeStats struct {
+	Success bool                 `json:"success"`
+	Message diag.PortProbeResult `json:"message"`
+	Errors  string               `json:"errors"`
+}
+
+// parseDiagArgs splits "[TARGET] key value ..." and checks the keys against
+// the ones the command knows
+func parseDiagArgs(args cli.Args, keys ...string) (string, map[string]string, error) {
+	argStrings := args.Slice()
+
+	known := map[string]bool{}
+	for _, k := range keys {
+		known[k] = true
+	}
+
+	m := map[string]string{}
+	for i := 1; i < len(argStrings); i++ {
+		if !known[argStrings[i]] {
+			return "", nil, fmt.Errorf("Unknown option %s\n", argStrings[i])
+		}
+		if i+1 >= len(argStrings) {
+			return "", nil, fmt.Errorf("Missing value of %s\n", argStrings[i])
+		}
+
+		m[argStrings[i]] = argStrings[i+1]
+		i++
+	}
+
+	return argStrings[0], m, nil
+}
+
+func parseDiagUint(m map[string]string, key string) (uint, error) {
+	v, ok := m[key]
+	if !ok {
+		return 0, nil
+	}
+
+	if !validator.IsUint32(v) {
+		return 0, fmt.Errorf("Invalid %s=%s\n", key, v)
+	}
+
+	n, _ := strconv.ParseUint(v, 10, 32)
+	return uint(n), nil
+}
+
+func parsePing(args cli.Args) (*diag.Ping, error) {
+	address, m, err := parseDiagArgs(args, "count", "interval", "timeout", "size", "dev", "family")
+	if err != nil {
+		return nil, err
+	}
+
+	p := diag.Ping{
+		Address: address,
+		Link:    m["dev"],
+		Family:  m["family"],
+	}
+	if p.Count, err = parseDiagUint(m, "count"); err != nil {
+		return nil, err
+	}
+	if p.IntervalMSec, err = parseDiagUint(m, "interval"); err != nil {
+		return nil, err
+	}
+	if p.TimeoutSec, err = parseDiagUint(m, "timeout"); err != nil {
+		return nil, err
+	}
+	if p.Size, err = parseDiagUint(m, "size"); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func parseTraceroute(args cli.Args) (*diag.Traceroute, error) {
+	address, m, err := parseDiagArgs(args, "protocol", "max-hops", "probes", "timeout", "dev", "family")
+	if err != nil {
+		return nil, err
+	}
+
+	t := diag.Traceroute{
+		Address:  address,
+		Protocol: m["protocol"],
+		Link:     m["dev"],
+		Family:   m["family"],
+	}
+	if t.MaxHops, err = parseDiagUint(m, "max-hops"); err != nil {
+		return nil, err
+	}
+	if t.Probes, err = parseDiagUint(m, "probes"); err != nil {
+		return nil, err
+	}
+	if t.TimeoutSec, err = parseDiagUint(m, "timeout"); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func parseLookup(args cli.Args) (*diag.Lookup, error) {
+	name, m, err := parseDiagArgs(args, "type", "dev", "family")
+	if err != nil {
+		return nil, err
+	}
+
+	return &diag.Lookup{
+		Name:   name,
+		Type:   m["type"],
+		Link:   m["dev"],
+		Family: m["family"],
+	}, nil
+}
+
+func parsePortProbe(args cli.Args) (*diag.PortProbe, error) {
+	address, m, err := parseDiagArgs(args, "port", "timeout", "dev", "family")
+	if err != nil {
+		return nil, err
+	}
+
+	if !validator.IsPort(m["port"]) {
+		return nil, fmt.Errorf("Invalid port=%s\n", m["port"])
+	}
+
+	p := diag.PortProbe{
+		Address: address,
+		Link:    m["dev"],
+		Family:  m["family"],
+	}
+	if p.Port, err = parseDiagUint(m, "port"); err != nil {
+		return nil, err
+	}
+	if p.TimeoutSec, err = parseDiagUint(m, "timeout"); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func networkDiagPing(args cli.Args, host string, token map[string]string) {
+	p, err := parsePing(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchAndWait(http.MethodPost, host, "/api/v1/network/diag/ping", token, p)
+	if err != nil {
+		fmt.Printf("Failed to ping: %v\n", err)
+		return
+	}
+
+	s := PingStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to ping: %v\n", s.Errors)
+		return
+	}
+
+	for _, r := range s.Message.Replies {
+		fmt.Printf("%v %v %v %v %v %v %v %.3f ms\n", color.HiBlueString("From:"), r.From, color.HiBlueString("Seq:"), r.Seq,
+			color.HiBlueString("TTL:"), r.TTL, color.HiBlueString("Time:"), r.RTTMSec)
+	}
+
+	fmt.Printf("%v %v (%v)\n", color.HiBlueString("Address:"), s.Message.Address, s.Message.IP)
+	fmt.Printf("%v %v %v %v %v %.1f%%\n", color.HiBlueString("Transmitted:"), s.Message.Transmitted,
+		color.HiBlueString("Received:"), s.Message.Received, color.HiBlueString("Packet Loss:"), s.Message.PacketLoss)
+	if s.Message.Received > 0 {
+		fmt.Printf("%v %.3f/%.3f/%.3f ms\n", color.HiBlueString("RTT min/avg/max:"), s.Message.MinRTTMSec, s.Message.AvgRTTMSec, s.Message.MaxRTTMSec)
+	}
+}
+
+func networkDiagTraceroute(args cli.Args, host string, token map[string]string) {
+	t, err := parseTraceroute(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchAndWait(http.MethodPost, host, "/api/v1/network/diag/traceroute", token, t)
+	if err != nil {
+		fmt.Printf("Failed to traceroute: %v\n", err)
+		return
+	}
+
+	s := TracerouteStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to traceroute: %v\n", s.Errors)
+		return
+	}
+
+	fmt.Printf("%v %v (%v) %v %v\n", color.HiBlueString("Address:"), s.Message.Address, s.Message.IP, color.HiBlueString("Protocol:"), s.Message.Protocol)
+	for _, h := range s.Message.Hops {
+		fmt.Printf("%2d ", h.TTL)
+
+		last := ""
+		for _, p := range h.Probes {
+			if validator.IsEmpty(p.Address) {
+				fmt.Printf(" *")
+				continue
+			}
+			if p.Address != last {
+				fmt.Printf(" %v", p.Address)
+				last = p.Address
+			}
+			fmt.Printf(" %.3f ms", p.RTTMSec)
+		}
+		fmt.Printf("\n")
+	}
+
+	if !s.Message.Reached {
+		fmt.Printf("%v\n", color.HiRedString("Destination not reached"))
+	}
+}
+
+func networkDiagLookup(args cli.Args, host string, token map[string]string) {
+	l, err := parseLookup(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchAndWait(http.MethodPost, host, "/api/v1/network/diag/lookup", token, l)
+	if err != nil {
+		fmt.Printf("Failed to lookup: %v\n", err)
+		return
+	}
+
+	s := LookupStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to lookup: %v\n", s.Errors)
+		return
+	}
+
+	if !validator.IsEmpty(s.Message.Canonical) && s.Message.Canonical != s.Message.Name {
+		fmt.Printf("%v %v\n", color.HiBlueString("Canonical:"), s.Message.Canonical)
+	}
+	for _, a := range s.Message.Addresses {
+		fmt.Printf("%v %v %v %v\n", color.HiBlueString("Address:"), a.Address, color.HiBlueString("Link:"), a.Link)
+	}
+	for _, r := range s.Message.Records {
+		fmt.Printf("%v %v IN %v %v", r.Name, r.TTL, r.Type, r.Data)
+		if !validator.IsEmpty(r.Link) {
+			fmt.Printf(" %v %v", color.HiBlueString("Link:"), r.Link)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func networkDiagPortProbe(args cli.Args, host string, token map[string]string) {
+	p, err := parsePortProbe(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchAndWait(http.MethodPost, host, "/api/v1/network/diag/probe", token, p)
+	if err != nil {
+		fmt.Printf("Failed to probe port: %v\n", err)
+		return
+	}
+
+	s := PortProbeStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to probe port: %v\n", s.Errors)
+		return
+	}
+
+	fmt.Printf("%v %v (%v) %v %v ", color.HiBlueString("Address:"), s.Message.Address, s.Message.IP, color.HiBlueString("Port:"), s.Message.Port)
+	if s.Message.Open {
+		fmt.Printf("%v %.3f ms\n", color.HiGreenString("open"), s.Message.RTTMSec)
+	} else {
+		fmt.Printf("%v %v\n", color.HiRedString("closed"), s.Message.Error)
+	}
+}
@@ -10,12 +10,25 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/vmware/pmd-next-gen/pkg/validator"
 	"github.com/vmware/pmd-next-gen/pkg/web"
 	"github.com/vmware/pmd-next-gen/plugins/network/networkd"
 	"github.com/urfave/cli/v2"
 )
 
+type LinkFilesStats struct {
+	Success bool                        `json:"success"`
+	Message []networkd.LinkFileDescribe `json:"message"`
+	Errors  string                      `json:"errors"`
+}
+
+type LinkConfigStats struct {
+	Success bool                        `json:"success"`
+	Message networkd.LinkConfigDescribe `json:"message"`
+	Errors  string                      `json:"errors"`
+}
+
 func dispatchNetworkLinkConfigReq(l networkd.Link, host string, token map[string]string) {
 	if validator.IsEmpty(l.Link) {
 		fmt.Printf("Failed to set link. Missing link name")
@@ -771,3 +784,113 @@ func networkConfigureLink(args cli.Args, host string, token map[string]string) {
 	// Dispatch Request.
 	dispatchNetworkLinkConfigReq(l, host, token)
 }
+
+func displayLinkConfig(l *networkd.Link) {
+	if !validator.IsEmpty(l.MatchSection.MACAddress) {
+		fmt.Printf("%v %v\n", color.HiBlueString("         Match MAC:"), l.MatchSection.MACAddress)
+	}
+	if !validator.IsEmpty(l.MatchSection.Path) {
+		fmt.Printf("%v %v\n", color.HiBlueString("        Match Path:"), l.MatchSection.Path)
+	}
+	if !validator.IsEmpty(l.MatchSection.Driver) {
+		fmt.Printf("%v %v\n", color.HiBlueString("      Match Driver:"), l.MatchSection.Driver)
+	}
+	if !validator.IsEmpty(l.MatchSection.Name) {
+		fmt.Printf("%v %v\n", color.HiBlueString("        Match Name:"), l.MatchSection.Name)
+	}
+	if len(l.NamePolicy) > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("       Name Policy:"), strings.Join(l.NamePolicy, " "))
+	}
+	if !validator.IsEmpty(l.Name) {
+		fmt.Printf("%v %v\n", color.HiBlueString("              Name:"), l.Name)
+	}
+	if !validator.IsEmpty(l.MACAddressPolicy) {
+		fmt.Printf("%v %v\n", color.HiBlueString("MAC Address Policy:"), l.MACAddressPolicy)
+	}
+	if !validator.IsEmpty(l.MTUBytes) {
+		fmt.Printf("%v %v\n", color.HiBlueString("               MTU:"), l.MTUBytes)
+	}
+	if !validator.IsEmpty(l.GenericSegmentationOffload) {
+		fmt.Printf("%v %v\n", color.HiBlueString("               GSO:"), l.GenericSegmentationOffload)
+	}
+	if !validator.IsEmpty(l.GenericReceiveOffload) {
+		fmt.Printf("%v %v\n", color.HiBlueString("               GRO:"), l.GenericReceiveOffload)
+	}
+	if !validator.IsEmpty(l.TCPSegmentationOffload) {
+		fmt.Printf("%v %v\n", color.HiBlueString("               TSO:"), l.TCPSegmentationOffload)
+	}
+	if !validator.IsEmpty(l.CombinedChannels) {
+		fmt.Printf("%v %v\n", color.HiBlueString(" Combined Channels:"), l.CombinedChannels)
+	}
+}
+
+func acquireLinkFiles(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/networkd/link", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire link files: %v\n", err)
+		return
+	}
+
+	l := LinkFilesStats{}
+	if err := json.Unmarshal(resp, &l); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !l.Success {
+		fmt.Printf("Failed to acquire link files: %v\n", l.Errors)
+		return
+	}
+
+	for _, f := range l.Message {
+		fmt.Printf("%v %v\n", color.HiBlueString("              File:"), f.Path)
+		if len(f.AppliedTo) > 0 {
+			fmt.Printf("%v %v\n", color.HiBlueString("        Applied To:"), strings.Join(f.AppliedTo, " "))
+		}
+		displayLinkConfig(f.Config)
+		fmt.Printf("\n")
+	}
+}
+
+func acquireLinkConfig(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/networkd/link/"+link, token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire link config: %v\n", err)
+		return
+	}
+
+	l := LinkConfigStats{}
+	if err := json.Unmarshal(resp, &l); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !l.Success {
+		fmt.Printf("Failed to acquire link config: %v\n", l.Errors)
+		return
+	}
+
+	fmt.Printf("%v %v\n", color.HiBlueString("              Link:"), l.Message.Name)
+	fmt.Printf("%v %v\n", color.HiBlueString("         Link File:"), l.Message.LinkFile)
+	if l.Message.Config != nil {
+		displayLinkConfig(l.Message.Config)
+	}
+}
+
+func networkRemoveLinkConfig(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodDelete, host, "/api/v1/network/networkd/link/"+link, token, nil)
+	if err != nil {
+		fmt.Printf("Failed to remove link config: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to remove link config: %v\n", m.Errors)
+	}
+}
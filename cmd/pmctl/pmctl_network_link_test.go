@@ -162,6 +162,34 @@ func TestLinkName(t *testing.T) {
 	}
 }
 
+func TestLinkMatchOriginalName(t *testing.T) {
+	setupLink(t, &netlink.Dummy{netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	l := networkd.Link{
+		Link: "test99",
+		MatchSection: networkd.MatchSection{
+			Name: "test99",
+		},
+		Alias: "ifalias",
+	}
+
+	m, err := configureLink(t, l)
+	if err != nil {
+		t.Fatalf("Failed to configure Link: %v\n", err)
+	}
+
+	if m.GetKeySectionString("Match", "OriginalName") != "test99" {
+		t.Fatalf("Failed to set OriginalName")
+	}
+	if m.GetKeySectionString("Match", "Name") != "" {
+		t.Fatalf("Name must not be set in .link file")
+	}
+	if m.GetKeySectionString("Match", "MACAddress") == "" {
+		t.Fatalf("Failed to keep MACAddress")
+	}
+}
+
 func TestLinkAltName(t *testing.T) {
 	setupLink(t, &netlink.Dummy{netlink.LinkAttrs{Name: "test99"}})
 	defer removeLink(t, "test99")
@@ -577,3 +605,67 @@ func TestLinkPacketRate(t *testing.T) {
 		t.Fatalf("Failed to set CoalescePacketRateSampleIntervalSec")
 	}
 }
+
+func TestLinkFilesAcquireAndRemove(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	l := networkd.Link{
+		Link:  "test99",
+		Alias: "ifalias",
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/networkd/link/configure", nil, l)
+	if err != nil {
+		t.Fatalf("Failed to configure Link: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to configure Link: %v\n", j.Errors)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/networkd/link", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire link files: %v\n", err)
+	}
+
+	f := LinkFilesStats{}
+	if err := json.Unmarshal(resp, &f); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !f.Success {
+		t.Fatalf("Failed to acquire link files: %v\n", f.Errors)
+	}
+
+	found := false
+	for _, d := range f.Message {
+		if d.Path == "/etc/systemd/network/10-test99.link" && d.Config.Alias == "ifalias" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Failed to find .link file of test99")
+	}
+
+	resp, err = web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/networkd/link/test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to remove link config: %v\n", err)
+	}
+
+	j = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to remove link config: %v\n", j.Errors)
+	}
+
+	if system.PathExists("/etc/systemd/network/10-test99.link") {
+		os.Remove("/etc/systemd/network/10-test99.link")
+		t.Fatalf("Failed to remove .link file of test99")
+	}
+}
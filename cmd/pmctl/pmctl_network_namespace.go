@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
+)
+
+// Set from the network command's --netns flag
+var networkNetNs string
+
+type NamespaceStats struct {
+	Success bool                  `json:"success"`
+	Message []namespace.Namespace `json:"message"`
+	Errors  string                `json:"errors"`
+}
+
+// netnsPath points a netlink request at the namespace given with --netns
+func netnsPath(path string) string {
+	if validator.IsEmpty(networkNetNs) {
+		return path
+	}
+
+	if strings.Contains(path, "?") {
+		return path + "&netns=" + url.QueryEscape(networkNetNs)
+	}
+
+	return path + "?netns=" + url.QueryEscape(networkNetNs)
+}
+
+func networkNamespace(method string, name string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(method, host, "/api/v1/network/netns/"+name, token, nil)
+	if err != nil {
+		fmt.Printf("Failed to configure network namespace: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure network namespace: %v\n", m.Errors)
+	}
+}
+
+func networkAddNamespace(name string, host string, token map[string]string) {
+	networkNamespace(http.MethodPost, name, host, token)
+}
+
+func networkRemoveNamespace(name string, host string, token map[string]string) {
+	networkNamespace(http.MethodDelete, name, host, token)
+}
+
+func acquireNamespaces(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/netns", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire network namespaces: %v\n", err)
+		return
+	}
+
+	n := NamespaceStats{}
+	if err := json.Unmarshal(resp, &n); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !n.Success {
+		fmt.Printf("Failed to acquire network namespaces: %v\n", n.Errors)
+		return
+	}
+
+	for _, ns := range n.Message {
+		fmt.Printf("%v", ns.Name)
+		if ns.ID >= 0 {
+			fmt.Printf(" %v %v", color.HiBlueString("id:"), ns.ID)
+		}
+		fmt.Printf("\n")
+	}
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/neighbor"
+)
+
+type NeighborStats struct {
+	Success bool                `json:"success"`
+	Message []neighbor.Neighbor `json:"message"`
+	Errors  string              `json:"errors"`
+}
+
+func parseNeighbor(args cli.Args) (*neighbor.Neighbor, error) {
+	argStrings := args.Slice()
+
+	n := neighbor.Neighbor{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Link = argStrings[i+1]
+		case "address":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid address=%s\n", argStrings[i+1])
+			}
+			n.IP = argStrings[i+1]
+		case "mac":
+			if validator.IsNotMAC(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid mac=%s\n", argStrings[i+1])
+			}
+			n.MACAddress = argStrings[i+1]
+		case "state":
+			n.State = argStrings[i+1]
+		case "router", "proxy":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid %s=%s\n", argStrings[i], argStrings[i+1])
+			}
+			b := validator.BoolToString(argStrings[i+1]) == "yes"
+			if argStrings[i] == "router" {
+				n.Router = b
+			} else {
+				n.Proxy = b
+			}
+		}
+	}
+
+	if validator.IsEmpty(n.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+	if validator.IsEmpty(n.IP) {
+		return nil, fmt.Errorf("Missing address\n")
+	}
+
+	return &n, nil
+}
+
+func networkNeighbor(method string, args cli.Args, host string, token map[string]string) {
+	n, err := parseNeighbor(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(method, host, netnsPath("/api/v1/network/netlink/neighbor"), token, n)
+	if err != nil {
+		fmt.Printf("Failed to configure neighbor: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure neighbor: %v\n", m.Errors)
+	}
+}
+
+func networkAddNeighbor(args cli.Args, host string, token map[string]string) {
+	networkNeighbor(http.MethodPost, args, host, token)
+}
+
+func networkRemoveNeighbor(args cli.Args, host string, token map[string]string) {
+	networkNeighbor(http.MethodDelete, args, host, token)
+}
+
+func networkFlushNeighbors(link string, permanent bool, host string, token map[string]string) {
+	path := "/api/v1/network/netlink/neighbor/" + link + "/flush"
+	if permanent {
+		path += "?permanent=true"
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, host, netnsPath(path), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to flush neighbors: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to flush neighbors: %v\n", m.Errors)
+	}
+}
+
+func acquireNeighbors(args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+
+	q := url.Values{}
+	for i := 0; i < len(argStrings); i++ {
+		switch argStrings[i] {
+		case "dev":
+			if i+1 < len(argStrings) {
+				q.Set("link", argStrings[i+1])
+			}
+		case "family":
+			if i+1 < len(argStrings) {
+				q.Set("family", argStrings[i+1])
+			}
+		case "proxy":
+			q.Set("proxy", "true")
+		}
+	}
+
+	path := "/api/v1/network/netlink/neighbor"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath(path), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire neighbors: %v\n", err)
+		return
+	}
+
+	n := NeighborStats{}
+	if err := json.Unmarshal(resp, &n); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !n.Success {
+		fmt.Printf("Failed to acquire neighbors: %v\n", n.Errors)
+		return
+	}
+
+	for _, nb := range n.Message {
+		fmt.Printf("%v %v %v %v", color.HiBlueString("Address:"), nb.IP, color.HiBlueString("Link:"), nb.Link)
+		if !validator.IsEmpty(nb.MACAddress) {
+			fmt.Printf(" %v %v", color.HiBlueString("MACAddress:"), nb.MACAddress)
+		}
+		if nb.Router {
+			fmt.Printf(" router")
+		}
+		if nb.Proxy {
+			fmt.Printf(" proxy")
+		} else {
+			fmt.Printf(" %v %v", color.HiBlueString("State:"), nb.State)
+		}
+		fmt.Printf("\n")
+	}
+}
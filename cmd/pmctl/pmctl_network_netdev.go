@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/fatih/color"
 	"github.com/urfave/cli/v2"
 	"github.com/vmware/pmd-next-gen/pkg/validator"
 	"github.com/vmware/pmd-next-gen/pkg/web"
@@ -333,6 +334,60 @@ func networkCreateVxLan(args cli.Args, host string, token map[string]string) {
 	}
 }
 
+type NetDevStats struct {
+	Success bool                    `json:"success"`
+	Message networkd.NetDevDescribe `json:"message"`
+	Errors  string                  `json:"errors"`
+}
+
+type WireGuardStats struct {
+	Success bool                     `json:"success"`
+	Message networkd.WireGuardStatus `json:"message"`
+	Errors  string                   `json:"errors"`
+}
+
+func parseWireGuardPeer(argStrings []string) (*networkd.WireGuardPeer, error) {
+	p := networkd.WireGuardPeer{}
+
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "pkey":
+			if !validator.IsWireGuardKey(argStrings[i+1]) {
+				return nil, fmt.Errorf("Failed to parse public key: %s\n", argStrings[i+1])
+			}
+			p.PublicKey = argStrings[i+1]
+		case "psk":
+			if validator.IsBool(argStrings[i+1]) {
+				p.GeneratePresharedKey = validator.BoolToString(argStrings[i+1]) == "yes"
+			} else if validator.IsWireGuardKey(argStrings[i+1]) {
+				p.PresharedKey = argStrings[i+1]
+			} else {
+				return nil, fmt.Errorf("Failed to parse preshared key: %s\n", argStrings[i+1])
+			}
+		case "ips":
+			ips := strings.Split(argStrings[i+1], ",")
+			for _, ip := range ips {
+				if !validator.IsIP(ip) {
+					return nil, fmt.Errorf("Failed to parse allowed ips: %s\n", argStrings[i+1])
+				}
+			}
+			p.AllowedIPs = ips
+		case "endpoint":
+			if !validator.IsWireGuardPeerEndpoint(argStrings[i+1]) {
+				return nil, fmt.Errorf("Failed to parse endpoint: %s\n", argStrings[i+1])
+			}
+			p.Endpoint = argStrings[i+1]
+		case "keepalive":
+			if !validator.IsWireGuardPersistentKeepalive(argStrings[i+1]) {
+				return nil, fmt.Errorf("Failed to parse keepalive: %s\n", argStrings[i+1])
+			}
+			p.PersistentKeepalive = argStrings[i+1]
+		}
+	}
+
+	return &p, nil
+}
+
 func networkCreateWireGuard(args cli.Args, host string, token map[string]string) {
 	argStrings := args.Slice()
 	n := networkd.NetDev{
@@ -340,14 +395,12 @@ func networkCreateWireGuard(args cli.Args, host string, token map[string]string)
 		Kind: "wireguard",
 	}
 
-	for i := 1; i < len(argStrings); {
+	for i := 1; i < len(argStrings)-1; i++ {
 		switch argStrings[i] {
 		case "dev":
 			n.Links = strings.Fields(argStrings[i+1])
 		case "skey":
 			n.WireGuardSection.PrivateKey = argStrings[i+1]
-		case "pkey":
-			n.WireGuardPeerSection.PublicKey = argStrings[i+1]
 		case "port":
 			if validator.IsWireGuardListenPort(argStrings[i+1]) {
 				n.WireGuardSection.ListenPort = argStrings[i+1]
@@ -355,30 +408,20 @@ func networkCreateWireGuard(args cli.Args, host string, token map[string]string)
 				fmt.Printf("Failed to parse listen port: %s\n", argStrings[i+1])
 				return
 			}
-		case "ips":
-			ips := strings.Split(argStrings[i+1], ",")
-			for _, ip := range ips {
-				if !validator.IsIP(ip) {
-					fmt.Printf("Failed to parse allowed ips: %s\n", argStrings[i+1])
-					return
-				}
-			}
-			n.WireGuardPeerSection.AllowedIPs = ips
-		case "endpoint":
-			if validator.IsWireGuardPeerEndpoint(argStrings[i+1]) {
-				n.WireGuardPeerSection.Endpoint = argStrings[i+1]
-			} else {
-				fmt.Printf("Failed to parse endpoint: %s\n", argStrings[i+1])
-				return
-			}
 		}
+	}
 
-		i++
+	p, err := parseWireGuardPeer(argStrings[1:])
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+	if !validator.IsEmpty(p.PublicKey) {
+		n.WireGuardPeerSections = append(n.WireGuardPeerSections, *p)
 	}
 
-	if validator.IsArrayEmpty(n.Links) || validator.IsEmpty(n.Name) || validator.IsEmpty(n.WireGuardSection.PrivateKey) ||
-		validator.IsEmpty(n.WireGuardPeerSection.PublicKey) || validator.IsEmpty(n.WireGuardPeerSection.Endpoint) {
-		fmt.Printf("Failed to create WireGuard. Missing WireGuard name, skey, pkey or dev\n")
+	if validator.IsArrayEmpty(n.Links) || validator.IsEmpty(n.Name) {
+		fmt.Printf("Failed to create WireGuard. Missing WireGuard name or dev\n")
 		return
 	}
 
@@ -396,6 +439,112 @@ func networkCreateWireGuard(args cli.Args, host string, token map[string]string)
 
 	if !m.Success {
 		fmt.Printf("Failed to create WireGuard: %v\n", m.Errors)
+		return
+	}
+
+	if k, ok := m.Message.(map[string]interface{}); ok {
+		fmt.Printf("%v %v\n", color.HiBlueString("PublicKey:"), k["PublicKey"])
+		printWireGuardPresharedKeys(k["PresharedKeys"])
+	}
+}
+
+func printWireGuardPresharedKeys(keys interface{}) {
+	l, ok := keys.([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, k := range l {
+		if psk, ok := k.(map[string]interface{}); ok {
+			fmt.Printf("%v %v %v\n", color.HiBlueString("PresharedKey:"), psk["PresharedKey"], psk["PublicKey"])
+		}
+	}
+}
+
+func networkWireGuardPeer(method string, args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+
+	p, err := parseWireGuardPeer(argStrings[1:])
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	if validator.IsEmpty(p.PublicKey) {
+		fmt.Printf("Missing pkey\n")
+		return
+	}
+
+	resp, err := web.DispatchSocket(method, host, "/api/v1/network/networkd/netdev/"+argStrings[0]+"/wireguard/peer", token, p)
+	if err != nil {
+		fmt.Printf("Failed to configure WireGuard peer: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure WireGuard peer: %v\n", m.Errors)
+		return
+	}
+
+	if k, ok := m.Message.(map[string]interface{}); ok {
+		printWireGuardPresharedKeys([]interface{}{k})
+	}
+}
+
+func networkAddWireGuardPeer(args cli.Args, host string, token map[string]string) {
+	networkWireGuardPeer(http.MethodPost, args, host, token)
+}
+
+func networkRemoveWireGuardPeer(args cli.Args, host string, token map[string]string) {
+	networkWireGuardPeer(http.MethodDelete, args, host, token)
+}
+
+func acquireWireGuardStatus(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/networkd/netdev/"+link+"/wireguard", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire WireGuard status: %v\n", err)
+		return
+	}
+
+	s := WireGuardStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to acquire WireGuard status: %v\n", s.Errors)
+		return
+	}
+
+	fmt.Printf("%v %v\n", color.HiBlueString("         Name:"), s.Message.Name)
+	fmt.Printf("%v %v\n", color.HiBlueString("   Public Key:"), s.Message.PublicKey)
+	fmt.Printf("%v %v\n", color.HiBlueString("  Listen Port:"), s.Message.ListenPort)
+	if s.Message.FirewallMark > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("Firewall Mark:"), s.Message.FirewallMark)
+	}
+
+	for _, p := range s.Message.Peers {
+		fmt.Printf("\n%v %v\n", color.HiBlueString("         Peer:"), p.PublicKey)
+		if !validator.IsEmpty(p.Endpoint) {
+			fmt.Printf("%v %v\n", color.HiBlueString("     Endpoint:"), p.Endpoint)
+		}
+		if len(p.AllowedIPs) > 0 {
+			fmt.Printf("%v %v\n", color.HiBlueString("  Allowed IPs:"), strings.Join(p.AllowedIPs, ", "))
+		}
+		if p.PersistentKeepalive > 0 {
+			fmt.Printf("%v %v\n", color.HiBlueString("    Keepalive:"), p.PersistentKeepalive)
+		}
+		if !p.LastHandshakeTime.IsZero() {
+			fmt.Printf("%v %v\n", color.HiBlueString("    Handshake:"), p.LastHandshakeTime)
+		}
+		fmt.Printf("%v %v received, %v sent\n", color.HiBlueString("     Transfer:"), p.RxBytes, p.TxBytes)
 	}
 }
 
@@ -485,3 +634,368 @@ func networkRemoveNetDev(args cli.Args, host string, token map[string]string) {
 		fmt.Printf("Failed to remove netdev %v\n", m.Errors)
 	}
 }
+
+func networkConfigureNetDev(n *networkd.NetDev, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodPost, host, "/api/v1/network/networkd/netdev/configure", token, *n)
+	if err != nil {
+		fmt.Printf("Failed to create %s: %v\n", n.Kind, err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to create %s: %v\n", n.Kind, m.Errors)
+	}
+}
+
+func networkCreateVRF(args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+	n := networkd.NetDev{
+		Name: argStrings[0],
+		Kind: "vrf",
+	}
+
+	for i := 1; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Links = strings.Split(argStrings[i+1], ",")
+		case "table":
+			if !validator.IsVRFTable(argStrings[i+1]) {
+				fmt.Printf("Invalid table: %s\n", argStrings[i+1])
+				return
+			}
+			n.VRFSection.Table = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(n.Name) || validator.IsEmpty(n.VRFSection.Table) {
+		fmt.Printf("Failed to create VRF. Missing VRF name or table\n")
+		return
+	}
+
+	networkConfigureNetDev(&n, host, token)
+}
+
+func networkCreateVeth(args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+	n := networkd.NetDev{
+		Name: argStrings[0],
+		Kind: "veth",
+	}
+
+	for i := 1; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "peer":
+			n.PeerSection.Name = argStrings[i+1]
+		case "peermac":
+			if validator.IsNotMAC(argStrings[i+1]) {
+				fmt.Printf("Invalid peermac: %s\n", argStrings[i+1])
+				return
+			}
+			n.PeerSection.MACAddress = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(n.Name) || validator.IsEmpty(n.PeerSection.Name) {
+		fmt.Printf("Failed to create Veth. Missing Veth name or peer\n")
+		return
+	}
+
+	networkConfigureNetDev(&n, host, token)
+}
+
+func networkCreateDummy(args cli.Args, host string, token map[string]string) {
+	n := networkd.NetDev{
+		Name: args.First(),
+		Kind: "dummy",
+	}
+
+	networkConfigureNetDev(&n, host, token)
+}
+
+func networkCreateGeneve(args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+	n := networkd.NetDev{
+		Name: argStrings[0],
+		Kind: "geneve",
+	}
+
+	for i := 1; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "id":
+			if !validator.IsGeneveId(argStrings[i+1]) {
+				fmt.Printf("Invalid id: %s\n", argStrings[i+1])
+				return
+			}
+			n.GeneveSection.Id = argStrings[i+1]
+		case "remote":
+			if !validator.IsIP(argStrings[i+1]) {
+				fmt.Printf("Invalid remote: %s\n", argStrings[i+1])
+				return
+			}
+			n.GeneveSection.Remote = argStrings[i+1]
+		case "tos":
+			n.GeneveSection.TOS = argStrings[i+1]
+		case "ttl":
+			n.GeneveSection.TTL = argStrings[i+1]
+		case "udpcsum":
+			n.GeneveSection.UDPChecksum = argStrings[i+1]
+		case "destport":
+			if !validator.IsPort(argStrings[i+1]) {
+				fmt.Printf("Invalid destport: %s\n", argStrings[i+1])
+				return
+			}
+			n.GeneveSection.DestinationPort = argStrings[i+1]
+		case "flowlabel":
+			n.GeneveSection.FlowLabel = argStrings[i+1]
+		case "df":
+			n.GeneveSection.IPDoNotFragment = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(n.Name) || validator.IsEmpty(n.GeneveSection.Id) {
+		fmt.Printf("Failed to create Geneve. Missing Geneve name or id\n")
+		return
+	}
+
+	networkConfigureNetDev(&n, host, token)
+}
+
+func networkCreateMACsec(args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+	n := networkd.NetDev{
+		Name: argStrings[0],
+		Kind: "macsec",
+	}
+
+	for i := 1; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Links = strings.Fields(argStrings[i+1])
+		case "port":
+			n.MACsecSection.Port = argStrings[i+1]
+		case "encrypt":
+			n.MACsecSection.Encrypt = argStrings[i+1]
+		case "txpn":
+			n.MACsecTransmitAssociationSection.PacketNumber = argStrings[i+1]
+		case "txkeyid":
+			n.MACsecTransmitAssociationSection.KeyId = argStrings[i+1]
+		case "txkey":
+			n.MACsecTransmitAssociationSection.Key = argStrings[i+1]
+		case "txkeyfile":
+			n.MACsecTransmitAssociationSection.KeyFile = argStrings[i+1]
+		case "txactivate":
+			n.MACsecTransmitAssociationSection.Activate = argStrings[i+1]
+		case "encoding":
+			n.MACsecTransmitAssociationSection.UseForEncoding = argStrings[i+1]
+		case "rxport":
+			n.MACsecReceiveChannelSection.Port = argStrings[i+1]
+			n.MACsecReceiveAssociationSection.Port = argStrings[i+1]
+		case "rxmac":
+			n.MACsecReceiveChannelSection.MACAddress = argStrings[i+1]
+			n.MACsecReceiveAssociationSection.MACAddress = argStrings[i+1]
+		case "rxpn":
+			n.MACsecReceiveAssociationSection.PacketNumber = argStrings[i+1]
+		case "rxkeyid":
+			n.MACsecReceiveAssociationSection.KeyId = argStrings[i+1]
+		case "rxkey":
+			n.MACsecReceiveAssociationSection.Key = argStrings[i+1]
+		case "rxkeyfile":
+			n.MACsecReceiveAssociationSection.KeyFile = argStrings[i+1]
+		case "rxactivate":
+			n.MACsecReceiveAssociationSection.Activate = argStrings[i+1]
+		}
+	}
+
+	if validator.IsArrayEmpty(n.Links) || validator.IsEmpty(n.Name) {
+		fmt.Printf("Failed to create MACsec. Missing MACsec name or dev\n")
+		return
+	}
+
+	networkConfigureNetDev(&n, host, token)
+}
+
+func networkCreateTunnel(args cli.Args, kind, host string, token map[string]string) {
+	argStrings := args.Slice()
+	n := networkd.NetDev{
+		Name: argStrings[0],
+		Kind: kind,
+	}
+
+	for i := 1; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Links = strings.Fields(argStrings[i+1])
+		case "local":
+			if !validator.IsTunnelAddress(argStrings[i+1]) {
+				fmt.Printf("Invalid local: %s\n", argStrings[i+1])
+				return
+			}
+			n.TunnelSection.Local = argStrings[i+1]
+		case "remote":
+			if !validator.IsTunnelAddress(argStrings[i+1]) {
+				fmt.Printf("Invalid remote: %s\n", argStrings[i+1])
+				return
+			}
+			n.TunnelSection.Remote = argStrings[i+1]
+		case "tos":
+			n.TunnelSection.TOS = argStrings[i+1]
+		case "ttl":
+			n.TunnelSection.TTL = argStrings[i+1]
+		case "key":
+			n.TunnelSection.Key = argStrings[i+1]
+		case "ikey":
+			n.TunnelSection.InputKey = argStrings[i+1]
+		case "okey":
+			n.TunnelSection.OutputKey = argStrings[i+1]
+		case "pmtudisc":
+			n.TunnelSection.DiscoverPathMTU = argStrings[i+1]
+		case "independent":
+			n.TunnelSection.Independent = argStrings[i+1]
+		case "loopback":
+			n.TunnelSection.AssignToLoopback = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(n.Name) {
+		fmt.Printf("Failed to create %s. Missing name\n", kind)
+		return
+	}
+
+	networkConfigureNetDev(&n, host, token)
+}
+
+func acquireNetDev(name string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/networkd/netdev/"+name, token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire netdev: %v\n", err)
+		return
+	}
+
+	s := NetDevStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to acquire netdev: %v\n", s.Errors)
+		return
+	}
+
+	d := s.Message
+	fmt.Printf("%v %v\n", color.HiBlueString("                Name:"), d.Name)
+	fmt.Printf("%v %v\n", color.HiBlueString("                Kind:"), d.Kind)
+	if !validator.IsEmpty(d.NetDevFile) {
+		fmt.Printf("%v %v\n", color.HiBlueString("         NetDev File:"), d.NetDevFile)
+	}
+	if d.Index > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("               Index:"), d.Index)
+		fmt.Printf("%v %v\n", color.HiBlueString("          Oper State:"), d.OperState)
+		fmt.Printf("%v %v\n", color.HiBlueString("                 MTU:"), d.MTU)
+		fmt.Printf("%v %v\n", color.HiBlueString("         MAC Address:"), d.MACAddress)
+	}
+	if d.Config != nil && len(d.Config.Links) > 0 {
+		fmt.Printf("%v %v\n", color.HiBlueString("               Links:"), strings.Join(d.Config.Links, " "))
+	}
+	if !validator.IsEmpty(d.Parent) {
+		fmt.Printf("%v %v\n", color.HiBlueString("              Parent:"), d.Parent)
+	}
+
+	switch d.Kind {
+	case "bond":
+		fmt.Printf("%v %v\n", color.HiBlueString("                Mode:"), d.BondMode)
+		fmt.Printf("%v %v\n", color.HiBlueString("Transmit Hash Policy:"), d.TransmitHashPolicy)
+		if !validator.IsEmpty(d.ActiveSlave) {
+			fmt.Printf("%v %v\n", color.HiBlueString("        Active Slave:"), d.ActiveSlave)
+		}
+		if d.AdInfo != nil {
+			fmt.Printf("%v %v\n", color.HiBlueString("       Aggregator Id:"), d.AdInfo.AggregatorId)
+			fmt.Printf("%v %v\n", color.HiBlueString("         Partner MAC:"), d.AdInfo.PartnerMAC)
+			fmt.Printf("%v %v\n", color.HiBlueString("         Partner Key:"), d.AdInfo.PartnerKey)
+		}
+	case "bridge":
+		fmt.Printf("%v %v\n", color.HiBlueString("                 STP:"), d.STP)
+		fmt.Printf("%v %v\n", color.HiBlueString("      VLAN Filtering:"), d.VLANFiltering)
+	case "vlan":
+		fmt.Printf("%v %v\n", color.HiBlueString("                  Id:"), d.VLANId)
+	case "vxlan":
+		fmt.Printf("%v %v\n", color.HiBlueString("                 VNI:"), d.VNI)
+		if !validator.IsEmpty(d.Remote) {
+			fmt.Printf("%v %v\n", color.HiBlueString("              Remote:"), d.Remote)
+		}
+		if !validator.IsEmpty(d.Local) {
+			fmt.Printf("%v %v\n", color.HiBlueString("               Local:"), d.Local)
+		}
+	}
+
+	for _, m := range d.Members {
+		fmt.Printf("%v %v (%v)", color.HiBlueString("              Member:"), m.Name, m.OperState)
+		if !validator.IsEmpty(m.MiiStatus) {
+			fmt.Printf(" %v %v %v %v", color.HiBlueString("State:"), m.State, color.HiBlueString("MII Status:"), m.MiiStatus)
+		}
+		if !validator.IsEmpty(m.STPState) {
+			fmt.Printf(" %v %v", color.HiBlueString("STP State:"), m.STPState)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func networkPatchNetDev(args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+	p := networkd.NetDevPatch{}
+
+	for i := 1; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "add-dev":
+			p.AddLinks = strings.Split(argStrings[i+1], ",")
+		case "remove-dev":
+			p.RemoveLinks = strings.Split(argStrings[i+1], ",")
+		case "desc":
+			p.Description = argStrings[i+1]
+		case "mtu":
+			if !validator.IsUint32(argStrings[i+1]) {
+				fmt.Printf("Failed to parse mtu: %s\n", argStrings[i+1])
+				return
+			}
+			p.MTUBytes = argStrings[i+1]
+		case "thp":
+			p.BondSection.TransmitHashPolicy = argStrings[i+1]
+		case "ltr":
+			if !validator.IsBondLACPTransmitRate(argStrings[i+1]) {
+				fmt.Printf("Failed to parse LACP transmit rate: %s\n", argStrings[i+1])
+				return
+			}
+			p.BondSection.LACPTransmitRate = argStrings[i+1]
+		case "mms":
+			p.BondSection.MIIMonitorSec = argStrings[i+1]
+		case "stp":
+			if !validator.IsBool(argStrings[i+1]) {
+				fmt.Printf("Failed to parse stp: %s\n", argStrings[i+1])
+				return
+			}
+			p.BridgeSection.STP = validator.BoolToString(argStrings[i+1])
+		}
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPatch, host, "/api/v1/network/networkd/netdev/"+argStrings[0], token, p)
+	if err != nil {
+		fmt.Printf("Failed to update netdev: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to update netdev: %v\n", m.Errors)
+	}
+}
@@ -657,3 +657,499 @@ func TestNetDevCreateTap(t *testing.T) {
 		t.Fatalf("Failed to remove .network file='%v'", err)
 	}
 }
+
+func TestNetDevCreateVRF(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.NetDev{
+		Name:  "vrf99",
+		Kind:  "vrf",
+		Links: []string{"test99"},
+		VRFSection: networkd.VRF{
+			Table: "99",
+		},
+	}
+
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to create VRF: %v\n", err)
+	}
+
+	time.Sleep(time.Second * 5)
+
+	if !validator.LinkExists("vrf99") {
+		t.Fatalf("Failed to create vrf='vrf99'")
+	}
+
+	m, _, err := networkd.CreateOrParseNetDevFile("vrf99", "vrf")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of vrf='vrf99'")
+	}
+
+	if m.GetKeySectionString("VRF", "Table") != "99" {
+		t.Fatalf("Invalid Table in .netdev file of vrf='vrf99'")
+	}
+
+	m, err = networkd.CreateOrParseNetworkFile("test99")
+	if err != nil {
+		t.Fatalf("Failed to parse .network file of test99")
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("Network", "VRF") != "vrf99" {
+		t.Fatalf("Failed to parse .network file of test99")
+	}
+
+	if err := networkd.RemoveNetDev(n.Name, n.Kind); err != nil {
+		t.Fatalf("Failed to remove .network file='%v'", err)
+	}
+}
+
+func TestNetDevCreateVeth(t *testing.T) {
+	n := networkd.NetDev{
+		Name: "veth99",
+		Kind: "veth",
+		PeerSection: networkd.Peer{
+			Name: "veth98",
+		},
+	}
+
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to create Veth: %v\n", err)
+	}
+
+	time.Sleep(time.Second * 5)
+
+	if !validator.LinkExists("veth99") || !validator.LinkExists("veth98") {
+		t.Fatalf("Failed to create veth='veth99' peer='veth98'")
+	}
+
+	m, _, err := networkd.CreateOrParseNetDevFile("veth99", "veth")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of veth='veth99'")
+	}
+
+	if m.GetKeySectionString("Peer", "Name") != "veth98" {
+		t.Fatalf("Invalid Peer Name in .netdev file of veth='veth99'")
+	}
+
+	if err := networkd.RemoveNetDev(n.Name, n.Kind); err != nil {
+		t.Fatalf("Failed to remove .network file='%v'", err)
+	}
+}
+
+func TestNetDevCreateGRE(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.NetDev{
+		Name:  "gre99",
+		Kind:  "gre",
+		Links: []string{"test99"},
+		TunnelSection: networkd.Tunnel{
+			Local:  "192.168.1.2",
+			Remote: "192.168.1.3",
+			TTL:    "64",
+		},
+	}
+
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to create GRE: %v\n", err)
+	}
+
+	time.Sleep(time.Second * 5)
+
+	m, _, err := networkd.CreateOrParseNetDevFile("gre99", "gre")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of gre='gre99'")
+	}
+
+	if m.GetKeySectionString("Tunnel", "Remote") != "192.168.1.3" {
+		t.Fatalf("Invalid Remote in .netdev file of gre='gre99'")
+	}
+
+	m, err = networkd.CreateOrParseNetworkFile("test99")
+	if err != nil {
+		t.Fatalf("Failed to parse .network file of test99")
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("Network", "Tunnel") != "gre99" {
+		t.Fatalf("Failed to parse .network file of test99")
+	}
+
+	if err := networkd.RemoveNetDev(n.Name, n.Kind); err != nil {
+		t.Fatalf("Failed to remove .network file='%v'", err)
+	}
+}
+
+func TestNetDevCreateWireGuardPeers(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	_, pkey1, _ := networkd.GenerateWireGuardKey()
+	_, pkey2, _ := networkd.GenerateWireGuardKey()
+
+	n := networkd.NetDev{
+		Name:  "wg99",
+		Kind:  "wireguard",
+		Links: []string{"test99"},
+		WireGuardSection: networkd.WireGuard{
+			ListenPort: "51820",
+		},
+		WireGuardPeerSections: []networkd.WireGuardPeer{
+			{
+				PublicKey:  pkey1,
+				Endpoint:   "192.168.1.3:51820",
+				AllowedIPs: []string{"10.0.0.1/32"},
+			},
+			{
+				PublicKey:            pkey2,
+				AllowedIPs:           []string{"10.0.0.2/32"},
+				GeneratePresharedKey: true,
+			},
+		},
+	}
+
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to create WireGuard: %v\n", err)
+	}
+	defer networkd.RemoveNetDev(n.Name, n.Kind)
+
+	m, err := networkd.ParseNetDevFile("wg99", "wireguard")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of wireguard='wg99'")
+	}
+
+	if !system.PathExists(m.GetKeySectionString("WireGuard", "PrivateKeyFile")) {
+		t.Fatalf("Failed to generate private key of wireguard='wg99'")
+	}
+	defer os.Remove(m.GetKeySectionString("WireGuard", "PrivateKeyFile"))
+
+	if len(m.Sections("WireGuardPeer")) != 2 {
+		t.Fatalf("Invalid number of peers in .netdev file of wireguard='wg99'")
+	}
+
+	p := networkd.WireGuardPeer{
+		PublicKey: pkey1,
+	}
+	resp, err := web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/networkd/netdev/wg99/wireguard/peer", nil, p)
+	if err != nil {
+		t.Fatalf("Failed to remove wireguard peer: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to remove wireguard peer: %v\n", j.Errors)
+	}
+
+	m, err = networkd.ParseNetDevFile("wg99", "wireguard")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of wireguard='wg99'")
+	}
+
+	if len(m.Sections("WireGuardPeer")) != 1 || m.Sections("WireGuardPeer")[0].Key("PublicKey").String() != pkey2 {
+		t.Fatalf("Failed to remove peer from .netdev file of wireguard='wg99'")
+	}
+
+	if !system.PathExists(m.Sections("WireGuardPeer")[0].Key("PresharedKeyFile").String()) {
+		t.Fatalf("Failed to generate preshared key of wireguard='wg99'")
+	}
+	defer os.Remove(m.Sections("WireGuardPeer")[0].Key("PresharedKeyFile").String())
+}
+
+func TestNetDevWireGuardKeyFile(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.NetDev{
+		Name:  "wg98",
+		Kind:  "wireguard",
+		Links: []string{"test99"},
+		WireGuardSection: networkd.WireGuard{
+			ListenPort: "invalid",
+		},
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/networkd/netdev/configure", nil, n)
+	if err != nil {
+		t.Fatalf("Failed to configure netdev: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if j.Success {
+		t.Fatalf("Invalid ListenPort accepted\n")
+	}
+	if system.PathExists("/etc/systemd/network/wg98.key") {
+		t.Fatalf("Key file written for invalid wireguard='wg98'\n")
+	}
+
+	n.WireGuardSection.ListenPort = "51821"
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to create WireGuard: %v\n", err)
+	}
+
+	key, err := os.ReadFile("/etc/systemd/network/wg98.key")
+	if err != nil {
+		t.Fatalf("Failed to generate private key of wireguard='wg98': %v\n", err)
+	}
+
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to reconfigure WireGuard: %v\n", err)
+	}
+
+	k, err := os.ReadFile("/etc/systemd/network/wg98.key")
+	if err != nil || string(k) != string(key) {
+		t.Fatalf("Private key of wireguard='wg98' rotated on reconfigure\n")
+	}
+
+	networkd.RemoveNetDev(n.Name, n.Kind)
+	if system.PathExists("/etc/systemd/network/wg98.key") {
+		t.Fatalf("Failed to remove private key of wireguard='wg98'\n")
+	}
+}
+
+func TestNetDevWireGuardPresharedKey(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	// Older clients send a single WireGuardPeerSection
+	n := map[string]interface{}{
+		"Name": "wg97",
+		"Kind": "wireguard",
+		"Link": []string{"test99"},
+		"WireGuardSection": networkd.WireGuard{
+			ListenPort:   "51822",
+			FirewallMark: "42",
+			RouteTable:   "main",
+		},
+		"WireGuardPeerSection": networkd.WireGuardPeer{
+			PublicKey:            "cFYnpDfOQS2QhOMwf0bSixjLfzjJ3ESDIaW+SXqp4SA=",
+			AllowedIPs:           []string{"10.0.0.2/32"},
+			GeneratePresharedKey: true,
+		},
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/networkd/netdev/configure", nil, n)
+	if err != nil {
+		t.Fatalf("Failed to configure netdev: %v\n", err)
+	}
+	defer networkd.RemoveNetDev("wg97", "wireguard")
+
+	j := struct {
+		Success bool                        `json:"success"`
+		Message networkd.WireGuardPublicKey `json:"message"`
+		Errors  string                      `json:"errors"`
+	}{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to create WireGuard: %v\n", j.Errors)
+	}
+
+	if len(j.Message.PresharedKeys) != 1 || !validator.IsWireGuardKey(j.Message.PresharedKeys[0].PresharedKey) {
+		t.Fatalf("Failed to return the generated preshared key: %v\n", j.Message.PresharedKeys)
+	}
+
+	m, err := networkd.ParseNetDevFile("wg97", "wireguard")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of wireguard='wg97'")
+	}
+	if m.GetKeySectionString("WireGuard", "FirewallMark") != "42" {
+		t.Fatalf("Failed to set FirewallMark")
+	}
+	if m.GetKeySectionString("WireGuard", "RouteTable") != "main" {
+		t.Fatalf("Failed to set RouteTable")
+	}
+	if m.GetKeySectionString("WireGuardPeer", "PublicKey") != "cFYnpDfOQS2QhOMwf0bSixjLfzjJ3ESDIaW+SXqp4SA=" {
+		t.Fatalf("Failed to set peer sent as WireGuardPeerSection")
+	}
+}
+
+func TestNetDevAcquireBond(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test98"}})
+	defer removeLink(t, "test99")
+	defer removeLink(t, "test98")
+
+	n := networkd.NetDev{
+		Name:  "bond99",
+		Kind:  "bond",
+		Links: []string{"test99", "test98"},
+		BondSection: networkd.Bond{
+			Mode:               "802.3ad",
+			TransmitHashPolicy: "layer3+4",
+		},
+	}
+
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to create Bond: %v\n", err)
+	}
+	defer networkd.RemoveNetDev(n.Name, n.Kind)
+
+	time.Sleep(time.Second * 5)
+
+	resp, err := web.DispatchSocket(http.MethodGet, "", "/api/v1/network/networkd/netdev/bond99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire netdev: %v\n", err)
+	}
+
+	d := NetDevStats{}
+	if err := json.Unmarshal(resp, &d); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !d.Success {
+		t.Fatalf("Failed to acquire netdev: %v\n", d.Errors)
+	}
+
+	if d.Message.Kind != "bond" || d.Message.Config == nil {
+		t.Fatalf("Failed to acquire .netdev of bond='bond99'")
+	}
+
+	if d.Message.Config.BondSection.TransmitHashPolicy != "layer3+4" {
+		t.Fatalf("Invalid TransmitHashPolicy of bond='bond99'")
+	}
+
+	if len(d.Message.Config.Links) != 2 {
+		t.Fatalf("Invalid links of bond='bond99': %v", d.Message.Config.Links)
+	}
+
+	if d.Message.BondMode != "802.3ad" {
+		t.Fatalf("Invalid mode of bond='bond99': %v", d.Message.BondMode)
+	}
+
+	for _, l := range []string{"test99", "test98"} {
+		m, err := networkd.CreateOrParseNetworkFile(l)
+		if err == nil {
+			os.Remove(m.Path)
+		}
+	}
+}
+
+func TestNetDevPatchBridge(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test98"}})
+	defer removeLink(t, "test99")
+	defer removeLink(t, "test98")
+
+	n := networkd.NetDev{
+		Name:  "br99",
+		Kind:  "bridge",
+		Links: []string{"test99"},
+	}
+
+	if err := configureNetDev(t, n); err != nil {
+		t.Fatalf("Failed to create Bridge: %v\n", err)
+	}
+	defer networkd.RemoveNetDev(n.Name, n.Kind)
+
+	time.Sleep(time.Second * 5)
+
+	// Keys pmd does not model must survive the patch
+	m, err := networkd.ParseNetDevFile("br99", "bridge")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of bridge='br99'")
+	}
+	if err := m.SetKeySectionString("Bridge", "MulticastSnooping", "no"); err != nil {
+		t.Fatalf("Failed to update .netdev file of bridge='br99': %v", err)
+	}
+	if err := m.Save(); err != nil {
+		t.Fatalf("Failed to update .netdev file of bridge='br99': %v", err)
+	}
+
+	p := networkd.NetDevPatch{
+		NetDev: networkd.NetDev{
+			BridgeSection: networkd.Bridge{
+				STP:           "yes",
+				VLANFiltering: "no",
+			},
+		},
+		AddLinks:    []string{"test98"},
+		RemoveLinks: []string{"test99"},
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPatch, "", "/api/v1/network/networkd/netdev/br99", nil, p)
+	if err != nil {
+		t.Fatalf("Failed to update netdev: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to update netdev: %v\n", j.Errors)
+	}
+
+	m, err = networkd.ParseNetDevFile("br99", "bridge")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of bridge='br99'")
+	}
+
+	if m.GetKeySectionString("Bridge", "STP") != "yes" {
+		t.Fatalf("Failed to update STP in .netdev file of bridge='br99'")
+	}
+	if m.GetKeySectionString("Bridge", "VLANFiltering") != "no" {
+		t.Fatalf("Failed to update VLANFiltering in .netdev file of bridge='br99'")
+	}
+	if m.GetKeySectionString("Bridge", "MulticastSnooping") != "no" {
+		t.Fatalf("Failed to keep MulticastSnooping in .netdev file of bridge='br99'")
+	}
+
+	links, err := networkd.FindNetDevLinks("br99", "bridge")
+	if err != nil {
+		t.Fatalf("Failed to find links of bridge='br99': %v", err)
+	}
+
+	if len(links) != 1 || links[0] != "test98" {
+		t.Fatalf("Invalid links of bridge='br99': %v", links)
+	}
+
+	// A key sent empty is removed, a rename is refused
+	resp, err = web.DispatchSocket(http.MethodPatch, "", "/api/v1/network/networkd/netdev/br99", nil,
+		map[string]interface{}{"BridgeSection": map[string]string{"STP": ""}})
+	if err != nil {
+		t.Fatalf("Failed to update netdev: %v\n", err)
+	}
+	j = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to update netdev: %v\n", j.Errors)
+	}
+
+	m, err = networkd.ParseNetDevFile("br99", "bridge")
+	if err != nil {
+		t.Fatalf("Failed to parse .netdev file of bridge='br99'")
+	}
+	if m.GetKeySectionString("Bridge", "STP") != "" {
+		t.Fatalf("Failed to remove STP from .netdev file of bridge='br99'")
+	}
+
+	resp, err = web.DispatchSocket(http.MethodPatch, "", "/api/v1/network/networkd/netdev/br99", nil, map[string]string{"Name": "br98"})
+	if err != nil {
+		t.Fatalf("Failed to update netdev: %v\n", err)
+	}
+	j = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if j.Success {
+		t.Fatalf("Renamed bridge='br99'")
+	}
+
+	for _, l := range []string{"test99", "test98"} {
+		m, err := networkd.CreateOrParseNetworkFile(l)
+		if err == nil {
+			os.Remove(m.Path)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/qdisc"
+	"github.com/vmware/pmd-next-gen/plugins/network/networkd"
+)
+
+type TrafficControlStats struct {
+	Success bool                     `json:"success"`
+	Message qdisc.LinkTrafficControl `json:"message"`
+	Errors  string                   `json:"errors"`
+}
+
+// Maps the tc kind names to the slice in networkd.TrafficControlSections
+var qdiscKinds = map[string]string{
+	"clsact":    "QDiscSections",
+	"ingress":   "QDiscSections",
+	"fq":        "FairQueueingSections",
+	"fq_codel":  "FairQueueingControlledDelaySections",
+	"tbf":       "TokenBucketFilterSections",
+	"cake":      "CAKESections",
+	"netem":     "NetworkEmulatorSections",
+	"htb":       "HierarchyTokenBucketSections",
+	"htb-class": "HierarchyTokenBucketClassSections",
+}
+
+// parseQDisc takes "dev [LINK] kind [KIND]" followed by the networkd option
+// names of the kind as keys, e.g. "rate 10M latencysec 50ms".
+func parseQDisc(args cli.Args) (*networkd.Network, error) {
+	argStrings := args.Slice()
+
+	n := networkd.Network{}
+	kind := ""
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Link = argStrings[i+1]
+		case "kind":
+			kind = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(n.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+
+	field, ok := qdiscKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("Invalid kind=%s\n", kind)
+	}
+
+	s := reflect.ValueOf(&n.TrafficControl).Elem().FieldByName(field)
+	e := reflect.New(s.Type().Elem()).Elem()
+	for i := 0; i < len(argStrings)-1; i += 2 {
+		key := argStrings[i]
+		if key == "dev" || key == "kind" {
+			continue
+		}
+
+		found := false
+		for j := 0; j < e.NumField(); j++ {
+			if strings.EqualFold(e.Type().Field(j).Name, key) {
+				e.Field(j).SetString(argStrings[i+1])
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("Invalid key=%s for kind=%s\n", key, kind)
+		}
+	}
+
+	if kind == "clsact" || kind == "ingress" {
+		e.FieldByName("Parent").SetString(kind)
+	}
+
+	s.Set(reflect.Append(s, e))
+	return &n, nil
+}
+
+func networkAddQDisc(args cli.Args, host string, token map[string]string) {
+	n, err := parseQDisc(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	networkConfigure(n, host, token)
+}
+
+func networkRemoveQDisc(args cli.Args, host string, token map[string]string) {
+	n, err := parseQDisc(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(http.MethodDelete, host, "/api/v1/network/networkd/network/remove", token, n)
+	if err != nil {
+		fmt.Printf("Failed to remove qdisc: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to remove qdisc: %v\n", m.Errors)
+	}
+}
+
+func displayTrafficControlObject(label string, o *qdisc.Object) {
+	fmt.Printf("%v %v %v %v %v %v\n", color.HiBlueString(label), o.Kind, color.HiBlueString("Handle:"), o.Handle, color.HiBlueString("Parent:"), o.Parent)
+	fmt.Printf("         %v %v %v %v %v %v %v %v %v %v\n",
+		color.HiBlueString("Sent:"), fmt.Sprintf("%d bytes %d pkt", o.Stats.Bytes, o.Stats.Packets),
+		color.HiBlueString("Dropped:"), o.Stats.Drops,
+		color.HiBlueString("Overlimits:"), o.Stats.Overlimits,
+		color.HiBlueString("Requeues:"), o.Stats.Requeues,
+		color.HiBlueString("Backlog:"), fmt.Sprintf("%db %dp", o.Stats.Backlog, o.Stats.Qlen))
+}
+
+func acquireTrafficControl(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath("/api/v1/network/netlink/qdisc/"+link), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire qdisc: %v\n", err)
+		return
+	}
+
+	t := TrafficControlStats{}
+	if err := json.Unmarshal(resp, &t); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !t.Success {
+		fmt.Printf("Failed to acquire qdisc: %v\n", t.Errors)
+		return
+	}
+
+	for _, q := range t.Message.QDiscs {
+		displayTrafficControlObject("  QDisc:", &q)
+	}
+	for _, c := range t.Message.Classes {
+		displayTrafficControlObject("  Class:", &c)
+	}
+}
@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/resolved"
+)
+
+type ResolvedConfigStats struct {
+	Success bool                            `json:"success"`
+	Message resolved.ResolvedConfigDescribe `json:"message"`
+	Errors  string                          `json:"errors"`
+}
+
+type ResolvedStatisticsStats struct {
+	Success bool                `json:"success"`
+	Message resolved.Statistics `json:"message"`
+	Errors  string              `json:"errors"`
+}
+
+func parseLinkDns(args cli.Args) (*resolved.LinkDns, error) {
+	argStrings := args.Slice()
+
+	d := resolved.LinkDns{}
+	for i := 0; i < len(argStrings); i++ {
+		if i+1 >= len(argStrings) {
+			return nil, fmt.Errorf("Missing value of %s\n", argStrings[i])
+		}
+
+		switch argStrings[i] {
+		case "dev":
+			d.Link = argStrings[i+1]
+		case "dns":
+			d.DnsServers = strings.Split(argStrings[i+1], ",")
+			if !validator.IsIPs(d.DnsServers) {
+				return nil, fmt.Errorf("Invalid dns=%s\n", argStrings[i+1])
+			}
+		case "domains":
+			d.Domains = strings.Split(argStrings[i+1], ",")
+		case "default-route":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid default-route=%s\n", argStrings[i+1])
+			}
+			d.DefaultRoute = validator.BoolToString(argStrings[i+1])
+		case "llmnr":
+			d.LLMNR = argStrings[i+1]
+		case "mdns":
+			d.MulticastDNS = argStrings[i+1]
+		case "dnssec":
+			d.DNSSEC = argStrings[i+1]
+		case "dns-over-tls":
+			d.DNSOverTLS = argStrings[i+1]
+		default:
+			return nil, fmt.Errorf("Unknown option %s\n", argStrings[i])
+		}
+		i++
+	}
+
+	if validator.IsEmpty(d.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+
+	return &d, nil
+}
+
+func networkConfigureRuntimeDns(args cli.Args, host string, token map[string]string) {
+	d, err := parseLinkDns(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, host, "/api/v1/network/resolved/"+d.Link+"/configure", token, d)
+	if err != nil {
+		fmt.Printf("Failed to configure link DNS: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure link DNS: %v\n", m.Errors)
+	}
+}
+
+func networkRevertRuntimeDns(link string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodPost, host, "/api/v1/network/resolved/"+link+"/revert", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to revert link DNS: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to revert link DNS: %v\n", m.Errors)
+	}
+}
+
+func parseResolvedConfig(args cli.Args) (*resolved.ResolvedConfig, error) {
+	argStrings := args.Slice()
+
+	c := resolved.ResolvedConfig{}
+	for i := 0; i < len(argStrings); i++ {
+		if i+1 >= len(argStrings) {
+			return nil, fmt.Errorf("Missing value of %s\n", argStrings[i])
+		}
+
+		switch argStrings[i] {
+		case "fallback-dns":
+			c.FallbackDNS = strings.Split(argStrings[i+1], ",")
+		case "dnssec":
+			c.DNSSEC = argStrings[i+1]
+		case "dns-over-tls":
+			c.DNSOverTLS = argStrings[i+1]
+		case "llmnr":
+			c.LLMNR = argStrings[i+1]
+		case "mdns":
+			c.MulticastDNS = argStrings[i+1]
+		case "cache":
+			c.Cache = argStrings[i+1]
+		case "stub-listener":
+			c.DNSStubListener = argStrings[i+1]
+		case "stub-listener-extra":
+			c.DNSStubListenerExtra = strings.Split(argStrings[i+1], ",")
+		case "read-etc-hosts":
+			c.ReadEtcHosts = argStrings[i+1]
+		case "single-label":
+			c.ResolveUnicastSingleLabel = argStrings[i+1]
+		default:
+			return nil, fmt.Errorf("Unknown option %s\n", argStrings[i])
+		}
+		i++
+	}
+
+	return &c, nil
+}
+
+func networkConfigureResolvedConfig(args cli.Args, host string, token map[string]string) {
+	c, err := parseResolvedConfig(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, host, "/api/v1/network/resolved/config", token, c)
+	if err != nil {
+		fmt.Printf("Failed to configure resolved: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure resolved: %v\n", m.Errors)
+	}
+}
+
+func networkResetResolvedConfig(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodDelete, host, "/api/v1/network/resolved/config", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to reset resolved configuration: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to reset resolved configuration: %v\n", m.Errors)
+	}
+}
+
+func networkShowResolvedConfig(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/resolved/config", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire resolved configuration: %v\n", err)
+		return
+	}
+
+	s := ResolvedConfigStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to acquire resolved configuration: %v\n", s.Errors)
+		return
+	}
+
+	c := s.Message.Configured
+	e := s.Message.Effective
+	for _, o := range []struct {
+		name       string
+		configured string
+		effective  string
+	}{
+		{"FallbackDNS", strings.Join(c.FallbackDNS, " "), strings.Join(e.FallbackDNS, " ")},
+		{"DNSSEC", c.DNSSEC, e.DNSSEC},
+		{"DNSOverTLS", c.DNSOverTLS, e.DNSOverTLS},
+		{"LLMNR", c.LLMNR, e.LLMNR},
+		{"MulticastDNS", c.MulticastDNS, e.MulticastDNS},
+		{"Cache", c.Cache, e.Cache},
+		{"DNSStubListener", c.DNSStubListener, e.DNSStubListener},
+		{"DNSStubListenerExtra", strings.Join(c.DNSStubListenerExtra, " "), strings.Join(e.DNSStubListenerExtra, " ")},
+		{"ReadEtcHosts", c.ReadEtcHosts, e.ReadEtcHosts},
+		{"ResolveUnicastSingleLabel", c.ResolveUnicastSingleLabel, e.ResolveUnicastSingleLabel},
+	} {
+		fmt.Printf("%v %v", color.HiBlueString("%25s:", o.name), o.configured)
+		if !validator.IsEmpty(o.effective) && o.effective != o.configured {
+			fmt.Printf(" %v %v", color.HiBlueString("(effective:"), o.effective+")")
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func networkShowResolvedStatistics(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, "/api/v1/network/resolved/statistics", token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire resolved statistics: %v\n", err)
+		return
+	}
+
+	s := ResolvedStatisticsStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !s.Success {
+		fmt.Printf("Failed to acquire resolved statistics: %v\n", s.Errors)
+		return
+	}
+
+	st := s.Message
+	fmt.Printf("%v %v\n", color.HiBlueString("        Current DNS Server:"), st.CurrentDNSServer)
+	fmt.Printf("%v %v\n", color.HiBlueString("          DNSSEC Supported:"), st.DNSSECSupported)
+	fmt.Printf("%v %v\n", color.HiBlueString("      Current Transactions:"), st.Transactions.CurrentTransactions)
+	fmt.Printf("%v %v\n", color.HiBlueString("        Total Transactions:"), st.Transactions.TotalTransactions)
+	fmt.Printf("%v %v\n", color.HiBlueString("                Cache Size:"), st.Cache.Size)
+	fmt.Printf("%v %v\n", color.HiBlueString("                Cache Hits:"), st.Cache.Hits)
+	fmt.Printf("%v %v\n", color.HiBlueString("              Cache Misses:"), st.Cache.Misses)
+	fmt.Printf("%v %v\n", color.HiBlueString("             DNSSEC Secure:"), st.DNSSEC.Secure)
+	fmt.Printf("%v %v\n", color.HiBlueString("           DNSSEC Insecure:"), st.DNSSEC.Insecure)
+	fmt.Printf("%v %v\n", color.HiBlueString("              DNSSEC Bogus:"), st.DNSSEC.Bogus)
+	fmt.Printf("%v %v\n", color.HiBlueString("      DNSSEC Indeterminate:"), st.DNSSEC.Indeterminate)
+
+	for _, l := range st.Links {
+		if validator.IsEmpty(l.CurrentDNSServer) {
+			continue
+		}
+
+		fmt.Printf("\n%v %v %v %v %v %v\n", color.HiBlueString("Link:"), l.Link, color.HiBlueString("Current DNS Server:"), l.CurrentDNSServer,
+			color.HiBlueString("DNSSEC Supported:"), l.DNSSECSupported)
+	}
+}
+
+// networkResolvedAction posts to one of the resolved endpoints which take no
+// arguments like flushcaches
+func networkResolvedAction(action string, what string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodPost, host, "/api/v1/network/resolved/"+action, token, nil)
+	if err != nil {
+		fmt.Printf("Failed to %s: %v\n", what, err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to %s: %v\n", what, m.Errors)
+	}
+}
@@ -0,0 +1,411 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/route"
+	"github.com/vmware/pmd-next-gen/plugins/network/networkd"
+)
+
+type NextHopStats struct {
+	Success bool                  `json:"success"`
+	Message []route.NextHopObject `json:"message"`
+	Errors  string                `json:"errors"`
+}
+
+type RouteStats struct {
+	Success bool              `json:"success"`
+	Message []route.RouteInfo `json:"message"`
+	Errors  string            `json:"errors"`
+}
+
+// parseRouteNextHop parses GW@LINK[:WEIGHT] of a multipath route
+func parseRouteNextHop(s string) (*route.RouteNextHop, error) {
+	nh := route.RouteNextHop{}
+
+	if i := strings.LastIndex(s, ":"); i > 0 && strings.Contains(s, "@") && i > strings.Index(s, "@") {
+		w, err := strconv.Atoi(s[i+1:])
+		if err != nil || w < 1 || w > 256 {
+			return nil, fmt.Errorf("Invalid nexthop weight=%s\n", s[i+1:])
+		}
+		nh.Weight = w
+		s = s[:i]
+	}
+
+	gw, link, _ := strings.Cut(s, "@")
+	if !validator.IsEmpty(gw) && !validator.IsValidIP(gw) {
+		return nil, fmt.Errorf("Invalid nexthop gateway=%s\n", gw)
+	}
+	nh.Gateway = gw
+	nh.Link = link
+
+	return &nh, nil
+}
+
+func parseRuntimeRoute(args cli.Args) (*route.Route, error) {
+	argStrings := args.Slice()
+
+	rt := route.Route{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dst":
+			if argStrings[i+1] != "default" && !validator.IsIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid dst=%s\n", argStrings[i+1])
+			}
+			rt.Destination = argStrings[i+1]
+		case "gw":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid gw=%s\n", argStrings[i+1])
+			}
+			rt.Gateway = argStrings[i+1]
+		case "dev":
+			rt.Link = argStrings[i+1]
+		case "src":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid src=%s\n", argStrings[i+1])
+			}
+			rt.Source = argStrings[i+1]
+		case "table":
+			rt.Table = argStrings[i+1]
+		case "metric":
+			if !validator.IsUint32(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid metric=%s\n", argStrings[i+1])
+			}
+			rt.Metric, _ = strconv.Atoi(argStrings[i+1])
+		case "protocol":
+			rt.Protocol = argStrings[i+1]
+		case "scope":
+			rt.Scope = argStrings[i+1]
+		case "type":
+			rt.Type = argStrings[i+1]
+		case "tos":
+			tos, err := strconv.ParseUint(argStrings[i+1], 0, 8)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid tos=%s\n", argStrings[i+1])
+			}
+			rt.Tos = int(tos)
+		case "mtu":
+			if !validator.IsUint32(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid mtu=%s\n", argStrings[i+1])
+			}
+			rt.MTU, _ = strconv.Atoi(argStrings[i+1])
+		case "onlink":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid onlink=%s\n", argStrings[i+1])
+			}
+			rt.OnLink = validator.BoolToString(argStrings[i+1])
+		case "nexthop":
+			nh, err := parseRouteNextHop(argStrings[i+1])
+			if err != nil {
+				return nil, err
+			}
+			rt.MultiPath = append(rt.MultiPath, *nh)
+		case "encap-mpls":
+			rt.Encap = &route.RouteEncap{Type: "mpls"}
+			for _, l := range strings.Split(argStrings[i+1], "/") {
+				label, err := strconv.Atoi(l)
+				if err != nil || label < 0 || label > 0xfffff {
+					return nil, fmt.Errorf("Invalid encap-mpls label=%s\n", l)
+				}
+				rt.Encap.Labels = append(rt.Encap.Labels, label)
+			}
+		case "encap-seg6":
+			rt.Encap = &route.RouteEncap{Type: "seg6", Segments: strings.Split(argStrings[i+1], ",")}
+		}
+	}
+
+	// The mode may come before the segments
+	for i := 0; i < len(argStrings)-1; i++ {
+		if argStrings[i] == "encap-seg6-mode" && rt.Encap != nil {
+			rt.Encap.Mode = argStrings[i+1]
+		}
+	}
+
+	if validator.IsEmpty(rt.Destination) {
+		return nil, fmt.Errorf("Missing dst\n")
+	}
+
+	return &rt, nil
+}
+
+func networkRuntimeRoute(method string, action string, args cli.Args, host string, token map[string]string) {
+	rt, err := parseRuntimeRoute(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+	rt.Action = action
+
+	resp, err := web.DispatchSocket(method, host, netnsPath("/api/v1/network/netlink/route"), token, rt)
+	if err != nil {
+		fmt.Printf("Failed to configure route: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure route: %v\n", m.Errors)
+	}
+}
+
+func networkAddRuntimeRoute(args cli.Args, host string, token map[string]string) {
+	networkRuntimeRoute(http.MethodPost, "add", args, host, token)
+}
+
+func networkReplaceRuntimeRoute(args cli.Args, host string, token map[string]string) {
+	networkRuntimeRoute(http.MethodPost, "replace", args, host, token)
+}
+
+func networkRemoveRuntimeRoute(args cli.Args, host string, token map[string]string) {
+	networkRuntimeRoute(http.MethodDelete, "remove", args, host, token)
+}
+
+func displayRouteInfo(rts []route.RouteInfo) {
+	for _, rt := range rts {
+		dst := "default"
+		if rt.Dst.IP != "" && rt.Dst.Mask != 0 {
+			dst = fmt.Sprintf("%v/%v", rt.Dst.IP, rt.Dst.Mask)
+		}
+		if rt.TypeName != "unicast" {
+			dst = rt.TypeName + " " + dst
+		}
+		fmt.Printf("%v", dst)
+
+		if !validator.IsEmpty(rt.Gw) {
+			fmt.Printf(" %v %v", color.HiBlueString("via"), rt.Gw)
+		}
+		if !validator.IsEmpty(rt.LinkName) {
+			fmt.Printf(" %v %v", color.HiBlueString("dev"), rt.LinkName)
+		}
+		if rt.TableName != "main" {
+			fmt.Printf(" %v %v", color.HiBlueString("table"), rt.TableName)
+		}
+		fmt.Printf(" %v %v", color.HiBlueString("proto"), rt.ProtocolName)
+		if rt.ScopeName != "global" {
+			fmt.Printf(" %v %v", color.HiBlueString("scope"), rt.ScopeName)
+		}
+		if !validator.IsEmpty(rt.Src) {
+			fmt.Printf(" %v %v", color.HiBlueString("src"), rt.Src)
+		}
+		if rt.Priority > 0 {
+			fmt.Printf(" %v %v", color.HiBlueString("metric"), rt.Priority)
+		}
+		if !validator.IsEmpty(rt.Encap) {
+			fmt.Printf(" %v %v", color.HiBlueString("encap"), rt.Encap)
+		}
+		for _, nh := range rt.NextHops {
+			fmt.Printf("\n\t%v %v %v %v %v %v", color.HiBlueString("nexthop via"), nh.Gw, color.HiBlueString("dev"), nh.LinkName, color.HiBlueString("weight"), nh.Weight)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+func acquireRoutes(args cli.Args, host string, token map[string]string) {
+	argStrings := args.Slice()
+
+	q := url.Values{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "table", "family", "protocol":
+			q.Set(argStrings[i], argStrings[i+1])
+		case "dev":
+			q.Set("link", argStrings[i+1])
+		}
+	}
+
+	path := "/api/v1/network/netlink/route"
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath(path), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire routes: %v\n", err)
+		return
+	}
+
+	r := RouteStats{}
+	if err := json.Unmarshal(resp, &r); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !r.Success {
+		fmt.Printf("Failed to acquire routes: %v\n", r.Errors)
+		return
+	}
+
+	displayRouteInfo(r.Message)
+}
+
+func acquireRouteGet(destination string, host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath("/api/v1/network/netlink/route/get?destination="+url.QueryEscape(destination)), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire route: %v\n", err)
+		return
+	}
+
+	r := RouteStats{}
+	if err := json.Unmarshal(resp, &r); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !r.Success {
+		fmt.Printf("Failed to acquire route: %v\n", r.Errors)
+		return
+	}
+
+	displayRouteInfo(r.Message)
+}
+
+func parseNextHop(args cli.Args) (*networkd.Network, error) {
+	argStrings := args.Slice()
+
+	n := networkd.Network{}
+	nh := networkd.NextHopSection{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "dev":
+			n.Link = argStrings[i+1]
+		case "id":
+			if !validator.IsNextHopId(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid id=%s\n", argStrings[i+1])
+			}
+			nh.Id = argStrings[i+1]
+		case "gw":
+			if !validator.IsValidIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid gw=%s\n", argStrings[i+1])
+			}
+			nh.Gateway = argStrings[i+1]
+		case "family":
+			nh.Family = argStrings[i+1]
+		case "onlink":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid onlink=%s\n", argStrings[i+1])
+			}
+			nh.OnLink = validator.BoolToString(argStrings[i+1])
+		case "blackhole":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid blackhole=%s\n", argStrings[i+1])
+			}
+			nh.Blackhole = validator.BoolToString(argStrings[i+1])
+		case "group":
+			group := strings.Split(argStrings[i+1], ",")
+			for _, g := range group {
+				if !validator.IsNextHopGroup(g) {
+					return nil, fmt.Errorf("Invalid group=%s\n", g)
+				}
+			}
+			nh.Group = group
+		}
+	}
+
+	if validator.IsEmpty(n.Link) {
+		return nil, fmt.Errorf("Missing dev\n")
+	}
+	if validator.IsEmpty(nh.Id) {
+		return nil, fmt.Errorf("Missing id\n")
+	}
+
+	n.NextHopSections = []networkd.NextHopSection{nh}
+	return &n, nil
+}
+
+func networkAddNextHop(args cli.Args, host string, token map[string]string) {
+	n, err := parseNextHop(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	networkConfigure(n, host, token)
+}
+
+func networkRemoveNextHop(args cli.Args, host string, token map[string]string) {
+	n, err := parseNextHop(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(http.MethodDelete, host, "/api/v1/network/networkd/network/remove", token, n)
+	if err != nil {
+		fmt.Printf("Failed to remove nexthop: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to remove nexthop: %v\n", m.Errors)
+	}
+}
+
+func acquireNextHops(host string, token map[string]string) {
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath("/api/v1/network/netlink/route/nexthop"), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire nexthops: %v\n", err)
+		return
+	}
+
+	n := NextHopStats{}
+	if err := json.Unmarshal(resp, &n); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !n.Success {
+		fmt.Printf("Failed to acquire nexthops: %v\n", n.Errors)
+		return
+	}
+
+	for _, nh := range n.Message {
+		fmt.Printf("%v %v", color.HiBlueString("Id:"), nh.Id)
+		if !validator.IsEmpty(nh.Family) {
+			fmt.Printf(" %v %v", color.HiBlueString("Family:"), nh.Family)
+		}
+		if !validator.IsEmpty(nh.Gateway) {
+			fmt.Printf(" %v %v", color.HiBlueString("Gateway:"), nh.Gateway)
+		}
+		if !validator.IsEmpty(nh.LinkName) {
+			fmt.Printf(" %v %v", color.HiBlueString("Link:"), nh.LinkName)
+		}
+		if nh.OnLink {
+			fmt.Printf(" onlink")
+		}
+		if nh.Blackhole {
+			fmt.Printf(" blackhole")
+		}
+		if len(nh.Group) > 0 {
+			var group []string
+			for _, g := range nh.Group {
+				group = append(group, fmt.Sprintf("%d:%d", g.Id, g.Weight))
+			}
+			fmt.Printf(" %v %v", color.HiBlueString("Group:"), strings.Join(group, " "))
+		}
+		fmt.Printf("\n")
+	}
+}
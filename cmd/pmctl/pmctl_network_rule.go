@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/rule"
+)
+
+type RuleStats struct {
+	Success bool        `json:"success"`
+	Message []rule.Rule `json:"message"`
+	Errors  string      `json:"errors"`
+}
+
+func parseRuntimeRule(args cli.Args) (*rule.Rule, error) {
+	argStrings := args.Slice()
+
+	r := rule.Rule{}
+	for i := 0; i < len(argStrings)-1; i++ {
+		switch argStrings[i] {
+		case "tos":
+			if !validator.IsRoutingTypeOfService(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid tos=%s\n", argStrings[i+1])
+			}
+			r.TypeOfService = argStrings[i+1]
+		case "from":
+			if !validator.IsIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid from=%s\n", argStrings[i+1])
+			}
+			r.From = argStrings[i+1]
+		case "to":
+			if !validator.IsIP(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid to=%s\n", argStrings[i+1])
+			}
+			r.To = argStrings[i+1]
+		case "fwmark":
+			if !validator.IsRoutingFirewallMark(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid fwmark=%s\n", argStrings[i+1])
+			}
+			r.FirewallMark = argStrings[i+1]
+		case "table":
+			r.Table = argStrings[i+1]
+		case "goto":
+			if !validator.IsUint32(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid goto=%s\n", argStrings[i+1])
+			}
+			r.Goto = argStrings[i+1]
+		case "prio":
+			if !validator.IsUint32(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid prio=%s\n", argStrings[i+1])
+			}
+			r.Priority = argStrings[i+1]
+		case "iif":
+			r.IncomingInterface = argStrings[i+1]
+		case "oif":
+			r.OutgoingInterface = argStrings[i+1]
+		case "srcport":
+			if !validator.IsRoutingPort(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid srcport=%s\n", argStrings[i+1])
+			}
+			r.SourcePort = argStrings[i+1]
+		case "destport":
+			if !validator.IsRoutingPort(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid destport=%s\n", argStrings[i+1])
+			}
+			r.DestinationPort = argStrings[i+1]
+		case "ipproto":
+			r.IPProtocol = argStrings[i+1]
+		case "invertrule":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid invertrule=%s\n", argStrings[i+1])
+			}
+			r.InvertRule = validator.BoolToString(argStrings[i+1]) == "yes"
+		case "family":
+			if !validator.IsAddressFamily(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid family=%s\n", argStrings[i+1])
+			}
+			r.Family = argStrings[i+1]
+		case "usr":
+			if !validator.IsRoutingUser(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid usr=%s\n", argStrings[i+1])
+			}
+			r.User = argStrings[i+1]
+		case "suppressprefixlen":
+			if !validator.IsRoutingSuppressPrefixLength(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid suppressprefixlen=%s\n", argStrings[i+1])
+			}
+			r.SuppressPrefixLength = argStrings[i+1]
+		case "suppressifgrp":
+			if !validator.IsUint32(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid suppressifgrp=%s\n", argStrings[i+1])
+			}
+			r.SuppressInterfaceGroup = argStrings[i+1]
+		case "type":
+			r.Type = argStrings[i+1]
+		case "protocol":
+			r.Protocol = argStrings[i+1]
+		}
+	}
+
+	return &r, nil
+}
+
+func networkRuntimeRule(method string, args cli.Args, host string, token map[string]string) {
+	r, err := parseRuntimeRule(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(method, host, netnsPath("/api/v1/network/rule"), token, r)
+	if err != nil {
+		fmt.Printf("Failed to configure routing policy rule: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure routing policy rule: %v\n", m.Errors)
+	}
+}
+
+func networkAddRuntimeRule(args cli.Args, host string, token map[string]string) {
+	networkRuntimeRule(http.MethodPost, args, host, token)
+}
+
+func networkRemoveRuntimeRule(args cli.Args, host string, token map[string]string) {
+	networkRuntimeRule(http.MethodDelete, args, host, token)
+}
+
+func acquireRules(family string, host string, token map[string]string) {
+	path := "/api/v1/network/rule"
+	if !validator.IsEmpty(family) {
+		path += "?family=" + family
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, host, netnsPath(path), token, nil)
+	if err != nil {
+		fmt.Printf("Failed to acquire routing policy rules: %v\n", err)
+		return
+	}
+
+	r := RuleStats{}
+	if err := json.Unmarshal(resp, &r); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !r.Success {
+		fmt.Printf("Failed to acquire routing policy rules: %v\n", r.Errors)
+		return
+	}
+
+	for _, rl := range r.Message {
+		fmt.Printf("%v:", rl.Priority)
+		if rl.InvertRule {
+			fmt.Printf(" not")
+		}
+
+		from := rl.From
+		if validator.IsEmpty(from) {
+			from = "all"
+		}
+		fmt.Printf(" %v %v", color.HiBlueString("from"), from)
+
+		for _, kv := range []struct {
+			key   string
+			value string
+		}{
+			{"to", rl.To},
+			{"tos", rl.TypeOfService},
+			{"fwmark", rl.FirewallMark},
+			{"iif", rl.IncomingInterface},
+			{"oif", rl.OutgoingInterface},
+			{"uidrange", rl.User},
+			{"ipproto", rl.IPProtocol},
+			{"sport", rl.SourcePort},
+			{"dport", rl.DestinationPort},
+			{"lookup", rl.Table},
+			{"goto", rl.Goto},
+			{"suppress_prefixlength", rl.SuppressPrefixLength},
+			{"suppress_ifgroup", rl.SuppressInterfaceGroup},
+			{"proto", rl.Protocol},
+		} {
+			if !validator.IsEmpty(kv.value) {
+				fmt.Printf(" %v %v", color.HiBlueString(kv.key), kv.value)
+			}
+		}
+
+		if rl.Type != "table" && rl.Type != "goto" {
+			fmt.Printf(" %v", rl.Type)
+		}
+		fmt.Printf("\n")
+	}
+}
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/link"
+)
+
+type LinkInfoStats struct {
+	Success bool          `json:"success"`
+	Message link.LinkInfo `json:"message"`
+	Errors  string        `json:"errors"`
+}
+
+func parseRuntimeLink(args cli.Args) (*link.Link, error) {
+	argStrings := args.Slice()
+
+	l := link.Link{
+		Name: argStrings[0],
+	}
+	for i := 1; i < len(argStrings); i++ {
+		switch argStrings[i] {
+		case "up", "down":
+			l.Action = argStrings[i]
+			continue
+		case "nomaster":
+			l.NoMaster = true
+			continue
+		}
+
+		if i+1 >= len(argStrings) {
+			return nil, fmt.Errorf("Missing value of %s\n", argStrings[i])
+		}
+
+		switch argStrings[i] {
+		case "mtu":
+			if !validator.IsUint32(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid mtu=%s\n", argStrings[i+1])
+			}
+			l.MTU = argStrings[i+1]
+		case "txqlen":
+			if !validator.IsUint32(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid txqlen=%s\n", argStrings[i+1])
+			}
+			l.TxQueueLength = argStrings[i+1]
+		case "alias":
+			l.Alias = argStrings[i+1]
+		case "mac":
+			if validator.IsNotMAC(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid mac=%s\n", argStrings[i+1])
+			}
+			l.MACAddress = argStrings[i+1]
+		case "promisc":
+			if !validator.IsBool(argStrings[i+1]) {
+				return nil, fmt.Errorf("Invalid promisc=%s\n", argStrings[i+1])
+			}
+			l.Promiscuous = validator.BoolToString(argStrings[i+1])
+		case "master":
+			l.Master = argStrings[i+1]
+		case "enslave":
+			l.Enslave = strings.Split(argStrings[i+1], ",")
+		case "netns":
+			l.Namespace = argStrings[i+1]
+		default:
+			return nil, fmt.Errorf("Unknown option %s\n", argStrings[i])
+		}
+		i++
+	}
+
+	return &l, nil
+}
+
+func networkConfigureRuntimeLink(args cli.Args, host string, token map[string]string) {
+	l, err := parseRuntimeLink(args)
+	if err != nil {
+		fmt.Printf("%v", err)
+		return
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, host, netnsPath("/api/v1/network/link/"+l.Name), token, l)
+	if err != nil {
+		fmt.Printf("Failed to configure link: %v\n", err)
+		return
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		fmt.Printf("Failed to decode json message: %v\n", err)
+		return
+	}
+
+	if !m.Success {
+		fmt.Printf("Failed to configure link: %v\n", m.Errors)
+	}
+}
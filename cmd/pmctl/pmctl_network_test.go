@@ -4,9 +4,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"os"
+	"path"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -17,6 +20,12 @@ import (
 	"github.com/vmware/pmd-next-gen/pkg/system"
 	"github.com/vmware/pmd-next-gen/pkg/validator"
 	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/diag"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/address"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/link"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/neighbor"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/route"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/rule"
 	"github.com/vmware/pmd-next-gen/plugins/network/networkd"
 	"github.com/vmware/pmd-next-gen/plugins/network/resolved"
 )
@@ -549,6 +558,96 @@ func TestNetworkAddress(t *testing.T) {
 	}
 }
 
+func TestNetworkAddressOptions(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		AddressSections: []networkd.AddressSection{
+			{
+				Address:                   "192.168.1.15/24",
+				PreferredLifetime:         "0",
+				DuplicateAddressDetection: "ipv4",
+				AddPrefixRoute:            "no",
+				RouteMetric:               "200",
+			},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure Address: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("Address", "PreferredLifetime") != "0" {
+		t.Fatalf("Failed to set PreferredLifetime")
+	}
+	if m.GetKeySectionString("Address", "DuplicateAddressDetection") != "ipv4" {
+		t.Fatalf("Failed to set DuplicateAddressDetection")
+	}
+	if m.GetKeySectionString("Address", "AddPrefixRoute") != "no" {
+		t.Fatalf("Failed to set AddPrefixRoute")
+	}
+	if m.GetKeySectionString("Address", "RouteMetric") != "200" {
+		t.Fatalf("Failed to set RouteMetric")
+	}
+}
+
+func TestNetworkRouteOptions(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		AddressSections: []networkd.AddressSection{
+			{
+				Address: "192.168.1.15/24",
+			},
+		},
+		RouteSections: []networkd.RouteSection{
+			{
+				Destination:             "10.10.0.0/16",
+				MultiPathRoute:          []string{"192.168.1.1@test99 10", "192.168.1.2@test99 20"},
+				Metric:                  "100",
+				Protocol:                "static",
+				MTUBytes:                "1400",
+				InitialCongestionWindow: "10",
+				QuickAck:                "yes",
+			},
+			{
+				Destination: "10.20.0.0/16",
+				Type:        "blackhole",
+			},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure Route: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	s := m.Sections("Route")
+	if len(s) != 2 {
+		t.Fatalf("Failed to set Route sections")
+	}
+
+	mp := s[0].Key("MultiPathRoute").ValueWithShadows()
+	if len(mp) != 2 || mp[1] != "192.168.1.2@test99 20" {
+		t.Fatalf("Failed to set MultiPathRoute: %v", mp)
+	}
+	if s[0].Key("Metric").String() != "100" || s[0].Key("Protocol").String() != "static" ||
+		s[0].Key("MTUBytes").String() != "1400" || s[0].Key("InitialCongestionWindow").String() != "10" ||
+		s[0].Key("QuickAck").String() != "yes" {
+		t.Fatalf("Failed to set Route options")
+	}
+	if s[1].Key("Type").String() != "blackhole" {
+		t.Fatalf("Failed to set Type")
+	}
+}
+
 func TestNetworkLinkMode(t *testing.T) {
 	setupLink(t, &netlink.Dummy{netlink.LinkAttrs{Name: "test99"}})
 	defer removeLink(t, "test99")
@@ -1090,6 +1189,83 @@ func TestNetworkConfigureRemoveDHCPv4Server(t *testing.T) {
 	}
 }
 
+func TestNetworkDHCPv4ServerStaticLease(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		NetworkSection: networkd.NetworkSection{
+			DHCPServer: "yes",
+		},
+		AddressSections: []networkd.AddressSection{
+			{
+				Address: "192.168.50.1/24",
+			},
+		},
+		DHCPv4ServerSection: networkd.DHCPv4ServerSection{
+			ServerAddress:     "192.168.50.1/24",
+			BootServerAddress: "192.168.50.2",
+			BootFilename:      "pxelinux.0",
+		},
+		DHCPServerStaticLeases: []networkd.DHCPServerStaticLeaseSection{
+			{
+				MACAddress: "00:11:22:33:44:55",
+				Address:    "192.168.50.10",
+			},
+			{
+				MACAddress: "00:11:22:33:44:66",
+				Address:    "192.168.50.11",
+			},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure DHCPServer: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("DHCPServer", "BootFilename") != "pxelinux.0" {
+		t.Fatalf("Failed to set BootFilename")
+	}
+	if len(m.Sections("DHCPServerStaticLease")) != 2 {
+		t.Fatalf("Failed to set DHCPServerStaticLease")
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, "", "/api/v1/network/networkd/test99/dhcpserver/leases", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire DHCPServer leases: %v\n", err)
+	}
+
+	j := DHCPServerLeasesStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire DHCPServer leases: %v\n", j.Errors)
+	}
+
+	n = networkd.Network{
+		Link: "test99",
+		DHCPServerStaticLeases: []networkd.DHCPServerStaticLeaseSection{
+			{
+				MACAddress: "00:11:22:33:44:55",
+			},
+		},
+	}
+
+	m, err = removeNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to remove DHCPServerStaticLease: %v\n", err)
+	}
+
+	s := m.Sections("DHCPServerStaticLease")
+	if len(s) != 1 || s[0].Key("MACAddress").String() != "00:11:22:33:44:66" {
+		t.Fatalf("Failed to remove DHCPServerStaticLease")
+	}
+}
+
 func TestNetworkConfigureIPv6SendRA(t *testing.T) {
 	setupLink(t, &netlink.Dummy{netlink.LinkAttrs{Name: "test99"}})
 	defer removeLink(t, "test99")
@@ -1453,3 +1629,1210 @@ func TestNetworkSRIOV(t *testing.T) {
 		t.Fatalf("Failed to set MACAddress")
 	}
 }
+
+func TestNetworkDropinConfigureAndReset(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	system.ExecRun("systemctl", "restart", "systemd-networkd")
+	time.Sleep(time.Second * 3)
+
+	n := networkd.Network{
+		Link:   "test99",
+		DropIn: true,
+		NetworkSection: networkd.NetworkSection{
+			DHCP: "ipv4",
+		},
+	}
+
+	base, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure network drop-in: %v\n", err)
+	}
+	defer os.Remove(base.Path)
+
+	if base.GetKeySectionString("Network", "DHCP") == "ipv4" {
+		t.Fatalf("DHCP written to .network file instead of the drop-in")
+	}
+
+	dropin := path.Join(base.Path+".d", "50-photon-mgmt.conf")
+	m, err := configfile.Load(dropin)
+	if err != nil {
+		t.Fatalf("Failed to load network drop-in: %v\n", err)
+	}
+
+	if m.GetKeySectionString("Network", "DHCP") != "ipv4" {
+		t.Fatalf("Failed to set DHCP in drop-in")
+	}
+
+	resp, err := web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/networkd/network/test99/dropin", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to reset network drop-in: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to reset network drop-in: %v\n", j.Errors)
+	}
+
+	if system.PathExists(dropin) {
+		t.Fatalf("Network drop-in still exists after reset")
+	}
+}
+
+func TestNetworkReplace(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	system.ExecRun("systemctl", "restart", "systemd-networkd")
+	time.Sleep(time.Second * 3)
+
+	n := networkd.Network{
+		Link: "test99",
+		NetworkSection: networkd.NetworkSection{
+			DHCP: "ipv4",
+			DNS:  []string{"192.168.1.2"},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure network: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	n = networkd.Network{
+		NetworkSection: networkd.NetworkSection{
+			DNS: []string{"192.168.1.3"},
+		},
+		AddressSections: []networkd.AddressSection{
+			{
+				Address: "192.168.1.10/24",
+			},
+		},
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPut, "", "/api/v1/network/networkd/network/test99", nil, n)
+	if err != nil {
+		t.Fatalf("Failed to replace network: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to replace network: %v\n", j.Errors)
+	}
+
+	m, err = configfile.Load(m.Path)
+	if err != nil {
+		t.Fatalf("Failed to load network file: %v\n", err)
+	}
+
+	if m.GetKeySectionString("Match", "Name") != "test99" {
+		t.Fatalf("Match section not preserved")
+	}
+	if m.GetKeySectionString("Network", "DHCP") != "" {
+		t.Fatalf("DHCP not removed on replace")
+	}
+	if m.GetKeySectionString("Network", "DNS") != "192.168.1.3" {
+		t.Fatalf("Failed to replace DNS")
+	}
+	if m.GetKeySectionString("Address", "Address") != "192.168.1.10/24" {
+		t.Fatalf("Failed to set Address")
+	}
+
+	n.NetworkSection.DNS = []string{"invalid"}
+	resp, err = web.DispatchSocket(http.MethodPut, "", "/api/v1/network/networkd/network/test99", nil, n)
+	if err != nil {
+		t.Fatalf("Failed to replace network: %v\n", err)
+	}
+
+	j = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if j.Success {
+		t.Fatalf("Invalid network document accepted")
+	}
+
+	m, err = configfile.Load(m.Path)
+	if err != nil {
+		t.Fatalf("Failed to load network file: %v\n", err)
+	}
+	if m.GetKeySectionString("Network", "DNS") != "192.168.1.3" {
+		t.Fatalf("Network file modified by invalid document")
+	}
+}
+
+func TestNetworkMatchMACAddress(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	system.ExecRun("systemctl", "restart", "systemd-networkd")
+	time.Sleep(time.Second * 3)
+
+	link, err := netlink.LinkByName("test99")
+	if err != nil {
+		t.Fatalf("Failed to find link: %v\n", err)
+	}
+	mac := link.Attrs().HardwareAddr.String()
+
+	n := networkd.Network{
+		Link: "test99",
+		MatchSection: networkd.MatchSection{
+			MACAddress: mac,
+			Driver:     "dummy",
+			Property:   []string{"ID_NET_DRIVER=dummy"},
+		},
+		NetworkSection: networkd.NetworkSection{
+			DHCP: "ipv4",
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure network: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("Match", "Name") != "" {
+		t.Fatalf("Name still set in Match section")
+	}
+	if m.GetKeySectionString("Match", "MACAddress") != mac {
+		t.Fatalf("Failed to set MACAddress in Match section")
+	}
+	if m.GetKeySectionString("Match", "Driver") != "dummy" {
+		t.Fatalf("Failed to set Driver in Match section")
+	}
+	if m.GetKeySectionString("Match", "Property") != "ID_NET_DRIVER=dummy" {
+		t.Fatalf("Failed to set Property in Match section")
+	}
+
+	n.MatchSection.MACAddress = "invalid"
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/networkd/network/configure", nil, n)
+	if err != nil {
+		t.Fatalf("Failed to configure network: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if j.Success {
+		t.Fatalf("Invalid MACAddress accepted in Match section")
+	}
+}
+
+func TestNetworkBridgePortAndVLAN(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	system.ExecRun("systemctl", "restart", "systemd-networkd")
+	time.Sleep(time.Second * 3)
+
+	n := networkd.Network{
+		Link: "test99",
+		NetworkSection: networkd.NetworkSection{
+			Bridge: "br99",
+		},
+		BridgePortSection: networkd.BridgePortSection{
+			Cost:     "10",
+			Priority: "32",
+			HairPin:  "yes",
+			Learning: "no",
+		},
+		BridgeVLANSections: []networkd.BridgeVLANSection{
+			{
+				VLAN:           "100-200",
+				PVID:           "100",
+				EgressUntagged: "100",
+			},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure bridge port: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("Network", "Bridge") != "br99" {
+		t.Fatalf("Failed to set Bridge")
+	}
+	if m.GetKeySectionString("Bridge", "Cost") != "10" {
+		t.Fatalf("Failed to set Cost")
+	}
+	if m.GetKeySectionString("Bridge", "Priority") != "32" {
+		t.Fatalf("Failed to set Priority")
+	}
+	if m.GetKeySectionString("Bridge", "HairPin") != "yes" {
+		t.Fatalf("Failed to set HairPin")
+	}
+	if m.GetKeySectionString("Bridge", "Learning") != "no" {
+		t.Fatalf("Failed to set Learning")
+	}
+	if m.GetKeySectionString("BridgeVLAN", "VLAN") != "100-200" {
+		t.Fatalf("Failed to set VLAN")
+	}
+	if m.GetKeySectionString("BridgeVLAN", "PVID") != "100" {
+		t.Fatalf("Failed to set PVID")
+	}
+	if m.GetKeySectionString("BridgeVLAN", "EgressUntagged") != "100" {
+		t.Fatalf("Failed to set EgressUntagged")
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netlink/bridge/vlan", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire bridge vlan: %v\n", err)
+	}
+
+	j := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire bridge vlan: %v\n", j.Errors)
+	}
+}
+
+func configureNetworkAndWait(t *testing.T, n networkd.Network) *networkd.LinkWaitState {
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/networkd/network/configure", nil, n)
+	if err != nil {
+		t.Fatalf("Failed to configure network: %v\n", err)
+	}
+
+	j := LinkWaitStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to configure network: %v\n", j.Errors)
+	}
+
+	return &j.Message
+}
+
+func TestNetworkConfigureWait(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		AddressSections: []networkd.AddressSection{
+			{
+				Address: "192.168.1.15/24",
+			},
+		},
+		Wait: networkd.WaitSection{
+			OperationalState: "routable",
+			TimeoutSec:       20,
+		},
+	}
+
+	s := configureNetworkAndWait(t, n)
+	defer func() {
+		if f, err := networkd.AcquireLinkNetworkFile("test99"); err == nil {
+			os.Remove(f)
+		}
+	}()
+
+	if !s.Reached || s.OperationalState != "routable" {
+		t.Fatalf("Link test99 did not reach routable: %v", s.OperationalState)
+	}
+}
+
+func TestNetworkConfigureWaitAutoRevert(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		NetworkSection: networkd.NetworkSection{
+			LinkLocalAddressing: "no",
+		},
+		Wait: networkd.WaitSection{
+			OperationalState: "routable",
+			TimeoutSec:       5,
+			AutoRevert:       true,
+		},
+	}
+
+	s := configureNetworkAndWait(t, n)
+	if s.Reached || !s.Reverted {
+		t.Fatalf("Failed to revert configuration of test99")
+	}
+
+	if system.PathExists("/etc/systemd/network/10-test99.network") {
+		os.Remove("/etc/systemd/network/10-test99.network")
+		t.Fatalf("Failed to remove .network file of test99 on revert")
+	}
+}
+
+func TestNetworkAcquireDHCPLease(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	l, err := netlink.LinkByName("test99")
+	if err != nil {
+		t.Fatalf("Failed to find link test99: %v\n", err)
+	}
+
+	lease := path.Join("/run/systemd/netif/leases", strconv.Itoa(l.Attrs().Index))
+	data := "ADDRESS=192.168.1.20\nNETMASK=255.255.255.0\nROUTER=192.168.1.1\nSERVER_ADDRESS=192.168.1.1\nT1=1800\nT2=3150\nLIFETIME=3600\nDNS=192.168.1.1 8.8.8.8\nDOMAINNAME=example.com\n"
+	if err := os.WriteFile(lease, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write lease: %v\n", err)
+	}
+	defer os.Remove(lease)
+
+	resp, err := web.DispatchSocket(http.MethodGet, "", "/api/v1/network/networkd/test99/lease", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire DHCP lease: %v\n", err)
+	}
+
+	j := DHCPLeaseStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire DHCP lease: %v\n", j.Errors)
+	}
+
+	if j.Message.Address != "192.168.1.20" || j.Message.Netmask != "255.255.255.0" {
+		t.Fatalf("Invalid lease address: %v/%v", j.Message.Address, j.Message.Netmask)
+	}
+	if len(j.Message.Router) != 1 || j.Message.Router[0] != "192.168.1.1" {
+		t.Fatalf("Invalid lease router: %v", j.Message.Router)
+	}
+	if len(j.Message.DNS) != 2 || j.Message.DNS[1] != "8.8.8.8" {
+		t.Fatalf("Invalid lease DNS: %v", j.Message.DNS)
+	}
+	if j.Message.LifetimeSec != 3600 || j.Message.T1Sec != 1800 || j.Message.T2Sec != 3150 {
+		t.Fatalf("Invalid lease timers: %v %v %v", j.Message.LifetimeSec, j.Message.T1Sec, j.Message.T2Sec)
+	}
+}
+
+func TestNetworkNextHopGroup(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		AddressSections: []networkd.AddressSection{
+			{
+				Address: "192.168.1.15/24",
+			},
+		},
+		NextHopSections: []networkd.NextHopSection{
+			{
+				Id:      "901",
+				Gateway: "192.168.1.1",
+			},
+			{
+				Id:      "902",
+				Gateway: "192.168.1.2",
+			},
+			{
+				Id:    "903",
+				Group: []string{"901:10", "902:20"},
+			},
+		},
+		RouteSections: []networkd.RouteSection{
+			{
+				Destination: "10.30.0.0/16",
+				NextHop:     "903",
+			},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure NextHop: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	if len(m.Sections("NextHop")) != 3 {
+		t.Fatalf("Failed to set NextHop sections")
+	}
+	if m.Sections("NextHop")[2].Key("Group").String() != "901:10 902:20" {
+		t.Fatalf("Failed to set NextHop Group")
+	}
+	if m.GetKeySectionString("Route", "NextHop") != "903" {
+		t.Fatalf("Failed to set Route NextHop")
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netlink/route/nexthop", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire nexthops: %v\n", err)
+	}
+
+	j := NextHopStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire nexthops: %v\n", j.Errors)
+	}
+
+	found := false
+	for _, nh := range j.Message {
+		if nh.Id == 903 && len(nh.Group) == 2 && nh.Group[1].Weight == 20 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("NextHop group 903 not found in kernel")
+	}
+}
+
+func TestNetworkTrafficControlHTB(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		TrafficControl: networkd.TrafficControlSections{
+			HierarchyTokenBucketSections: []networkd.HierarchyTokenBucketSection{
+				{
+					Parent:       "root",
+					Handle:       "1:",
+					DefaultClass: "10",
+				},
+			},
+			HierarchyTokenBucketClassSections: []networkd.HierarchyTokenBucketClassSection{
+				{
+					Parent:   "1:",
+					ClassId:  "1:10",
+					Rate:     "10M",
+					CeilRate: "20M",
+				},
+			},
+			FairQueueingControlledDelaySections: []networkd.FairQueueingControlledDelaySection{
+				{
+					Parent:    "1:10",
+					Handle:    "10:",
+					TargetSec: "5ms",
+				},
+			},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure qdisc: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("HierarchyTokenBucket", "Handle") != "1:" {
+		t.Fatalf("Failed to set HierarchyTokenBucket")
+	}
+	if m.GetKeySectionString("HierarchyTokenBucketClass", "Rate") != "10M" {
+		t.Fatalf("Failed to set HierarchyTokenBucketClass")
+	}
+	if m.GetKeySectionString("FairQueueingControlledDelay", "TargetSec") != "5ms" {
+		t.Fatalf("Failed to set FairQueueingControlledDelay")
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netlink/qdisc/test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire qdisc: %v\n", err)
+	}
+
+	j := TrafficControlStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire qdisc: %v\n", j.Errors)
+	}
+
+	kinds := make(map[string]bool)
+	for _, q := range j.Message.QDiscs {
+		kinds[q.Kind] = true
+	}
+	if !kinds["htb"] || !kinds["fq_codel"] {
+		t.Fatalf("Failed to find htb and fq_codel qdiscs: %v", j.Message.QDiscs)
+	}
+	if len(j.Message.Classes) == 0 || j.Message.Classes[0].Handle != "1:10" {
+		t.Fatalf("Failed to find htb class 1:10: %v", j.Message.Classes)
+	}
+}
+
+func TestNetworkTrafficControlHTBRootClass(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	n := networkd.Network{
+		Link: "test99",
+		TrafficControl: networkd.TrafficControlSections{
+			HierarchyTokenBucketSections: []networkd.HierarchyTokenBucketSection{
+				{
+					Parent: "root",
+					Handle: "1:",
+				},
+			},
+			HierarchyTokenBucketClassSections: []networkd.HierarchyTokenBucketClassSection{
+				{
+					Parent:  "root",
+					ClassId: "1:1",
+					Rate:    "10M",
+				},
+			},
+		},
+	}
+
+	m, err := configureNetwork(t, n)
+	if err != nil {
+		t.Fatalf("Failed to configure qdisc: %v\n", err)
+	}
+	defer os.Remove(m.Path)
+
+	if m.GetKeySectionString("HierarchyTokenBucketClass", "Parent") != "root" {
+		t.Fatalf("Failed to set HierarchyTokenBucketClass with Parent=root")
+	}
+}
+
+func TestNetworkRuntimeAddress(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	a := address.AddressAction{
+		Link: "test99",
+		Address: address.Address{
+			IP:          "192.168.50.10/24",
+			Label:       "test99:1",
+			ScopeName:   "link",
+			PreferedLft: 300,
+			ValidLft:    600,
+			FlagNames:   []string{"noprefixroute"},
+		},
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/netlink/address/test99", nil, a)
+	if err != nil {
+		t.Fatalf("Failed to add address: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to add address: %v\n", m.Errors)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netlink/address/test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire addresses: %v\n", err)
+	}
+
+	j := LinkAddressStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire addresses: %v\n", j.Errors)
+	}
+
+	found := false
+	for _, addr := range j.Message.Addresses {
+		if addr.IP == "192.168.50.10" && addr.Label == "test99:1" && addr.ScopeName == "link" && addr.ValidLft <= 600 && share.StringContains(addr.FlagNames, "noprefixroute") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Runtime address not found on test99")
+	}
+
+	a.Address = address.Address{IP: "192.168.50.10"}
+	resp, err = web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/netlink/address/test99", nil, a)
+	if err != nil {
+		t.Fatalf("Failed to remove address: %v\n", err)
+	}
+
+	m = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to remove address: %v\n", m.Errors)
+	}
+}
+
+func TestNetworkRuntimeRoute(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	l, err := netlink.LinkByName("test99")
+	if err != nil {
+		t.Fatalf("Failed to find link test99: %v\n", err)
+	}
+	if err := netlink.LinkSetUp(l); err != nil {
+		t.Fatalf("Failed to set link test99 up: %v\n", err)
+	}
+
+	for _, rt := range []route.Route{
+		{
+			Action:      "add",
+			Destination: "10.90.0.0/16",
+			Link:        "test99",
+			Table:       "100",
+			Metric:      300,
+			Protocol:    "static",
+		},
+		{
+			Action:      "add",
+			Destination: "10.91.0.0/16",
+			Type:        "blackhole",
+		},
+	} {
+		resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/netlink/route", nil, rt)
+		if err != nil {
+			t.Fatalf("Failed to add route: %v\n", err)
+		}
+
+		m := web.JSONResponseMessage{}
+		if err := json.Unmarshal(resp, &m); err != nil {
+			t.Fatalf("Failed to decode json message: %v\n", err)
+		}
+		if !m.Success {
+			t.Fatalf("Failed to add route: %v\n", m.Errors)
+		}
+	}
+
+	resp, err := web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netlink/route?table=100&link=test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire routes: %v\n", err)
+	}
+
+	j := RouteStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success || len(j.Message) != 1 {
+		t.Fatalf("Failed to acquire routes of table 100: %v\n", j.Errors)
+	}
+	if j.Message[0].Dst.IP != "10.90.0.0" || j.Message[0].ProtocolName != "static" || j.Message[0].Priority != 300 || j.Message[0].ScopeName != "link" {
+		t.Fatalf("Route in table 100 does not match: %+v\n", j.Message[0])
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netlink/route/get?destination=10.91.1.1", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to get route: %v\n", err)
+	}
+
+	j = RouteStats{}
+	if err := json.Unmarshal(resp, &j); err == nil && j.Success {
+		t.Fatalf("Route lookup of blackhole destination succeeded: %+v\n", j.Message)
+	}
+
+	for _, rt := range []route.Route{
+		{
+			Destination: "10.90.0.0/16",
+			Table:       "100",
+		},
+		{
+			Destination: "10.91.0.0/16",
+			Type:        "blackhole",
+		},
+	} {
+		resp, err := web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/netlink/route", nil, rt)
+		if err != nil {
+			t.Fatalf("Failed to remove route: %v\n", err)
+		}
+
+		m := web.JSONResponseMessage{}
+		if err := json.Unmarshal(resp, &m); err != nil {
+			t.Fatalf("Failed to decode json message: %v\n", err)
+		}
+		if !m.Success {
+			t.Fatalf("Failed to remove route: %v\n", m.Errors)
+		}
+	}
+}
+
+func TestNetworkRuntimeRule(t *testing.T) {
+	r := rule.Rule{
+		Priority:        "1201",
+		From:            "10.80.0.0/16",
+		FirewallMark:    "0x10/0xff",
+		Table:           "100",
+		User:            "1000-2000",
+		IPProtocol:      "tcp",
+		DestinationPort: "443",
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/rule", nil, r)
+	if err != nil {
+		t.Fatalf("Failed to add rule: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to add rule: %v\n", m.Errors)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/rule?family=ipv4", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire rules: %v\n", err)
+	}
+
+	j := RuleStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire rules: %v\n", j.Errors)
+	}
+
+	found := false
+	for _, rl := range j.Message {
+		if rl.Priority == "1201" && rl.From == "10.80.0.0/16" && rl.FirewallMark == "0x10/0xff" && rl.Table == "100" &&
+			rl.User == "1000-2000" && rl.IPProtocol == "tcp" && rl.DestinationPort == "443" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Rule 1201 not found in kernel")
+	}
+
+	resp, err = web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/rule", nil, rule.Rule{Priority: "1201"})
+	if err != nil {
+		t.Fatalf("Failed to remove rule: %v\n", err)
+	}
+
+	m = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to remove rule: %v\n", m.Errors)
+	}
+}
+
+func TestNetworkNeighbor(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	nb := neighbor.Neighbor{
+		Link:       "test99",
+		IP:         "192.168.99.10",
+		MACAddress: "00:11:22:33:44:55",
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/netlink/neighbor", nil, nb)
+	if err != nil {
+		t.Fatalf("Failed to add neighbor: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to add neighbor: %v\n", m.Errors)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netlink/neighbor?link=test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire neighbors: %v\n", err)
+	}
+
+	j := NeighborStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire neighbors: %v\n", j.Errors)
+	}
+
+	found := false
+	for _, n := range j.Message {
+		if n.IP == "192.168.99.10" && n.MACAddress == "00:11:22:33:44:55" && n.State == "PERMANENT" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Neighbor 192.168.99.10 not found in kernel")
+	}
+
+	resp, err = web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/netlink/neighbor", nil, nb)
+	if err != nil {
+		t.Fatalf("Failed to remove neighbor: %v\n", err)
+	}
+
+	m = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to remove neighbor: %v\n", m.Errors)
+	}
+}
+
+func TestNetworkRuntimeLink(t *testing.T) {
+	setupLink(t, &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: "br99"}})
+	defer removeLink(t, "br99")
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	l := link.Link{
+		Action:        "up",
+		MTU:           "1400",
+		TxQueueLength: "500",
+		Alias:         "uplink",
+		Master:        "br99",
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/link/test99", nil, l)
+	if err != nil {
+		t.Fatalf("Failed to configure link: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to configure link: %v\n", m.Errors)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/link/test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire link: %v\n", err)
+	}
+
+	j := LinkInfoStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire link: %v\n", j.Errors)
+	}
+
+	br, err := netlink.LinkByName("br99")
+	if err != nil {
+		t.Fatalf("Failed to find br99: %v\n", err)
+	}
+
+	if j.Message.Mtu != 1400 {
+		t.Fatalf("Failed to set MTU")
+	}
+	if j.Message.TxQLen != 500 {
+		t.Fatalf("Failed to set TxQLen")
+	}
+	if j.Message.Alias != "uplink" {
+		t.Fatalf("Failed to set Alias")
+	}
+	if j.Message.MasterIndex != br.Attrs().Index {
+		t.Fatalf("Failed to set Master")
+	}
+	if !strings.Contains(j.Message.Flags, "up") {
+		t.Fatalf("Failed to bring link up")
+	}
+}
+
+func TestNetworkNamespace(t *testing.T) {
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/netns/test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to add network namespace: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to add network namespace: %v\n", m.Errors)
+	}
+	defer web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/netns/test99", nil, nil)
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/netns", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire network namespaces: %v\n", err)
+	}
+
+	n := NamespaceStats{}
+	if err := json.Unmarshal(resp, &n); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !n.Success {
+		t.Fatalf("Failed to acquire network namespaces: %v\n", n.Errors)
+	}
+
+	found := false
+	for _, ns := range n.Message {
+		if ns.Name == "test99" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Failed to find network namespace test99")
+	}
+
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+
+	resp, err = web.DispatchSocket(http.MethodPost, "", "/api/v1/network/link/test99", nil, link.Link{Namespace: "test99"})
+	if err != nil {
+		t.Fatalf("Failed to move link: %v\n", err)
+	}
+
+	m = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to move link: %v\n", m.Errors)
+	}
+
+	if _, err := netlink.LinkByName("test99"); err == nil {
+		t.Fatalf("Link test99 still in the host namespace")
+	}
+
+	a := address.AddressAction{
+		Address: address.Address{
+			IP: "192.168.99.1/24",
+		},
+	}
+
+	resp, err = web.DispatchSocket(http.MethodPost, "", "/api/v1/network/netlink/address/test99?netns=test99", nil, a)
+	if err != nil {
+		t.Fatalf("Failed to add address: %v\n", err)
+	}
+
+	m = web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to add address: %v\n", m.Errors)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/link/test99?netns=test99", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire link: %v\n", err)
+	}
+
+	j := LinkInfoStats{}
+	if err := json.Unmarshal(resp, &j); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !j.Success {
+		t.Fatalf("Failed to acquire link in network namespace: %v\n", j.Errors)
+	}
+}
+
+func TestNetworkDiagPing(t *testing.T) {
+	p := diag.Ping{
+		Address:      "127.0.0.1",
+		Count:        2,
+		IntervalMSec: 200,
+	}
+
+	resp, err := web.DispatchAndWait(http.MethodPost, "", "/api/v1/network/diag/ping", nil, p)
+	if err != nil {
+		t.Fatalf("Failed to ping: %v\n", err)
+	}
+
+	s := PingStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !s.Success {
+		t.Fatalf("Failed to ping: %v\n", s.Errors)
+	}
+
+	if s.Message.Transmitted != 2 || s.Message.Received != 2 {
+		t.Fatalf("Failed to receive echo replies: %d/%d\n", s.Message.Received, s.Message.Transmitted)
+	}
+}
+
+func TestNetworkDiagPortProbe(t *testing.T) {
+	p := diag.PortProbe{
+		Address:    "127.0.0.1",
+		Port:       1,
+		TimeoutSec: 1,
+	}
+
+	resp, err := web.DispatchAndWait(http.MethodPost, "", "/api/v1/network/diag/probe", nil, p)
+	if err != nil {
+		t.Fatalf("Failed to probe port: %v\n", err)
+	}
+
+	s := PortProbeStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !s.Success {
+		t.Fatalf("Failed to probe port: %v\n", s.Errors)
+	}
+
+	if s.Message.Open {
+		t.Fatalf("Port 1 reported open\n")
+	}
+}
+
+func TestNetworkRuntimeLinkDns(t *testing.T) {
+	setupLink(t, &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Name: "test99"}})
+	defer removeLink(t, "test99")
+
+	d := resolved.LinkDns{
+		DnsServers: []string{"192.168.1.53", "fe80::53"},
+		Domains:    []string{"test1.com", "~test2.com"},
+		LLMNR:      "no",
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/resolved/test99/configure", nil, d)
+	if err != nil {
+		t.Fatalf("Failed to configure link DNS: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to configure link DNS: %v\n", m.Errors)
+	}
+
+	l, err := netlink.LinkByName("test99")
+	if err != nil {
+		t.Fatalf("Failed to acquire link: %v\n", err)
+	}
+
+	c, err := resolved.NewSDConnection()
+	if err != nil {
+		t.Fatalf("Failed to establish connection to the system bus: %v\n", err)
+	}
+	defer c.Close()
+
+	dns, err := c.DBusAcquireDnsFromResolveLink(context.Background(), l.Attrs().Index)
+	if err != nil {
+		t.Fatalf("Failed to acquire link DNS: %v\n", err)
+	}
+	if len(dns) != 2 || dns[0].Dns != "192.168.1.53" {
+		t.Fatalf("Failed to configure link DNS: %v\n", dns)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodPost, "", "/api/v1/network/resolved/test99/revert", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to revert link DNS: %v\n", err)
+	}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to revert link DNS: %v\n", m.Errors)
+	}
+
+	dns, err = c.DBusAcquireDnsFromResolveLink(context.Background(), l.Attrs().Index)
+	if err != nil {
+		t.Fatalf("Failed to acquire link DNS: %v\n", err)
+	}
+	if len(dns) != 0 {
+		t.Fatalf("Failed to revert link DNS: %v\n", dns)
+	}
+}
+
+func TestNetworkResolvedConfig(t *testing.T) {
+	c := resolved.ResolvedConfig{
+		FallbackDNS:  []string{"192.168.1.53", "192.168.1.54"},
+		DNSSEC:       "allow-downgrade",
+		Cache:        "no-negative",
+		ReadEtcHosts: "no",
+	}
+
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/resolved/config", nil, c)
+	if err != nil {
+		t.Fatalf("Failed to configure resolved: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to configure resolved: %v\n", m.Errors)
+	}
+	defer web.DispatchSocket(http.MethodDelete, "", "/api/v1/network/resolved/config", nil, nil)
+
+	f, err := configfile.Load("/etc/systemd/resolved.conf.d/50-photon-mgmt.conf")
+	if err != nil {
+		t.Fatalf("Failed to load resolved.conf drop-in: %v\n", err)
+	}
+	if f.GetKeySectionString("Resolve", "FallbackDNS") != "192.168.1.53 192.168.1.54" {
+		t.Fatalf("Failed to set FallbackDNS\n")
+	}
+	if f.GetKeySectionString("Resolve", "DNSSEC") != "allow-downgrade" {
+		t.Fatalf("Failed to set DNSSEC\n")
+	}
+	if f.GetKeySectionString("Resolve", "ReadEtcHosts") != "no" {
+		t.Fatalf("Failed to set ReadEtcHosts\n")
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/resolved/config", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire resolved configuration: %v\n", err)
+	}
+
+	s := ResolvedConfigStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !s.Success {
+		t.Fatalf("Failed to acquire resolved configuration: %v\n", s.Errors)
+	}
+	if s.Message.Configured.Cache != "no-negative" {
+		t.Fatalf("Failed to report configured Cache: %v\n", s.Message.Configured)
+	}
+}
+
+func TestNetworkResolvedStatistics(t *testing.T) {
+	resp, err := web.DispatchSocket(http.MethodPost, "", "/api/v1/network/resolved/statistics/reset", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to reset resolved statistics: %v\n", err)
+	}
+
+	m := web.JSONResponseMessage{}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to reset resolved statistics: %v\n", m.Errors)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodGet, "", "/api/v1/network/resolved/statistics", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to acquire resolved statistics: %v\n", err)
+	}
+
+	s := ResolvedStatisticsStats{}
+	if err := json.Unmarshal(resp, &s); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !s.Success {
+		t.Fatalf("Failed to acquire resolved statistics: %v\n", s.Errors)
+	}
+
+	if s.Message.Cache.Hits != 0 || s.Message.Cache.Misses != 0 {
+		t.Fatalf("Failed to reset cache statistics: %v\n", s.Message.Cache)
+	}
+
+	resp, err = web.DispatchSocket(http.MethodPost, "", "/api/v1/network/resolved/flushcaches", nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to flush resolved caches: %v\n", err)
+	}
+	if err := json.Unmarshal(resp, &m); err != nil {
+		t.Fatalf("Failed to decode json message: %v\n", err)
+	}
+	if !m.Success {
+		t.Fatalf("Failed to flush resolved caches: %v\n", m.Errors)
+	}
+}
@@ -5,12 +5,17 @@ package configfile
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/go-ini/ini"
+
+	"github.com/vmware/pmd-next-gen/pkg/parser"
 )
 
 type Meta struct {
@@ -35,6 +40,18 @@ func (m *Meta) Save() error {
 	return m.Cfg.SaveTo(m.Path)
 }
 
+// Append merges the files on top of the loaded one. The result is meant for
+// reading only, Save() still writes to the original path.
+func (m *Meta) Append(paths ...string) error {
+	for _, p := range paths {
+		if err := m.Cfg.Append(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func ParseKeyFromSectionString(path string, section string, key string) (string, error) {
 	c, err := Load(path)
 	if err != nil {
@@ -160,6 +177,87 @@ func (m *Meta) SetKeyToNewSectionUint(key string, value uint) {
 	m.Section.NewKey(key, s)
 }
 
+func (m *Meta) Sections(section string) []*ini.Section {
+	sections, err := m.Cfg.SectionsByName(section)
+	if err != nil {
+		return nil
+	}
+
+	return sections
+}
+
+// MapSectionsTo fills the fields of the struct pointed to by v from the keys of
+// the sections, using the field name as key name. Later sections override
+// earlier ones and list keys accumulate, the way systemd merges drop-ins. An
+// empty assignment resets whatever the sections before it set.
+func MapSectionsTo(sections []*ini.Section, v interface{}) error {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	var errs []error
+	for i := 0; i < rt.NumField(); i++ {
+		f := rv.Field(i)
+		name := rt.Field(i).Name
+
+		for _, s := range sections {
+			if !s.HasKey(name) {
+				continue
+			}
+
+			// Empty values are not kept among the shadows, a key starting
+			// with one is a reset followed by new values
+			k := s.Key(name)
+			if k.Value() == "" {
+				f.Set(reflect.Zero(f.Type()))
+			}
+
+			values := k.ValueWithShadows()
+			if len(values) == 0 {
+				continue
+			}
+			last := values[len(values)-1]
+
+			switch f.Kind() {
+			case reflect.String:
+				f.SetString(last)
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				u, err := strconv.ParseUint(last, 10, f.Type().Bits())
+				if err != nil {
+					errs = append(errs, fmt.Errorf("invalid %s='%s'", name, last))
+					continue
+				}
+				f.SetUint(u)
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				n, err := strconv.ParseInt(last, 10, f.Type().Bits())
+				if err != nil {
+					errs = append(errs, fmt.Errorf("invalid %s='%s'", name, last))
+					continue
+				}
+				f.SetInt(n)
+			case reflect.Bool:
+				b, err := parser.ParseBool(last)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("invalid %s='%s'", name, last))
+					continue
+				}
+				f.SetBool(b)
+			case reflect.Slice:
+				if f.Type().Elem().Kind() != reflect.String {
+					continue
+				}
+
+				for _, value := range values {
+					for _, w := range strings.Fields(value) {
+						f.Set(reflect.Append(f, reflect.ValueOf(w)))
+					}
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 func MapTo(cfg *ini.File, section string, v interface{}) error {
 	if err := cfg.Section(section).MapTo(v); err != nil {
 		return err
@@ -19,10 +19,10 @@ func ApplyCapability(cred *syscall.Credential) error {
 	allCapabilityTypes := capability.CAPS | capability.BOUNDS | capability.AMBS
 
 	caps.Clear(capability.CAPS | capability.BOUNDS | capability.AMBS)
-	caps.Set(capability.BOUNDS, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE)
-	caps.Set(capability.PERMITTED, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE)
-	caps.Set(capability.INHERITABLE, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE)
-	caps.Set(capability.EFFECTIVE, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE)
+	caps.Set(capability.BOUNDS, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE, capability.CAP_NET_RAW)
+	caps.Set(capability.PERMITTED, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE, capability.CAP_NET_RAW)
+	caps.Set(capability.INHERITABLE, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE, capability.CAP_NET_RAW)
+	caps.Set(capability.EFFECTIVE, capability.CAP_NET_ADMIN, capability.CAP_SYS_ADMIN, capability.CAP_NET_BIND_SERVICE, capability.CAP_NET_RAW)
 
 	caps.Clear(capability.AMBIENT)
 	return caps.Apply(allCapabilityTypes)
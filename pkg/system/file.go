@@ -137,6 +137,22 @@ func ChangePermission(u string, file string) (err error) {
 	return os.Chmod(file, 0660)
 }
 
+// ChangeGroupPermission hands the file to root and the group, for secrets
+// which the group may read but not modify.
+func ChangeGroupPermission(g string, file string, perm os.FileMode) error {
+	grp, err := user.LookupGroup(g)
+	if err != nil {
+		return err
+	}
+
+	gid, _ := strconv.Atoi(grp.Gid)
+	if err := os.Chown(file, 0, gid); err != nil {
+		return err
+	}
+
+	return os.Chmod(file, perm)
+}
+
 func CreateStateDirs(path string, uid int, gid int) error {
 	if err := os.MkdirAll(path, os.FileMode(07777)); err != nil {
 		return err
@@ -4,7 +4,9 @@
 package validator
 
 import (
+	"encoding/base64"
 	"net"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -97,6 +99,10 @@ func IsValidIP(ip string) bool {
 	return true
 }
 
+func IsIPv4(ip string) bool {
+	return net.ParseIP(ip).To4() != nil
+}
+
 func IsIP(str string) bool {
 	_, _, err := net.ParseCIDR(str)
 	if err != nil {
@@ -166,6 +172,40 @@ func IsNotMAC(mac string) bool {
 	return !govalidator.IsMAC(mac)
 }
 
+func IsMatchMACAddress(macs string) bool {
+	for _, mac := range strings.Fields(strings.TrimPrefix(macs, "!")) {
+		if !govalidator.IsMAC(mac) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var matchPatternRegexp = regexp.MustCompile(`^[A-Za-z0-9_.:@+*?\[\]/-]+$`)
+
+// IsMatchPattern accepts a space separated list of glob patterns as taken by
+// Name=, Path=, Driver=, Type= and Kind=, optionally inverted by a leading "!"
+func IsMatchPattern(patterns string) bool {
+	fields := strings.Fields(strings.TrimPrefix(patterns, "!"))
+	if len(fields) == 0 {
+		return false
+	}
+
+	for _, f := range fields {
+		if !matchPatternRegexp.MatchString(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func IsMatchProperty(property string) bool {
+	k, v, ok := strings.Cut(property, "=")
+	return ok && k != "" && !strings.ContainsAny(k+v, " \t")
+}
+
 func IsScope(s string) bool {
 	switch s {
 	case "global", "link", "host":
@@ -244,6 +284,27 @@ func IsWireGuardListenPort(port string) bool {
 	return port == "auto" || IsPort(port)
 }
 
+// WireGuard keys are 32 bytes, base64 encoded
+func IsWireGuardKey(key string) bool {
+	k, err := base64.StdEncoding.DecodeString(key)
+	return err == nil && len(k) == 32
+}
+
+func IsWireGuardPersistentKeepalive(interval string) bool {
+	return interval == "off" || IsUint16(interval)
+}
+
+// Routes to the AllowedIPs are not added with "off", else they go to the named or numbered table
+func IsWireGuardRouteTable(table string) bool {
+	switch table {
+	case "off", "default", "main", "local":
+		return true
+	}
+
+	t, err := strconv.ParseUint(table, 10, 32)
+	return err == nil && t > 0
+}
+
 func IsWireGuardPeerEndpoint(endPoint string) bool {
 	ip, port, err := net.SplitHostPort(endPoint)
 	if err != nil {
@@ -259,6 +320,89 @@ func IsWireGuardPeerEndpoint(endPoint string) bool {
 	return true
 }
 
+func IsVRFTable(table string) bool {
+	t, err := strconv.ParseUint(table, 10, 32)
+	return err == nil && t > 0
+}
+
+func IsGeneveId(id string) bool {
+	return IsVxLanVNI(id)
+}
+
+func IsGeneveFlowLabel(label string) bool {
+	l, err := strconv.ParseUint(label, 10, 32)
+	return err == nil && l <= 1048575
+}
+
+func IsGeneveIPDoNotFragment(df string) bool {
+	return df == "inherit" || IsBool(df)
+}
+
+func IsMACsecPort(port string) bool {
+	p, err := strconv.ParseUint(port, 10, 16)
+	return err == nil && p > 0
+}
+
+func IsMACsecPacketNumber(pn string) bool {
+	p, err := strconv.ParseUint(pn, 10, 32)
+	return err == nil && p > 0
+}
+
+func IsMACsecKeyId(id string) bool {
+	return IsUint8(id)
+}
+
+// MACsec keys are 128 or 256 bit hex strings
+func IsMACsecKey(key string) bool {
+	return (len(key) == 32 || len(key) == 64) && govalidator.IsHexadecimal(key)
+}
+
+func IsTunnelKind(kind string) bool {
+	return kind == "ipip" || kind == "gre" || kind == "gretap" || kind == "ip6gre" || kind == "sit" || kind == "vti"
+}
+
+func IsTunnelAddress(addr string) bool {
+	return addr == "any" || IsIP(addr)
+}
+
+func IsTunnelTTL(ttl string) bool {
+	return ttl == "inherit" || IsUint8(ttl)
+}
+
+func IsTunnelKey(key string) bool {
+	return IsUint32(key) || govalidator.IsIPv4(key)
+}
+
+func IsBridgePortCost(cost string) bool {
+	c, err := strconv.ParseUint(cost, 10, 16)
+	return err == nil && c > 0
+}
+
+func IsBridgePortPriority(prio string) bool {
+	p, err := strconv.ParseUint(prio, 10, 8)
+	return err == nil && p <= 63
+}
+
+func IsVLANId(id string) bool {
+	v, err := strconv.ParseUint(id, 10, 16)
+	return err == nil && v > 0 && v < 4095
+}
+
+func IsVLANIdRange(ids string) bool {
+	from, to, ok := strings.Cut(ids, "-")
+	if !ok {
+		return IsVLANId(ids)
+	}
+
+	if !IsVLANId(from) || !IsVLANId(to) {
+		return false
+	}
+
+	f, _ := strconv.Atoi(from)
+	t, _ := strconv.Atoi(to)
+	return f <= t
+}
+
 func IsLinkMACAddressPolicy(policy string) bool {
 	return policy == "persistent" || policy == "random" || policy == "none"
 }
@@ -276,6 +420,15 @@ func IsLinkName(name string) bool {
 	return true
 }
 
+// IsInterfaceName checks the kernel's rules for interface names
+func IsInterfaceName(name string) bool {
+	if len(name) == 0 || len(name) > 15 || name == "." || name == ".." {
+		return false
+	}
+
+	return !strings.ContainsAny(name, "/: \t\n")
+}
+
 func IsLinkAlternativeNamesPolicy(policy string) bool {
 	return policy == "database" || policy == "onboard" || policy == "slot" ||
 		policy == "path" || policy == "mac"
@@ -453,6 +606,143 @@ func IsRoutingType(typ string) bool {
 	return typ == "blackhole" || typ == "unreachable" || typ == "prohibit"
 }
 
+func IsRouteType(typ string) bool {
+	switch typ {
+	case "unicast", "local", "broadcast", "anycast", "multicast", "blackhole", "unreachable", "prohibit", "throw", "nat", "xresolve":
+		return true
+	}
+
+	return false
+}
+
+func IsRouteProtocol(p string) bool {
+	switch p {
+	case "kernel", "boot", "static", "ra", "dhcp":
+		return true
+	}
+
+	return IsUint8(p)
+}
+
+func IsRouteIPv6Preference(p string) bool {
+	return p == "low" || p == "medium" || p == "high"
+}
+
+// IsRouteMultiPath checks "address[@interface] [weight]"
+func IsRouteMultiPath(mp string) bool {
+	f := strings.Fields(mp)
+	if len(f) == 0 || len(f) > 2 {
+		return false
+	}
+
+	gw := strings.Split(f[0], "@")
+	if len(gw) > 2 || !IsValidIP(gw[0]) {
+		return false
+	}
+	if len(gw) == 2 && !IsInterfaceName(gw[1]) {
+		return false
+	}
+
+	if len(f) == 2 {
+		w, err := strconv.ParseUint(f[1], 10, 32)
+		if err != nil || w < 1 || w > 256 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func IsNextHopId(id string) bool {
+	i, err := strconv.ParseUint(id, 10, 32)
+	return err == nil && i > 0
+}
+
+// IsNextHopGroup checks "id[:weight]" with a weight of 1 to 255
+func IsNextHopGroup(g string) bool {
+	v := strings.Split(g, ":")
+	if len(v) > 2 || !IsNextHopId(v[0]) {
+		return false
+	}
+
+	if len(v) == 2 {
+		w, err := strconv.ParseUint(v[1], 10, 8)
+		if err != nil || w < 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func IsAddressPreferredLifetime(l string) bool {
+	return l == "forever" || l == "infinity" || l == "0"
+}
+
+func IsAddressDuplicateAddressDetection(d string) bool {
+	return d == "ipv4" || d == "ipv6" || d == "both" || d == "none"
+}
+
+func isTrafficControlMajorMinor(h string) bool {
+	v := strings.Split(h, ":")
+	if len(v) != 2 {
+		return false
+	}
+
+	for _, n := range v {
+		if n == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(n, 16, 16); err != nil {
+			return false
+		}
+	}
+
+	return v[0] != ""
+}
+
+func IsTrafficControlParent(p string) bool {
+	if p == "root" || p == "clsact" || p == "ingress" {
+		return true
+	}
+
+	return isTrafficControlMajorMinor(p)
+}
+
+// IsTrafficControlHandle checks "major:" or a plain hexadecimal major
+func IsTrafficControlHandle(h string) bool {
+	if !strings.Contains(h, ":") {
+		h += ":"
+	}
+
+	return isTrafficControlMajorMinor(h) && strings.HasSuffix(h, ":")
+}
+
+func IsTrafficControlClassId(id string) bool {
+	return isTrafficControlMajorMinor(id) && !strings.HasSuffix(id, ":")
+}
+
+// IsByteSize checks a size with an optional K, M or G suffix
+func IsByteSize(s string) bool {
+	if strings.HasSuffix(s, "K") || strings.HasSuffix(s, "M") || strings.HasSuffix(s, "G") {
+		s = s[:len(s)-1]
+	}
+
+	return IsUint32(s)
+}
+
+func IsPercent(p string) bool {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(p, "%"), 64)
+	return err == nil && strings.HasSuffix(p, "%") && v >= 0 && v <= 100
+}
+
+var timeSpanRegexp = regexp.MustCompile(`^(\d+(\.\d+)?\s*(us|usec|ms|msec|s|sec|seconds?|m|min|minutes?|h|hr|hours?|d|days?)?\s*)+$`)
+
+// IsTimeSpan checks a systemd time span such as "100ms", "5s" or "1min 30s"
+func IsTimeSpan(t string) bool {
+	return t == "infinity" || timeSpanRegexp.MatchString(t)
+}
+
 func IsRouterPreference(p string) bool {
 	return p == "high" || p == "low" || p == "medium" || p == "normal" || p == "default"
 }
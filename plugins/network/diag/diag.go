@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package diag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	protocolICMP   = 1
+	protocolUDP    = 17
+	protocolICMPv6 = 58
+)
+
+func decodeJSONRequest(r *http.Request, v interface{}) error {
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// resolveTarget turns a host name or address into an IP. Family "ipv4" or
+// "ipv6" restricts the lookup, empty picks whatever resolves first.
+func resolveTarget(host string, family string) (net.IP, error) {
+	network := "ip"
+	switch family {
+	case "":
+	case "ipv4":
+		network = "ip4"
+	case "ipv6":
+		network = "ip6"
+	default:
+		return nil, fmt.Errorf("invalid family='%s'", family)
+	}
+
+	a, err := net.ResolveIPAddr(network, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.IP, nil
+}
+
+// bindToDevice returns a socket Control function which binds to link like
+// "ping -I" does. An empty link leaves the socket unbound.
+func bindToDevice(link string) func(string, string, syscall.RawConn) error {
+	if link == "" {
+		return nil
+	}
+
+	return func(network, address string, c syscall.RawConn) error {
+		var err error
+		if cerr := c.Control(func(fd uintptr) {
+			err = unix.BindToDevice(int(fd), link)
+		}); cerr != nil {
+			return cerr
+		}
+		return err
+	}
+}
+
+// icmpConn is a raw ICMP or ICMPv6 socket which also hands back the TTL or hop
+// limit of received packets
+type icmpConn struct {
+	conn net.PacketConn
+	v4   *ipv4.PacketConn
+	v6   *ipv6.PacketConn
+	buf  []byte
+}
+
+func listenICMP(ip net.IP, link string) (*icmpConn, error) {
+	lc := net.ListenConfig{
+		Control: bindToDevice(link),
+	}
+
+	if ip.To4() != nil {
+		conn, err := lc.ListenPacket(context.Background(), "ip4:icmp", "0.0.0.0")
+		if err != nil {
+			return nil, err
+		}
+
+		c := icmpConn{
+			conn: conn,
+			v4:   ipv4.NewPacketConn(conn),
+			buf:  make([]byte, 65536),
+		}
+		if err := c.v4.SetControlMessage(ipv4.FlagTTL, true); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return &c, nil
+	}
+
+	conn, err := lc.ListenPacket(context.Background(), "ip6:ipv6-icmp", "::")
+	if err != nil {
+		return nil, err
+	}
+
+	c := icmpConn{
+		conn: conn,
+		v6:   ipv6.NewPacketConn(conn),
+		buf:  make([]byte, 65536),
+	}
+	if err := c.v6.SetControlMessage(ipv6.FlagHopLimit, true); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+func (c *icmpConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *icmpConn) protocol() int {
+	if c.v4 != nil {
+		return protocolICMP
+	}
+
+	return protocolICMPv6
+}
+
+func (c *icmpConn) echoType() (icmp.Type, icmp.Type) {
+	if c.v4 != nil {
+		return ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	}
+
+	return ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+}
+
+// setTTL sets the TTL or hop limit of outgoing unicast packets
+func (c *icmpConn) setTTL(ttl int) error {
+	if c.v4 != nil {
+		return c.v4.SetTTL(ttl)
+	}
+
+	return c.v6.SetHopLimit(ttl)
+}
+
+func (c *icmpConn) writeEcho(dst net.IP, id int, seq int, payload []byte) error {
+	request, _ := c.echoType()
+	m := icmp.Message{
+		Type: request,
+		Body: &icmp.Echo{
+			ID:   id,
+			Seq:  seq,
+			Data: payload,
+		},
+	}
+
+	// The kernel fills in the ICMPv6 checksum
+	b, err := m.Marshal(nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.conn.WriteTo(b, &net.IPAddr{IP: dst})
+	return err
+}
+
+// read returns the next ICMP message along with its sender and TTL
+func (c *icmpConn) read(deadline time.Time) (*icmp.Message, net.IP, int, error) {
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
+		return nil, nil, 0, err
+	}
+
+	b := c.buf
+	for {
+		var n, ttl int
+		var src net.Addr
+		var err error
+
+		if c.v4 != nil {
+			var cm *ipv4.ControlMessage
+			n, cm, src, err = c.v4.ReadFrom(b)
+			if cm != nil {
+				ttl = cm.TTL
+			}
+		} else {
+			var cm *ipv6.ControlMessage
+			n, cm, src, err = c.v6.ReadFrom(b)
+			if cm != nil {
+				ttl = cm.HopLimit
+			}
+		}
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		m, err := icmp.ParseMessage(c.protocol(), b[:n])
+		if err != nil {
+			continue
+		}
+
+		var ip net.IP
+		if a, ok := src.(*net.IPAddr); ok {
+			ip = a.IP
+		}
+
+		return m, ip, ttl, nil
+	}
+}
+
+func isTimeout(err error) bool {
+	if e, ok := err.(net.Error); ok {
+		return e.Timeout()
+	}
+
+	return false
+}
+
+func durationToMSec(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package diag
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vmware/pmd-next-gen/pkg/jobs"
+)
+
+func routerPing(w http.ResponseWriter, r *http.Request) {
+	p := Ping{}
+	if err := decodeJSONRequest(r, &p); err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	job := jobs.CreateJob(func() (interface{}, error) {
+		return p.Run()
+	})
+	jobs.AcceptedResponse(w, job)
+}
+
+func routerTraceroute(w http.ResponseWriter, r *http.Request) {
+	t := Traceroute{}
+	if err := decodeJSONRequest(r, &t); err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	job := jobs.CreateJob(func() (interface{}, error) {
+		return t.Run()
+	})
+	jobs.AcceptedResponse(w, job)
+}
+
+func routerLookup(w http.ResponseWriter, r *http.Request) {
+	l := Lookup{}
+	if err := decodeJSONRequest(r, &l); err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	// The job outlives the request, so does its context
+	job := jobs.CreateJob(func() (interface{}, error) {
+		return l.Run(context.Background())
+	})
+	jobs.AcceptedResponse(w, job)
+}
+
+func routerPortProbe(w http.ResponseWriter, r *http.Request) {
+	p := PortProbe{}
+	if err := decodeJSONRequest(r, &p); err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	job := jobs.CreateJob(func() (interface{}, error) {
+		return p.Run()
+	})
+	jobs.AcceptedResponse(w, job)
+}
+
+func RegisterRouterDiag(router *mux.Router) {
+	n := router.PathPrefix("/diag").Subrouter().StrictSlash(false)
+
+	n.HandleFunc("/ping", routerPing).Methods("POST")
+	n.HandleFunc("/traceroute", routerTraceroute).Methods("POST")
+	n.HandleFunc("/lookup", routerLookup).Methods("POST")
+	n.HandleFunc("/probe", routerPortProbe).Methods("POST")
+}
@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package diag
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/vmware/pmd-next-gen/plugins/network/resolved"
+)
+
+var recordTypes = map[string]dnsmessage.Type{
+	"A":      dnsmessage.TypeA,
+	"NS":     dnsmessage.TypeNS,
+	"CNAME":  dnsmessage.TypeCNAME,
+	"SOA":    dnsmessage.TypeSOA,
+	"PTR":    dnsmessage.TypePTR,
+	"MX":     dnsmessage.TypeMX,
+	"TXT":    dnsmessage.TypeTXT,
+	"AAAA":   dnsmessage.TypeAAAA,
+	"SRV":    dnsmessage.TypeSRV,
+	"CAA":    257,
+	"DS":     43,
+	"DNSKEY": 48,
+	"TLSA":   52,
+	"SSHFP":  44,
+}
+
+// Lookup resolves Name through systemd-resolved. Without Type the addresses
+// are looked up like getaddrinfo() does, otherwise the records of Type
+// ("MX", "TXT", ... or a number). Link restricts the lookup to the DNS servers
+// of that link.
+type Lookup struct {
+	Name   string `json:"Name"`
+	Link   string `json:"Link"`
+	Family string `json:"Family"`
+	Type   string `json:"Type"`
+}
+
+type LookupAddress struct {
+	Link    string `json:"Link"`
+	Family  string `json:"Family"`
+	Address string `json:"Address"`
+}
+
+type LookupRecord struct {
+	Link string `json:"Link"`
+	Name string `json:"Name"`
+	Type string `json:"Type"`
+	TTL  uint32 `json:"TTL"`
+	Data string `json:"Data"`
+}
+
+type LookupResult struct {
+	Name      string          `json:"Name"`
+	Canonical string          `json:"Canonical"`
+	Addresses []LookupAddress `json:"Addresses"`
+	Records   []LookupRecord  `json:"Records"`
+}
+
+func parseRecordType(s string) (dnsmessage.Type, error) {
+	if t, ok := recordTypes[strings.ToUpper(s)]; ok {
+		return t, nil
+	}
+
+	t, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid type='%s'", s)
+	}
+
+	return dnsmessage.Type(t), nil
+}
+
+func recordTypeToString(t dnsmessage.Type) string {
+	for k, v := range recordTypes {
+		if v == t {
+			return k
+		}
+	}
+
+	return strconv.Itoa(int(t))
+}
+
+func recordDataToString(body dnsmessage.ResourceBody) string {
+	switch b := body.(type) {
+	case *dnsmessage.AResource:
+		return net.IP(b.A[:]).String()
+	case *dnsmessage.AAAAResource:
+		return net.IP(b.AAAA[:]).String()
+	case *dnsmessage.CNAMEResource:
+		return b.CNAME.String()
+	case *dnsmessage.NSResource:
+		return b.NS.String()
+	case *dnsmessage.PTRResource:
+		return b.PTR.String()
+	case *dnsmessage.MXResource:
+		return fmt.Sprintf("%d %s", b.Pref, b.MX)
+	case *dnsmessage.SRVResource:
+		return fmt.Sprintf("%d %d %d %s", b.Priority, b.Weight, b.Port, b.Target)
+	case *dnsmessage.SOAResource:
+		return fmt.Sprintf("%s %s %d %d %d %d %d", b.NS, b.MBox, b.Serial, b.Refresh, b.Retry, b.Expire, b.MinTTL)
+	case *dnsmessage.TXTResource:
+		txt := []string{}
+		for _, t := range b.TXT {
+			txt = append(txt, strconv.Quote(t))
+		}
+		return strings.Join(txt, " ")
+	case *dnsmessage.UnknownResource:
+		return hex.EncodeToString(b.Data)
+	}
+
+	return ""
+}
+
+// parseRecord decodes a resource record in wire format as handed out by
+// ResolveRecord. It is wrapped into a message with a single answer so the
+// dnsmessage parser can take it.
+func parseRecord(data []byte) (*dnsmessage.Resource, error) {
+	msg := make([]byte, 12, 12+len(data))
+	msg[2] = 0x80
+	msg[7] = 1
+	msg = append(msg, data...)
+
+	var p dnsmessage.Parser
+	if _, err := p.Start(msg); err != nil {
+		return nil, err
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil, err
+	}
+
+	r, err := p.Answer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func familyToString(family int32) string {
+	if family == syscall.AF_INET6 {
+		return "ipv6"
+	}
+
+	return "ipv4"
+}
+
+func (l *Lookup) Run(ctx context.Context) (*LookupResult, error) {
+	if l.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+
+	family := syscall.AF_UNSPEC
+	switch l.Family {
+	case "":
+	case "ipv4":
+		family = syscall.AF_INET
+	case "ipv6":
+		family = syscall.AF_INET6
+	default:
+		return nil, fmt.Errorf("invalid family='%s'", l.Family)
+	}
+
+	index := 0
+	if l.Link != "" {
+		link, err := netlink.LinkByName(l.Link)
+		if err != nil {
+			return nil, err
+		}
+		index = link.Attrs().Index
+	}
+
+	links := map[int32]string{}
+	if ll, err := netlink.LinkList(); err == nil {
+		for _, link := range ll {
+			links[int32(link.Attrs().Index)] = link.Attrs().Name
+		}
+	}
+
+	c, err := resolved.NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return nil, err
+	}
+	defer c.Close()
+
+	r := LookupResult{
+		Name:      l.Name,
+		Addresses: []LookupAddress{},
+		Records:   []LookupRecord{},
+	}
+
+	if l.Type == "" {
+		addrs, canonical, err := c.DBusResolveHostname(ctx, index, l.Name, family)
+		if err != nil {
+			log.Errorf("Failed to resolve name='%s': %v", l.Name, err)
+			return nil, err
+		}
+
+		r.Canonical = canonical
+		for _, a := range addrs {
+			r.Addresses = append(r.Addresses, LookupAddress{
+				Link:    links[a.Ifindex],
+				Family:  familyToString(a.Family),
+				Address: net.IP(a.Address).String(),
+			})
+		}
+
+		return &r, nil
+	}
+
+	t, err := parseRecordType(l.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := c.DBusResolveRecord(ctx, index, l.Name, uint16(dnsmessage.ClassINET), uint16(t))
+	if err != nil {
+		log.Errorf("Failed to resolve name='%s' type='%s': %v", l.Name, l.Type, err)
+		return nil, err
+	}
+
+	for _, rr := range records {
+		lr := LookupRecord{
+			Link: links[rr.Ifindex],
+			Type: recordTypeToString(dnsmessage.Type(rr.Type)),
+		}
+
+		if res, err := parseRecord(rr.Data); err == nil {
+			lr.Name = res.Header.Name.String()
+			lr.TTL = res.Header.TTL
+			lr.Data = recordDataToString(res.Body)
+		} else {
+			lr.Data = hex.EncodeToString(rr.Data)
+		}
+
+		r.Records = append(r.Records, lr)
+	}
+
+	return &r, nil
+}
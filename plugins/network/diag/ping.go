@@ -0,0 +1,189 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package diag
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/icmp"
+)
+
+const (
+	defaultPingCount    = 4
+	maxPingCount        = 100
+	defaultPingInterval = 1000
+	minPingInterval     = 200
+	defaultPingTimeout  = 2
+	defaultPingSize     = 56
+	maxPingSize         = 65507
+)
+
+// Ping sends ICMP or ICMPv6 echo requests to Address. Link binds the socket to
+// a link like "ping -I". Zero values fall back to 4 requests, one per second,
+// with a 2 second wait for the last reply.
+type Ping struct {
+	Address      string `json:"Address"`
+	Family       string `json:"Family"`
+	Link         string `json:"Link"`
+	Count        uint   `json:"Count"`
+	IntervalMSec uint   `json:"IntervalMSec"`
+	TimeoutSec   uint   `json:"TimeoutSec"`
+	Size         uint   `json:"Size"`
+}
+
+type PingReply struct {
+	Seq     int     `json:"Seq"`
+	From    string  `json:"From"`
+	TTL     int     `json:"TTL"`
+	RTTMSec float64 `json:"RTTMSec"`
+}
+
+type PingStatistics struct {
+	Address     string      `json:"Address"`
+	IP          string      `json:"IP"`
+	Transmitted int         `json:"Transmitted"`
+	Received    int         `json:"Received"`
+	PacketLoss  float64     `json:"PacketLoss"`
+	MinRTTMSec  float64     `json:"MinRTTMSec"`
+	AvgRTTMSec  float64     `json:"AvgRTTMSec"`
+	MaxRTTMSec  float64     `json:"MaxRTTMSec"`
+	Replies     []PingReply `json:"Replies"`
+}
+
+func (p *Ping) setDefaults() error {
+	if p.Address == "" {
+		return fmt.Errorf("missing address")
+	}
+
+	if p.Count == 0 {
+		p.Count = defaultPingCount
+	}
+	if p.Count > maxPingCount {
+		return fmt.Errorf("invalid count='%d'", p.Count)
+	}
+
+	if p.IntervalMSec == 0 {
+		p.IntervalMSec = defaultPingInterval
+	}
+	if p.IntervalMSec < minPingInterval {
+		return fmt.Errorf("invalid interval='%d'", p.IntervalMSec)
+	}
+
+	if p.TimeoutSec == 0 {
+		p.TimeoutSec = defaultPingTimeout
+	}
+	if p.Size == 0 {
+		p.Size = defaultPingSize
+	}
+	if p.Size > maxPingSize {
+		return fmt.Errorf("invalid size='%d'", p.Size)
+	}
+
+	return nil
+}
+
+func (p *Ping) Run() (*PingStatistics, error) {
+	if err := p.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	ip, err := resolveTarget(p.Address, p.Family)
+	if err != nil {
+		log.Errorf("Failed to resolve address='%s': %v", p.Address, err)
+		return nil, err
+	}
+
+	c, err := listenICMP(ip, p.Link)
+	if err != nil {
+		log.Errorf("Failed to open ICMP socket: %v", err)
+		return nil, err
+	}
+	defer c.Close()
+
+	// Raw sockets see every echo reply, the id picks out ours
+	id := rand.Intn(0xffff)
+	_, reply := c.echoType()
+	payload := make([]byte, p.Size)
+	interval := time.Duration(p.IntervalMSec) * time.Millisecond
+
+	s := PingStatistics{
+		Address: p.Address,
+		IP:      ip.String(),
+		Replies: []PingReply{},
+	}
+
+	sent := map[int]time.Time{}
+	var last time.Time
+	next := time.Now()
+	for {
+		if s.Transmitted < int(p.Count) && !time.Now().Before(next) {
+			s.Transmitted++
+			if err := c.writeEcho(ip, id, s.Transmitted, payload); err != nil {
+				log.Errorf("Failed to send echo request to '%s': %v", ip, err)
+				return nil, err
+			}
+			last = time.Now()
+			sent[s.Transmitted] = last
+			next = next.Add(interval)
+		}
+
+		deadline := next
+		if s.Transmitted == int(p.Count) {
+			deadline = last.Add(time.Duration(p.TimeoutSec) * time.Second)
+		}
+
+		m, src, ttl, err := c.read(deadline)
+		if err != nil {
+			if !isTimeout(err) {
+				return nil, err
+			}
+			if s.Transmitted == int(p.Count) {
+				break
+			}
+			continue
+		}
+
+		echo, ok := m.Body.(*icmp.Echo)
+		if m.Type != reply || !ok || echo.ID != id {
+			continue
+		}
+
+		t, ok := sent[echo.Seq]
+		if !ok {
+			continue
+		}
+		delete(sent, echo.Seq)
+
+		s.Replies = append(s.Replies, PingReply{
+			Seq:     echo.Seq,
+			From:    src.String(),
+			TTL:     ttl,
+			RTTMSec: durationToMSec(time.Since(t)),
+		})
+
+		if len(s.Replies) == int(p.Count) {
+			break
+		}
+	}
+
+	s.Received = len(s.Replies)
+	s.PacketLoss = float64(s.Transmitted-s.Received) * 100 / float64(s.Transmitted)
+	for i, r := range s.Replies {
+		if i == 0 || r.RTTMSec < s.MinRTTMSec {
+			s.MinRTTMSec = r.RTTMSec
+		}
+		if r.RTTMSec > s.MaxRTTMSec {
+			s.MaxRTTMSec = r.RTTMSec
+		}
+		s.AvgRTTMSec += r.RTTMSec
+	}
+	if s.Received > 0 {
+		s.AvgRTTMSec /= float64(s.Received)
+	}
+
+	return &s, nil
+}
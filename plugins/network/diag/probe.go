@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package diag
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultProbeTimeout = 5
+
+// PortProbe checks whether a TCP connection to Address and Port can be
+// established
+type PortProbe struct {
+	Address    string `json:"Address"`
+	Family     string `json:"Family"`
+	Link       string `json:"Link"`
+	Port       uint   `json:"Port"`
+	TimeoutSec uint   `json:"TimeoutSec"`
+}
+
+// PortProbeResult reports a refused or timed out connection in Error, only
+// failures to run the probe at all fail the job
+type PortProbeResult struct {
+	Address string  `json:"Address"`
+	IP      string  `json:"IP"`
+	Port    uint    `json:"Port"`
+	Open    bool    `json:"Open"`
+	RTTMSec float64 `json:"RTTMSec"`
+	Error   string  `json:"Error"`
+}
+
+func (p *PortProbe) Run() (*PortProbeResult, error) {
+	if p.Address == "" {
+		return nil, fmt.Errorf("missing address")
+	}
+	if p.Port == 0 || p.Port > 65535 {
+		return nil, fmt.Errorf("invalid port='%d'", p.Port)
+	}
+	if p.TimeoutSec == 0 {
+		p.TimeoutSec = defaultProbeTimeout
+	}
+
+	ip, err := resolveTarget(p.Address, p.Family)
+	if err != nil {
+		log.Errorf("Failed to resolve address='%s': %v", p.Address, err)
+		return nil, err
+	}
+
+	d := net.Dialer{
+		Timeout: time.Duration(p.TimeoutSec) * time.Second,
+		Control: bindToDevice(p.Link),
+	}
+
+	r := PortProbeResult{
+		Address: p.Address,
+		IP:      ip.String(),
+		Port:    p.Port,
+	}
+
+	start := time.Now()
+	conn, err := d.Dial("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(int(p.Port))))
+	if err != nil {
+		r.Error = err.Error()
+		return &r, nil
+	}
+	conn.Close()
+
+	r.Open = true
+	r.RTTMSec = durationToMSec(time.Since(start))
+
+	return &r, nil
+}
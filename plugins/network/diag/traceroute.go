@@ -0,0 +1,307 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package diag
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	defaultTracerouteMaxHops = 30
+	maxTracerouteMaxHops     = 64
+	defaultTracerouteProbes  = 3
+	maxTracerouteProbes      = 10
+	defaultTracerouteTimeout = 2
+
+	// First destination port of UDP probes, same as traceroute(8)
+	tracerouteBasePort = 33434
+)
+
+// Traceroute probes the path to Address with increasing TTLs. Protocol is
+// "udp" (default) or "icmp", Probes is the number of probes sent per hop.
+type Traceroute struct {
+	Address    string `json:"Address"`
+	Family     string `json:"Family"`
+	Link       string `json:"Link"`
+	Protocol   string `json:"Protocol"`
+	MaxHops    uint   `json:"MaxHops"`
+	Probes     uint   `json:"Probes"`
+	TimeoutSec uint   `json:"TimeoutSec"`
+}
+
+// TracerouteProbe is one answered or (with empty Address) lost probe
+type TracerouteProbe struct {
+	Address string  `json:"Address"`
+	RTTMSec float64 `json:"RTTMSec"`
+}
+
+type TracerouteHop struct {
+	TTL    int               `json:"TTL"`
+	Probes []TracerouteProbe `json:"Probes"`
+}
+
+type TracerouteResult struct {
+	Address  string          `json:"Address"`
+	IP       string          `json:"IP"`
+	Protocol string          `json:"Protocol"`
+	Reached  bool            `json:"Reached"`
+	Hops     []TracerouteHop `json:"Hops"`
+}
+
+func (t *Traceroute) setDefaults() error {
+	if t.Address == "" {
+		return fmt.Errorf("missing address")
+	}
+
+	switch t.Protocol {
+	case "":
+		t.Protocol = "udp"
+	case "udp", "icmp":
+	default:
+		return fmt.Errorf("invalid protocol='%s'", t.Protocol)
+	}
+
+	if t.MaxHops == 0 {
+		t.MaxHops = defaultTracerouteMaxHops
+	}
+	if t.MaxHops > maxTracerouteMaxHops {
+		return fmt.Errorf("invalid max hops='%d'", t.MaxHops)
+	}
+
+	if t.Probes == 0 {
+		t.Probes = defaultTracerouteProbes
+	}
+	if t.Probes > maxTracerouteProbes {
+		return fmt.Errorf("invalid probes='%d'", t.Probes)
+	}
+
+	if t.TimeoutSec == 0 {
+		t.TimeoutSec = defaultTracerouteTimeout
+	}
+
+	return nil
+}
+
+// udpProber sends the UDP probes, the ICMP errors they cause come back on the
+// raw socket
+type udpProber struct {
+	conn net.PacketConn
+	v4   *ipv4.PacketConn
+	v6   *ipv6.PacketConn
+	port int
+}
+
+func listenUDP(ip net.IP, link string) (*udpProber, error) {
+	lc := net.ListenConfig{
+		Control: bindToDevice(link),
+	}
+
+	network := "udp6"
+	if ip.To4() != nil {
+		network = "udp4"
+	}
+
+	conn, err := lc.ListenPacket(context.Background(), network, ":0")
+	if err != nil {
+		return nil, err
+	}
+
+	u := udpProber{
+		conn: conn,
+		port: conn.LocalAddr().(*net.UDPAddr).Port,
+	}
+	if ip.To4() != nil {
+		u.v4 = ipv4.NewPacketConn(conn)
+	} else {
+		u.v6 = ipv6.NewPacketConn(conn)
+	}
+
+	return &u, nil
+}
+
+func (u *udpProber) send(dst net.IP, ttl int, seq int) error {
+	var err error
+	if u.v4 != nil {
+		err = u.v4.SetTTL(ttl)
+	} else {
+		err = u.v6.SetHopLimit(ttl)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = u.conn.WriteTo(make([]byte, 32), &net.UDPAddr{IP: dst, Port: tracerouteBasePort + seq})
+	return err
+}
+
+func (u *udpProber) localPort() int {
+	if u == nil {
+		return 0
+	}
+
+	return u.port
+}
+
+// quotedPacket returns the transport header of the packet quoted in an ICMP
+// error along with its protocol
+func quotedPacket(data []byte, v4 bool) (int, []byte) {
+	if v4 {
+		if len(data) < ipv4.HeaderLen {
+			return 0, nil
+		}
+		hl := int(data[0]&0x0f) << 2
+		if len(data) < hl+8 {
+			return 0, nil
+		}
+		return int(data[9]), data[hl:]
+	}
+
+	if len(data) < ipv6.HeaderLen+8 {
+		return 0, nil
+	}
+	return int(data[6]), data[ipv6.HeaderLen:]
+}
+
+// matchProbe tells whether m answers the probe with sequence seq and whether
+// it ends the trace
+func (t *Traceroute) matchProbe(m *icmp.Message, c *icmpConn, id int, localPort int, seq int) (bool, bool) {
+	_, reply := c.echoType()
+	if m.Type == reply {
+		echo, ok := m.Body.(*icmp.Echo)
+		return ok && t.Protocol == "icmp" && echo.ID == id && echo.Seq == seq, true
+	}
+
+	var data []byte
+	final := false
+	switch b := m.Body.(type) {
+	case *icmp.TimeExceeded:
+		data = b.Data
+	case *icmp.DstUnreach:
+		data = b.Data
+		final = true
+	default:
+		return false, false
+	}
+
+	proto, inner := quotedPacket(data, c.v4 != nil)
+	if inner == nil {
+		return false, false
+	}
+
+	if t.Protocol == "udp" {
+		return proto == protocolUDP &&
+			int(binary.BigEndian.Uint16(inner[0:2])) == localPort &&
+			int(binary.BigEndian.Uint16(inner[2:4])) == tracerouteBasePort+seq, final
+	}
+
+	return proto == c.protocol() &&
+		int(binary.BigEndian.Uint16(inner[4:6])) == id &&
+		int(binary.BigEndian.Uint16(inner[6:8])) == seq, final
+}
+
+func (t *Traceroute) Run() (*TracerouteResult, error) {
+	if err := t.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	ip, err := resolveTarget(t.Address, t.Family)
+	if err != nil {
+		log.Errorf("Failed to resolve address='%s': %v", t.Address, err)
+		return nil, err
+	}
+
+	c, err := listenICMP(ip, t.Link)
+	if err != nil {
+		log.Errorf("Failed to open ICMP socket: %v", err)
+		return nil, err
+	}
+	defer c.Close()
+
+	var u *udpProber
+	if t.Protocol == "udp" {
+		if u, err = listenUDP(ip, t.Link); err != nil {
+			log.Errorf("Failed to open UDP socket: %v", err)
+			return nil, err
+		}
+		defer u.conn.Close()
+	}
+
+	id := rand.Intn(0xffff)
+	r := TracerouteResult{
+		Address:  t.Address,
+		IP:       ip.String(),
+		Protocol: t.Protocol,
+		Hops:     []TracerouteHop{},
+	}
+
+	seq := 0
+	for ttl := 1; ttl <= int(t.MaxHops); ttl++ {
+		hop := TracerouteHop{
+			TTL:    ttl,
+			Probes: []TracerouteProbe{},
+		}
+
+		done := false
+		for i := 0; i < int(t.Probes); i++ {
+			seq++
+
+			if u != nil {
+				err = u.send(ip, ttl, seq)
+			} else {
+				if err = c.setTTL(ttl); err == nil {
+					err = c.writeEcho(ip, id, seq, make([]byte, 32))
+				}
+			}
+			if err != nil {
+				log.Errorf("Failed to send probe to '%s': %v", ip, err)
+				return nil, err
+			}
+
+			start := time.Now()
+			deadline := start.Add(time.Duration(t.TimeoutSec) * time.Second)
+			probe := TracerouteProbe{}
+			for {
+				m, src, _, err := c.read(deadline)
+				if err != nil {
+					if !isTimeout(err) {
+						return nil, err
+					}
+					break
+				}
+
+				ok, final := t.matchProbe(m, c, id, u.localPort(), seq)
+				if !ok {
+					continue
+				}
+
+				probe.Address = src.String()
+				probe.RTTMSec = durationToMSec(time.Since(start))
+				if final {
+					done = true
+					r.Reached = src.Equal(ip)
+				}
+				break
+			}
+
+			hop.Probes = append(hop.Probes, probe)
+		}
+
+		r.Hops = append(r.Hops, hop)
+		if done {
+			break
+		}
+	}
+
+	return &r, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
 )
 
 func routerAcquirEthTool(w http.ResponseWriter, r *http.Request) {
@@ -17,7 +18,9 @@ func routerAcquirEthTool(w http.ResponseWriter, r *http.Request) {
 		Link: vars["link"],
 	}
 
-	err := e.AcquireEthTool(w)
+	err := namespace.Run(r.URL.Query().Get("netns"), func() error {
+		return e.AcquireEthTool(w)
+	})
 	if err != nil {
 		web.JSONResponseError(err, w)
 	}
@@ -30,7 +33,9 @@ func routerAcquirActionEthTool(w http.ResponseWriter, r *http.Request) {
 		Action: vars["property"],
 	}
 
-	err := e.AcquireActionEthTool(w)
+	err := namespace.Run(r.URL.Query().Get("netns"), func() error {
+		return e.AcquireActionEthTool(w)
+	})
 	if err != nil {
 		web.JSONResponseError(err, w)
 	}
@@ -49,7 +54,9 @@ func routerConfigureEthTool(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = e.ConfigureEthTool(w)
+	err = namespace.Run(r.URL.Query().Get("netns"), func() error {
+		return e.ConfigureEthTool(w)
+	})
 	if err != nil {
 		web.JSONResponseError(err, w)
 	}
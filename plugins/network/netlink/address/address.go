@@ -5,9 +5,15 @@ package address
 
 import (
 	"encoding/json"
+	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"strings"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 )
 
 type Address struct {
@@ -20,6 +26,9 @@ type Address struct {
 	Broadcast   string `json:"Broadcast"`
 	PreferedLft int    `json:"PreferedLft"`
 	ValidLft    int    `json:"ValidLft"`
+
+	ScopeName string   `json:"ScopeName"`
+	FlagNames []string `json:"FlagNames"`
 }
 
 type AddressInfo struct {
@@ -32,6 +41,9 @@ type AddressInfo struct {
 	Addresses []Address `json:"Addresses"`
 }
 
+// Runtime addresses are applied with netlink only and are lost once the link
+// is reconfigured by systemd-networkd, unlike the [Address] sections of a
+// .network file.
 type AddressAction struct {
 	Action  string  `json:"action"`
 	Link    string  `json:"link"`
@@ -47,18 +59,155 @@ func decodeJSONRequest(r *http.Request) (*AddressAction, error) {
 	return &address, nil
 }
 
+var addressScopes = map[string]int{
+	"global":  unix.RT_SCOPE_UNIVERSE,
+	"site":    unix.RT_SCOPE_SITE,
+	"link":    unix.RT_SCOPE_LINK,
+	"host":    unix.RT_SCOPE_HOST,
+	"nowhere": unix.RT_SCOPE_NOWHERE,
+}
+
+// Flags an address may be created with, the rest are only set by the kernel
+var addressFlags = map[string]int{
+	"nodad":          unix.IFA_F_NODAD,
+	"optimistic":     unix.IFA_F_OPTIMISTIC,
+	"homeaddress":    unix.IFA_F_HOMEADDRESS,
+	"mngtmpaddr":     unix.IFA_F_MANAGETEMPADDR,
+	"noprefixroute":  unix.IFA_F_NOPREFIXROUTE,
+	"autojoin":       unix.IFA_F_MCAUTOJOIN,
+	"stable-privacy": unix.IFA_F_STABLE_PRIVACY,
+}
+
+var addressReadOnlyFlags = map[string]int{
+	"secondary":  unix.IFA_F_SECONDARY,
+	"dadfailed":  unix.IFA_F_DADFAILED,
+	"deprecated": unix.IFA_F_DEPRECATED,
+	"tentative":  unix.IFA_F_TENTATIVE,
+	"permanent":  unix.IFA_F_PERMANENT,
+}
+
+func scopeName(scope int) string {
+	for k, v := range addressScopes {
+		if v == scope {
+			return k
+		}
+	}
+
+	return fmt.Sprintf("%d", scope)
+}
+
+func flagNames(flags int) []string {
+	names := []string{}
+	for _, m := range []map[string]int{addressFlags, addressReadOnlyFlags} {
+		for k, v := range m {
+			if flags&v != 0 {
+				names = append(names, k)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// parseIPNet accepts a bare address as well as a prefix
+func parseIPNet(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		return netlink.ParseIPNet(s)
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address='%s'", s)
+	}
+
+	bits := 128
+	if ip.To4() != nil {
+		bits = 32
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+func (a *AddressAction) buildAddr(link netlink.Link) (*netlink.Addr, error) {
+	ipnet, err := parseIPNet(a.Address.IP)
+	if err != nil {
+		return nil, err
+	}
+	addr := &netlink.Addr{IPNet: ipnet}
+
+	if a.Address.Label != "" {
+		if addr.IP.To4() == nil {
+			return nil, fmt.Errorf("label is supported only by IPv4 addresses")
+		}
+		if !strings.HasPrefix(a.Address.Label, link.Attrs().Name) {
+			return nil, fmt.Errorf("label='%s' must start with link name='%s'", a.Address.Label, link.Attrs().Name)
+		}
+		addr.Label = a.Address.Label
+	}
+
+	addr.Scope = a.Address.Scope
+	if a.Address.ScopeName != "" {
+		scope, ok := addressScopes[a.Address.ScopeName]
+		if !ok {
+			return nil, fmt.Errorf("invalid scope='%s'", a.Address.ScopeName)
+		}
+		addr.Scope = scope
+	}
+
+	addr.Flags = a.Address.Flags
+	for _, f := range a.Address.FlagNames {
+		v, ok := addressFlags[f]
+		if !ok {
+			return nil, fmt.Errorf("invalid flag='%s'", f)
+		}
+		addr.Flags |= v
+	}
+
+	if a.Address.Peer != "" {
+		if addr.Peer, err = parseIPNet(a.Address.Peer); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.Address.Broadcast != "" {
+		if addr.Broadcast = net.ParseIP(a.Address.Broadcast); addr.Broadcast == nil {
+			return nil, fmt.Errorf("invalid broadcast='%s'", a.Address.Broadcast)
+		}
+	}
+
+	if a.Address.PreferedLft > a.Address.ValidLft && a.Address.ValidLft > 0 {
+		return nil, fmt.Errorf("preferred lifetime must not exceed valid lifetime")
+	}
+	addr.PreferedLft = a.Address.PreferedLft
+	addr.ValidLft = a.Address.ValidLft
+
+	// Only the valid lifetime given means the address stays preferred until it expires
+	if addr.ValidLft > 0 && addr.PreferedLft == 0 {
+		addr.PreferedLft = addr.ValidLft
+	}
+	// And only the preferred one means it is valid forever
+	if addr.PreferedLft > 0 && addr.ValidLft == 0 {
+		addr.ValidLft = int(^uint32(0))
+	}
+
+	return addr, nil
+}
+
 func (a *AddressAction) Add() error {
 	link, err := netlink.LinkByName(a.Link)
 	if err != nil {
 		return err
 	}
 
-	addr, err := netlink.ParseAddr(a.Address.IP)
+	addr, err := a.buildAddr(link)
 	if err != nil {
+		log.Errorf("Failed to parse address='%s' of link='%s': %v", a.Address.IP, a.Link, err)
 		return err
 	}
 
 	if err := netlink.AddrAdd(link, addr); err != nil {
+		log.Errorf("Failed to add address='%s' to link='%s': %v", a.Address.IP, a.Link, err)
 		return err
 	}
 
@@ -71,12 +220,29 @@ func (a *AddressAction) Remove() error {
 		return err
 	}
 
-	addr, err := netlink.ParseAddr(a.Address.IP)
+	ipnet, err := parseIPNet(a.Address.IP)
 	if err != nil {
 		return err
 	}
+	addr := &netlink.Addr{IPNet: ipnet}
+
+	// The kernel matches the prefix length too, look it up when not given
+	if !strings.Contains(a.Address.IP, "/") {
+		addrs, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+		if err != nil {
+			return err
+		}
+
+		for i := range addrs {
+			if addrs[i].IP.Equal(ipnet.IP) {
+				addr = &addrs[i]
+				break
+			}
+		}
+	}
 
 	if err = netlink.AddrDel(link, addr); err != nil {
+		log.Errorf("Failed to remove address='%s' from link='%s': %v", a.Address.IP, a.Link, err)
 		return err
 	}
 
@@ -91,6 +257,8 @@ func fillOneAddress(a *netlink.Addr) Address {
 		Flags:       a.Flags,
 		PreferedLft: a.PreferedLft,
 		ValidLft:    a.ValidLft,
+		ScopeName:   scopeName(a.Scope),
+		FlagNames:   flagNames(a.Flags),
 	}
 
 	addr.Mask, _ = a.Mask.Size()
@@ -121,6 +289,21 @@ func buildAddressList(link netlink.Link, addrs []netlink.Addr) AddressInfo {
 	return addr
 }
 
+func AcquireLinkAddresses(name string) (*AddressInfo, error) {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := netlink.AddrList(link, netlink.FAMILY_ALL)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := buildAddressList(link, a)
+	return &addrs, nil
+}
+
 func AcquireAddresses() ([]AddressInfo, error) {
 	linkList, err := netlink.LinkList()
 	if err != nil {
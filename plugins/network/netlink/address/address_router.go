@@ -9,19 +9,74 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
 )
 
 func routerAcquireAddress(w http.ResponseWriter, r *http.Request) {
-	addrs, err := AcquireAddresses()
+	var addrs []AddressInfo
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		addrs, err = AcquireAddresses()
+		return err
+	})
 	if err != nil {
 		web.JSONResponseError(err, w)
+		return
 	}
 
 	web.JSONResponse(addrs, w)
 }
 
+func routerAcquireLinkAddress(w http.ResponseWriter, r *http.Request) {
+	var addrs *AddressInfo
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		addrs, err = AcquireLinkAddresses(mux.Vars(r)["link"])
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(addrs, w)
+}
+
+func routerAddAddress(w http.ResponseWriter, r *http.Request) {
+	a, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+	a.Link = mux.Vars(r)["link"]
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), a.Add); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("added", w)
+}
+
+func routerRemoveAddress(w http.ResponseWriter, r *http.Request) {
+	a, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+	a.Link = mux.Vars(r)["link"]
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), a.Remove); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("removed", w)
+}
+
 func RegisterRouterAddress(router *mux.Router) {
 	s := router.PathPrefix("/netlink").Subrouter().StrictSlash(false)
 
 	s.HandleFunc("/address", routerAcquireAddress).Methods("GET")
+	s.HandleFunc("/address/{link}", routerAcquireLinkAddress).Methods("GET")
+	s.HandleFunc("/address/{link}", routerAddAddress).Methods("POST")
+	s.HandleFunc("/address/{link}", routerRemoveAddress).Methods("DELETE")
 }
@@ -0,0 +1,247 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"syscall"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+type FDBEntry struct {
+	Link       string   `json:"Link"`
+	Ifindex    int      `json:"Ifindex"`
+	MACAddress string   `json:"MACAddress"`
+	VLAN       int      `json:"VLAN"`
+	Master     string   `json:"Master"`
+	State      string   `json:"State"`
+	Flags      []string `json:"Flags"`
+
+	// Remote VTEP of vxlan entries
+	Destination string `json:"Destination"`
+	VNI         int    `json:"VNI"`
+}
+
+type VLAN struct {
+	Id             uint16 `json:"Id"`
+	PVID           bool   `json:"PVID"`
+	EgressUntagged bool   `json:"EgressUntagged"`
+}
+
+type PortVLANs struct {
+	Link    string `json:"Link"`
+	Ifindex int    `json:"Ifindex"`
+	VLANs   []VLAN `json:"VLANs"`
+}
+
+func linkIndexToName(index int) string {
+	if index == 0 {
+		return ""
+	}
+
+	l, err := netlink.LinkByIndex(index)
+	if err != nil {
+		return ""
+	}
+
+	return l.Attrs().Name
+}
+
+func fdbStateToString(state int) string {
+	switch {
+	case state&netlink.NUD_PERMANENT != 0:
+		return "permanent"
+	case state&netlink.NUD_NOARP != 0:
+		return "static"
+	case state&netlink.NUD_STALE != 0:
+		return "stale"
+	case state&netlink.NUD_REACHABLE != 0:
+		return "reachable"
+	}
+
+	return "none"
+}
+
+func fdbFlagsToStrings(flags int) []string {
+	var s []string
+	if flags&netlink.NTF_SELF != 0 {
+		s = append(s, "self")
+	}
+	if flags&netlink.NTF_MASTER != 0 {
+		s = append(s, "master")
+	}
+	if flags&netlink.NTF_ROUTER != 0 {
+		s = append(s, "router")
+	}
+
+	return s
+}
+
+func decodeJSONRequest(r *http.Request) (*FDBEntry, error) {
+	e := FDBEntry{}
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+func (e *FDBEntry) buildNeigh() (*netlink.Neigh, error) {
+	l, err := netlink.LinkByName(e.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	mac, err := net.ParseMAC(e.MACAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	n := netlink.Neigh{
+		LinkIndex:    l.Attrs().Index,
+		Family:       syscall.AF_BRIDGE,
+		HardwareAddr: mac,
+		Vlan:         e.VLAN,
+		VNI:          e.VNI,
+	}
+
+	switch e.State {
+	case "", "permanent":
+		n.State = netlink.NUD_PERMANENT
+	case "static":
+		n.State = netlink.NUD_NOARP
+	case "dynamic":
+		n.State = netlink.NUD_REACHABLE
+	default:
+		return nil, fmt.Errorf("invalid state='%s'", e.State)
+	}
+
+	for _, f := range e.Flags {
+		switch f {
+		case "self":
+			n.Flags |= netlink.NTF_SELF
+		case "master":
+			n.Flags |= netlink.NTF_MASTER
+		case "router":
+			n.Flags |= netlink.NTF_ROUTER
+		default:
+			return nil, fmt.Errorf("invalid flag='%s'", f)
+		}
+	}
+
+	// Entries of bridge ports go to the bridge, vxlan keeps its own
+	if n.Flags&(netlink.NTF_SELF|netlink.NTF_MASTER) == 0 {
+		if l.Attrs().MasterIndex > 0 {
+			n.Flags |= netlink.NTF_MASTER
+		} else {
+			n.Flags |= netlink.NTF_SELF
+		}
+	}
+
+	if e.Destination != "" {
+		if n.IP = net.ParseIP(e.Destination); n.IP == nil {
+			return nil, fmt.Errorf("invalid destination='%s'", e.Destination)
+		}
+	}
+
+	if l.Type() == "vxlan" && n.IP == nil {
+		return nil, fmt.Errorf("vxlan entries need a destination")
+	}
+
+	return &n, nil
+}
+
+// AddFDB appends the entry, vxlan devices may flood an all-zero MAC to several destinations
+func (e *FDBEntry) AddFDB() error {
+	n, err := e.buildNeigh()
+	if err != nil {
+		log.Errorf("Failed to parse FDB entry MAC='%s' link='%s': %v", e.MACAddress, e.Link, err)
+		return err
+	}
+
+	if err := netlink.NeighAppend(n); err != nil {
+		log.Errorf("Failed to add FDB entry MAC='%s' link='%s': %v", e.MACAddress, e.Link, err)
+		return err
+	}
+
+	return nil
+}
+
+func (e *FDBEntry) RemoveFDB() error {
+	n, err := e.buildNeigh()
+	if err != nil {
+		log.Errorf("Failed to parse FDB entry MAC='%s' link='%s': %v", e.MACAddress, e.Link, err)
+		return err
+	}
+
+	if err := netlink.NeighDel(n); err != nil {
+		log.Errorf("Failed to remove FDB entry MAC='%s' link='%s': %v", e.MACAddress, e.Link, err)
+		return err
+	}
+
+	return nil
+}
+
+func AcquireFDB() ([]FDBEntry, error) {
+	neighs, err := netlink.NeighList(0, syscall.AF_BRIDGE)
+	if err != nil {
+		return nil, err
+	}
+
+	var fdb []FDBEntry
+	for _, n := range neighs {
+		e := FDBEntry{
+			Link:       linkIndexToName(n.LinkIndex),
+			Ifindex:    n.LinkIndex,
+			MACAddress: n.HardwareAddr.String(),
+			VLAN:       n.Vlan,
+			Master:     linkIndexToName(n.MasterIndex),
+			State:      fdbStateToString(n.State),
+			Flags:      fdbFlagsToStrings(n.Flags),
+			VNI:        n.VNI,
+		}
+		if n.IP != nil {
+			e.Destination = n.IP.String()
+		}
+
+		fdb = append(fdb, e)
+	}
+
+	return fdb, nil
+}
+
+func AcquireVLANs() ([]PortVLANs, error) {
+	vlans, err := netlink.BridgeVlanList()
+	if err != nil {
+		return nil, err
+	}
+
+	var ports []PortVLANs
+	for index, infos := range vlans {
+		p := PortVLANs{
+			Link:    linkIndexToName(int(index)),
+			Ifindex: int(index),
+		}
+
+		for _, v := range infos {
+			p.VLANs = append(p.VLANs, VLAN{
+				Id:             v.Vid,
+				PVID:           v.PortVID(),
+				EgressUntagged: v.EngressUntag(),
+			})
+		}
+
+		ports = append(ports, p)
+	}
+
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Ifindex < ports[j].Ifindex })
+
+	return ports, nil
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package bridge
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
+)
+
+func routerAcquireFDB(w http.ResponseWriter, r *http.Request) {
+	var fdb []FDBEntry
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		fdb, err = AcquireFDB()
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(fdb, w)
+}
+
+func routerAddFDB(w http.ResponseWriter, r *http.Request) {
+	e, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), e.AddFDB); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("added", w)
+}
+
+func routerRemoveFDB(w http.ResponseWriter, r *http.Request) {
+	e, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), e.RemoveFDB); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("removed", w)
+}
+
+func routerAcquireVLANs(w http.ResponseWriter, r *http.Request) {
+	var vlans []PortVLANs
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		vlans, err = AcquireVLANs()
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(vlans, w)
+}
+
+func RegisterRouterBridge(router *mux.Router) {
+	s := router.PathPrefix("/netlink").Subrouter().StrictSlash(false)
+
+	s.HandleFunc("/bridge/fdb", routerAcquireFDB).Methods("GET")
+	s.HandleFunc("/bridge/fdb", routerAddFDB).Methods("POST")
+	s.HandleFunc("/bridge/fdb", routerRemoveFDB).Methods("DELETE")
+	s.HandleFunc("/bridge/vlan", routerAcquireVLANs).Methods("GET")
+}
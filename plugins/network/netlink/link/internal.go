@@ -4,10 +4,25 @@
 package link
 
 import (
+	"net"
+
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
 )
 
+func acquireLink(link string) (netlink.Link, error) {
+	l, err := netlink.LinkByName(link)
+	if err != nil {
+		log.Errorf("Failed to find link='%s': %v", link, err)
+		return nil, err
+	}
+
+	return l, nil
+}
+
 func setMTU(link string, mtu int) error {
 	l, err := netlink.LinkByName(link)
 	if err != nil {
@@ -22,3 +37,162 @@ func setMTU(link string, mtu int) error {
 
 	return nil
 }
+
+func setTxQLen(link string, qlen int) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	if err = netlink.LinkSetTxQLen(l, qlen); err != nil {
+		log.Errorf("Failed to set link='%s' txqueuelen='%d': %v", link, qlen, err)
+		return err
+	}
+
+	return nil
+}
+
+func setAlias(link string, alias string) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	if err = netlink.LinkSetAlias(l, alias); err != nil {
+		log.Errorf("Failed to set link='%s' alias='%s': %v", link, alias, err)
+		return err
+	}
+
+	return nil
+}
+
+func setHardwareAddr(link string, mac net.HardwareAddr) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	if err = netlink.LinkSetHardwareAddr(l, mac); err != nil {
+		log.Errorf("Failed to set link='%s' MAC='%s': %v", link, mac, err)
+		return err
+	}
+
+	return nil
+}
+
+func setPromisc(link string, on bool) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	if on {
+		err = netlink.SetPromiscOn(l)
+	} else {
+		err = netlink.SetPromiscOff(l)
+	}
+	if err != nil {
+		log.Errorf("Failed to set link='%s' promisc='%t': %v", link, on, err)
+		return err
+	}
+
+	return nil
+}
+
+// setMaster enslaves the link to a bond, bridge or VRF. The kernel refuses to
+// enslave a link to a bond while it is up, so it is bounced around the change.
+func setMaster(link string, master string) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	m, err := acquireLink(master)
+	if err != nil {
+		return err
+	}
+
+	up := l.Attrs().Flags&net.FlagUp != 0
+	if m.Type() == "bond" && up {
+		if err := netlink.LinkSetDown(l); err != nil {
+			log.Errorf("Failed to bring down link='%s': %v", link, err)
+			return err
+		}
+	}
+
+	if err = netlink.LinkSetMaster(l, m); err != nil {
+		log.Errorf("Failed to set link='%s' master='%s': %v", link, master, err)
+		if m.Type() == "bond" && up {
+			netlink.LinkSetUp(l)
+		}
+		return err
+	}
+
+	if m.Type() == "bond" && up {
+		if err := netlink.LinkSetUp(l); err != nil {
+			log.Errorf("Failed to bring up link='%s': %v", link, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setNoMaster(link string) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	if err = netlink.LinkSetNoMaster(l); err != nil {
+		log.Errorf("Failed to release link='%s' from master: %v", link, err)
+		return err
+	}
+
+	return nil
+}
+
+func setUp(link string, up bool) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	if up {
+		err = netlink.LinkSetUp(l)
+	} else {
+		err = netlink.LinkSetDown(l)
+	}
+	if err != nil {
+		log.Errorf("Failed to set link='%s' up='%t': %v", link, up, err)
+		return err
+	}
+
+	return nil
+}
+
+// openNetNs opens a namespace created by "ip netns add" or the namespace of a pid
+func openNetNs(ns string) (netns.NsHandle, error) {
+	h, err := namespace.Open(ns)
+	if err != nil {
+		log.Errorf("Failed to find network namespace='%s': %v", ns, err)
+		return netns.None(), err
+	}
+
+	return h, nil
+}
+
+// setNetNs moves the link into the namespace opened as h
+func setNetNs(link string, ns string, h netns.NsHandle) error {
+	l, err := acquireLink(link)
+	if err != nil {
+		return err
+	}
+
+	if err = netlink.LinkSetNsFd(l, int(h)); err != nil {
+		log.Errorf("Failed to move link='%s' to network namespace='%s': %v", link, ns, err)
+		return err
+	}
+
+	return nil
+}
@@ -4,16 +4,36 @@
 package link
 
 import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
 	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/vmware/pmd-next-gen/pkg/validator"
 )
 
+// Link describes runtime changes of a link. Action is "up" or "down", Enslave
+// lists the links to attach to this bond, bridge or VRF and Namespace is a
+// network namespace name created by "ip netns add" or a pid. Empty fields are
+// left alone.
 type Link struct {
-	Action  string   `json:"Action"`
-	Name    string   `json:"Name"`
-	MTU     string   `json:"MTU"`
-	Kind    string   `json:"Kind"`
-	Mode    string   `json:"Mode"`
-	Enslave []string `json:"Enslave"`
+	Action        string   `json:"Action"`
+	Name          string   `json:"Name"`
+	MTU           string   `json:"MTU"`
+	Kind          string   `json:"Kind"`
+	Mode          string   `json:"Mode"`
+	Enslave       []string `json:"Enslave"`
+	TxQueueLength string   `json:"TxQueueLength"`
+	Alias         string   `json:"Alias"`
+	MACAddress    string   `json:"MACAddress"`
+	Promiscuous   string   `json:"Promiscuous"`
+	Master        string   `json:"Master"`
+	NoMaster      bool     `json:"NoMaster"`
+	Namespace     string   `json:"Namespace"`
 }
 
 type LinkInfo struct {
@@ -94,3 +114,138 @@ func AcquireLinks() ([]LinkInfo, error) {
 
 	return j, nil
 }
+
+func AcquireLink(name string) (*LinkInfo, error) {
+	l, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := fillOneLink(l)
+	return &info, nil
+}
+
+func decodeJSONRequest(r *http.Request) (*Link, error) {
+	l := Link{}
+	if err := json.NewDecoder(r.Body).Decode(&l); err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// Configure applies the requested changes in place with netlink. All values
+// are checked before the first change so a bad request leaves the link as is.
+func (l *Link) Configure() error {
+	if _, err := acquireLink(l.Name); err != nil {
+		return err
+	}
+
+	var mtu, qlen int
+	var err error
+	if !validator.IsEmpty(l.MTU) {
+		if mtu, err = strconv.Atoi(l.MTU); err != nil || mtu <= 0 {
+			return fmt.Errorf("invalid MTU='%s'", l.MTU)
+		}
+	}
+	if !validator.IsEmpty(l.TxQueueLength) {
+		if qlen, err = strconv.Atoi(l.TxQueueLength); err != nil || qlen < 0 {
+			return fmt.Errorf("invalid TxQueueLength='%s'", l.TxQueueLength)
+		}
+	}
+
+	var mac net.HardwareAddr
+	if !validator.IsEmpty(l.MACAddress) {
+		if mac, err = net.ParseMAC(l.MACAddress); err != nil {
+			return fmt.Errorf("invalid MACAddress='%s'", l.MACAddress)
+		}
+	}
+	if !validator.IsEmpty(l.Promiscuous) && !validator.IsBool(l.Promiscuous) {
+		return fmt.Errorf("invalid Promiscuous='%s'", l.Promiscuous)
+	}
+	if !validator.IsEmpty(l.Master) && l.NoMaster {
+		return fmt.Errorf("Master and NoMaster are mutually exclusive")
+	}
+
+	switch l.Action {
+	case "", "up", "down":
+	default:
+		return fmt.Errorf("invalid Action='%s'", l.Action)
+	}
+
+	// Links and the namespace referred to must exist as well
+	if !validator.IsEmpty(l.Master) {
+		if _, err := acquireLink(l.Master); err != nil {
+			return err
+		}
+	}
+	for _, s := range l.Enslave {
+		if _, err := acquireLink(s); err != nil {
+			return err
+		}
+	}
+
+	ns := netns.None()
+	if !validator.IsEmpty(l.Namespace) {
+		if ns, err = openNetNs(l.Namespace); err != nil {
+			return err
+		}
+		defer ns.Close()
+	}
+
+	if mtu > 0 {
+		if err := setMTU(l.Name, mtu); err != nil {
+			return err
+		}
+	}
+	if !validator.IsEmpty(l.TxQueueLength) {
+		if err := setTxQLen(l.Name, qlen); err != nil {
+			return err
+		}
+	}
+	if !validator.IsEmpty(l.Alias) {
+		if err := setAlias(l.Name, l.Alias); err != nil {
+			return err
+		}
+	}
+	if mac != nil {
+		if err := setHardwareAddr(l.Name, mac); err != nil {
+			return err
+		}
+	}
+	if !validator.IsEmpty(l.Promiscuous) {
+		if err := setPromisc(l.Name, validator.BoolToString(l.Promiscuous) == "yes"); err != nil {
+			return err
+		}
+	}
+
+	if !validator.IsEmpty(l.Master) {
+		if err := setMaster(l.Name, l.Master); err != nil {
+			return err
+		}
+	} else if l.NoMaster {
+		if err := setNoMaster(l.Name); err != nil {
+			return err
+		}
+	}
+	for _, s := range l.Enslave {
+		if err := setMaster(s, l.Name); err != nil {
+			return err
+		}
+	}
+
+	if l.Action != "" {
+		if err := setUp(l.Name, l.Action == "up"); err != nil {
+			return err
+		}
+	}
+
+	// The link disappears from this namespace, so this goes last
+	if !validator.IsEmpty(l.Namespace) {
+		if err := setNetNs(l.Name, l.Namespace, ns); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -9,19 +9,58 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
 )
 
 func routerAcquireLink(w http.ResponseWriter, r *http.Request) {
-	links, err := AcquireLinks()
+	var links []LinkInfo
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		links, err = AcquireLinks()
+		return err
+	})
 	if err != nil {
 		web.JSONResponseError(err, w)
+		return
 	}
 
 	web.JSONResponse(links, w)
 }
 
+func routerAcquireOneLink(w http.ResponseWriter, r *http.Request) {
+	var l *LinkInfo
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		l, err = AcquireLink(mux.Vars(r)["name"])
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(l, w)
+}
+
+func routerConfigureLink(w http.ResponseWriter, r *http.Request) {
+	l, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	l.Name = mux.Vars(r)["name"]
+	if err := namespace.Run(r.URL.Query().Get("netns"), l.Configure); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("configured", w)
+}
+
 func RegisterRouterLink(router *mux.Router) {
 	s := router.PathPrefix("/netlink").Subrouter().StrictSlash(false)
 
 	s.HandleFunc("/link", routerAcquireLink).Methods("GET")
+
+	router.HandleFunc("/link/{name}", routerAcquireOneLink).Methods("GET")
+	router.HandleFunc("/link/{name}", routerConfigureLink).Methods("POST")
 }
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package namespace
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// Named namespaces live where "ip netns" keeps them
+const netnsRunDir = "/run/netns"
+
+type Namespace struct {
+	Name string `json:"Name"`
+	ID   int    `json:"ID"`
+}
+
+// isValidName rejects names that would escape /run/netns
+func isValidName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.Contains(name, "/")
+}
+
+// Open returns a handle to a namespace named under /run/netns or, when there
+// is no such name and ns is a number, to the namespace of that pid.
+func Open(ns string) (netns.NsHandle, error) {
+	if _, err := os.Stat(path.Join(netnsRunDir, ns)); err != nil {
+		if pid, err := strconv.Atoi(ns); err == nil && pid > 0 {
+			return netns.GetFromPid(pid)
+		}
+	}
+
+	if !isValidName(ns) {
+		return -1, fmt.Errorf("invalid network namespace='%s'", ns)
+	}
+
+	return netns.GetFromPath(path.Join(netnsRunDir, ns))
+}
+
+// Run calls fn with the OS thread switched into the namespace. The netlink and
+// ioctl sockets opened by fn belong to it then. An empty ns runs fn in place.
+func Run(ns string, fn func() error) error {
+	if ns == "" {
+		return fn()
+	}
+
+	target, err := Open(ns)
+	if err != nil {
+		log.Errorf("Failed to open network namespace='%s': %v", ns, err)
+		return err
+	}
+	defer target.Close()
+
+	runtime.LockOSThread()
+
+	origin, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		return err
+	}
+	defer origin.Close()
+
+	if err := netns.Set(target); err != nil {
+		runtime.UnlockOSThread()
+		log.Errorf("Failed to enter network namespace='%s': %v", ns, err)
+		return err
+	}
+
+	defer func() {
+		// A thread that can not go back stays locked, the runtime drops it
+		// when the goroutine exits
+		if err := netns.Set(origin); err != nil {
+			log.Errorf("Failed to leave network namespace='%s': %v", ns, err)
+			return
+		}
+		runtime.UnlockOSThread()
+	}()
+
+	return fn()
+}
+
+func AcquireNamespaces() ([]Namespace, error) {
+	entries, err := os.ReadDir(netnsRunDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Namespace{}, nil
+		}
+		return nil, err
+	}
+
+	nss := []Namespace{}
+	for _, e := range entries {
+		n := Namespace{
+			Name: e.Name(),
+			ID:   -1,
+		}
+
+		if h, err := netns.GetFromPath(path.Join(netnsRunDir, e.Name())); err == nil {
+			if id, err := netlink.GetNetNsIdByFd(int(h)); err == nil {
+				n.ID = id
+			}
+			h.Close()
+		}
+
+		nss = append(nss, n)
+	}
+
+	return nss, nil
+}
+
+// prepareRunDir makes /run/netns a shared mount point like "ip netns add"
+// does, so the namespace mounts propagate to other mount namespaces.
+func prepareRunDir() error {
+	if err := os.MkdirAll(netnsRunDir, 0755); err != nil {
+		return err
+	}
+
+	err := unix.Mount("", netnsRunDir, "none", unix.MS_SHARED|unix.MS_REC, "")
+	if err == unix.EINVAL {
+		if err := unix.Mount(netnsRunDir, netnsRunDir, "none", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+			return err
+		}
+		err = unix.Mount("", netnsRunDir, "none", unix.MS_SHARED|unix.MS_REC, "")
+	}
+
+	return err
+}
+
+// Add creates a named namespace by bind mounting a new one over a file in
+// /run/netns
+func Add(name string) error {
+	if !isValidName(name) {
+		return fmt.Errorf("invalid network namespace='%s'", name)
+	}
+
+	if err := prepareRunDir(); err != nil {
+		log.Errorf("Failed to prepare '%s': %v", netnsRunDir, err)
+		return err
+	}
+
+	p := path.Join(netnsRunDir, name)
+	f, err := os.OpenFile(p, os.O_RDONLY|os.O_CREATE|os.O_EXCL, 0444)
+	if err != nil {
+		log.Errorf("Failed to create network namespace='%s': %v", name, err)
+		return err
+	}
+	f.Close()
+
+	runtime.LockOSThread()
+
+	origin, err := netns.Get()
+	if err != nil {
+		runtime.UnlockOSThread()
+		os.Remove(p)
+		return err
+	}
+	defer origin.Close()
+
+	ns, err := netns.New()
+	if err != nil {
+		runtime.UnlockOSThread()
+		os.Remove(p)
+		log.Errorf("Failed to create network namespace='%s': %v", name, err)
+		return err
+	}
+	defer ns.Close()
+
+	err = unix.Mount(fmt.Sprintf("/proc/%d/task/%d/ns/net", os.Getpid(), unix.Gettid()), p, "none", unix.MS_BIND, "")
+
+	if err := netns.Set(origin); err != nil {
+		log.Errorf("Failed to leave network namespace='%s': %v", name, err)
+	} else {
+		runtime.UnlockOSThread()
+	}
+
+	if err != nil {
+		os.Remove(p)
+		log.Errorf("Failed to mount network namespace='%s': %v", name, err)
+		return err
+	}
+
+	return nil
+}
+
+func Remove(name string) error {
+	if !isValidName(name) {
+		return fmt.Errorf("invalid network namespace='%s'", name)
+	}
+
+	p := path.Join(netnsRunDir, name)
+	if err := unix.Unmount(p, unix.MNT_DETACH); err != nil && err != unix.EINVAL {
+		log.Errorf("Failed to unmount network namespace='%s': %v", name, err)
+		return err
+	}
+
+	if err := os.Remove(p); err != nil {
+		log.Errorf("Failed to remove network namespace='%s': %v", name, err)
+		return err
+	}
+
+	return nil
+}
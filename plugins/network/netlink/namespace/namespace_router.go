@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package namespace
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vmware/pmd-next-gen/pkg/web"
+)
+
+func routerAcquireNamespaces(w http.ResponseWriter, r *http.Request) {
+	nss, err := AcquireNamespaces()
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(nss, w)
+}
+
+func routerAddNamespace(w http.ResponseWriter, r *http.Request) {
+	if err := Add(mux.Vars(r)["name"]); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("added", w)
+}
+
+func routerRemoveNamespace(w http.ResponseWriter, r *http.Request) {
+	if err := Remove(mux.Vars(r)["name"]); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("removed", w)
+}
+
+func RegisterRouterNamespace(router *mux.Router) {
+	router.HandleFunc("/netns", routerAcquireNamespaces).Methods("GET")
+	router.HandleFunc("/netns/{name}", routerAddNamespace).Methods("POST")
+	router.HandleFunc("/netns/{name}", routerRemoveNamespace).Methods("DELETE")
+}
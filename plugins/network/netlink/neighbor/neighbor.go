@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package neighbor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Neighbor is an ARP or NDP entry. The same struct is used to add and remove
+// entries, State defaults to PERMANENT then.
+type Neighbor struct {
+	Link       string `json:"Link"`
+	Ifindex    int    `json:"Ifindex"`
+	Family     string `json:"Family"`
+	IP         string `json:"IP"`
+	MACAddress string `json:"MACAddress"`
+	State      string `json:"State"`
+	Router     bool   `json:"Router"`
+	Proxy      bool   `json:"Proxy"`
+}
+
+// NeighborFilter selects the entries returned by AcquireNeighbors
+type NeighborFilter struct {
+	Link   string
+	Family string
+	Proxy  bool
+}
+
+var neighborStates = map[string]int{
+	"INCOMPLETE": netlink.NUD_INCOMPLETE,
+	"REACHABLE":  netlink.NUD_REACHABLE,
+	"STALE":      netlink.NUD_STALE,
+	"DELAY":      netlink.NUD_DELAY,
+	"PROBE":      netlink.NUD_PROBE,
+	"FAILED":     netlink.NUD_FAILED,
+	"NOARP":      netlink.NUD_NOARP,
+	"PERMANENT":  netlink.NUD_PERMANENT,
+}
+
+func decodeJSONRequest(r *http.Request) (*Neighbor, error) {
+	n := Neighbor{}
+	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+		return nil, err
+	}
+
+	return &n, nil
+}
+
+func stateToString(state int) string {
+	if state == netlink.NUD_NONE {
+		return "NONE"
+	}
+
+	for k, v := range neighborStates {
+		if state&v != 0 {
+			return k
+		}
+	}
+
+	return strconv.Itoa(state)
+}
+
+func parseFamily(family string) (int, error) {
+	switch family {
+	case "", "all":
+		return netlink.FAMILY_ALL, nil
+	case "ipv4":
+		return netlink.FAMILY_V4, nil
+	case "ipv6":
+		return netlink.FAMILY_V6, nil
+	}
+
+	return 0, fmt.Errorf("invalid family='%s'", family)
+}
+
+func familyToString(family int) string {
+	if family == netlink.FAMILY_V6 {
+		return "ipv6"
+	}
+
+	return "ipv4"
+}
+
+func fillOneNeighbor(n *netlink.Neigh, links map[int]string) Neighbor {
+	nb := Neighbor{
+		Link:    links[n.LinkIndex],
+		Ifindex: n.LinkIndex,
+		Family:  familyToString(n.Family),
+		State:   stateToString(n.State),
+		Router:  n.Flags&netlink.NTF_ROUTER != 0,
+		Proxy:   n.Flags&netlink.NTF_PROXY != 0,
+	}
+
+	if n.IP != nil {
+		nb.IP = n.IP.String()
+	}
+	if n.HardwareAddr != nil {
+		nb.MACAddress = n.HardwareAddr.String()
+	}
+
+	return nb
+}
+
+func AcquireNeighbors(f *NeighborFilter) ([]Neighbor, error) {
+	family, err := parseFamily(f.Family)
+	if err != nil {
+		return nil, err
+	}
+
+	index := 0
+	if f.Link != "" {
+		l, err := netlink.LinkByName(f.Link)
+		if err != nil {
+			return nil, err
+		}
+		index = l.Attrs().Index
+	}
+
+	var neighs []netlink.Neigh
+	if f.Proxy {
+		neighs, err = netlink.NeighProxyList(index, family)
+	} else {
+		neighs, err = netlink.NeighList(index, family)
+	}
+	if err != nil {
+		log.Errorf("Failed to acquire neighbors: %v", err)
+		return nil, err
+	}
+
+	links := map[int]string{}
+	if ll, err := netlink.LinkList(); err == nil {
+		for _, l := range ll {
+			links[l.Attrs().Index] = l.Attrs().Name
+		}
+	}
+
+	nbs := []Neighbor{}
+	for _, n := range neighs {
+		// Dumping AF_UNSPEC returns the bridge FDB too
+		if n.Family != netlink.FAMILY_V4 && n.Family != netlink.FAMILY_V6 {
+			continue
+		}
+
+		nbs = append(nbs, fillOneNeighbor(&n, links))
+	}
+
+	return nbs, nil
+}
+
+func (nb *Neighbor) buildNeigh() (*netlink.Neigh, error) {
+	l, err := netlink.LinkByName(nb.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(nb.IP)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP='%s'", nb.IP)
+	}
+
+	n := netlink.Neigh{
+		LinkIndex: l.Attrs().Index,
+		Family:    netlink.FAMILY_V6,
+		IP:        ip,
+	}
+	if ip.To4() != nil {
+		n.Family = netlink.FAMILY_V4
+	}
+
+	if nb.MACAddress != "" {
+		if n.HardwareAddr, err = net.ParseMAC(nb.MACAddress); err != nil {
+			return nil, err
+		}
+	}
+
+	if nb.Proxy {
+		n.Flags |= netlink.NTF_PROXY
+	}
+	if nb.Router {
+		n.Flags |= netlink.NTF_ROUTER
+	}
+
+	return &n, nil
+}
+
+// Add creates or replaces an entry like "ip neigh replace" does
+func (nb *Neighbor) Add() error {
+	n, err := nb.buildNeigh()
+	if err != nil {
+		log.Errorf("Failed to parse neighbor IP='%s' link='%s': %v", nb.IP, nb.Link, err)
+		return err
+	}
+
+	if !nb.Proxy {
+		if n.HardwareAddr == nil {
+			return fmt.Errorf("missing MAC address of neighbor IP='%s'", nb.IP)
+		}
+
+		n.State = netlink.NUD_PERMANENT
+		if nb.State != "" {
+			state, ok := neighborStates[nb.State]
+			if !ok {
+				return fmt.Errorf("invalid state='%s'", nb.State)
+			}
+			n.State = state
+		}
+	}
+
+	if err := netlink.NeighSet(n); err != nil {
+		log.Errorf("Failed to add neighbor IP='%s' link='%s': %v", nb.IP, nb.Link, err)
+		return err
+	}
+
+	return nil
+}
+
+func (nb *Neighbor) Remove() error {
+	n, err := nb.buildNeigh()
+	if err != nil {
+		log.Errorf("Failed to parse neighbor IP='%s' link='%s': %v", nb.IP, nb.Link, err)
+		return err
+	}
+
+	if err := netlink.NeighDel(n); err != nil {
+		log.Errorf("Failed to remove neighbor IP='%s' link='%s': %v", nb.IP, nb.Link, err)
+		return err
+	}
+
+	return nil
+}
+
+// FlushNeighbors removes the learned entries of the link like "ip neigh flush
+// dev" does. Static ones are kept unless permanent is set.
+func FlushNeighbors(link string, permanent bool) error {
+	l, err := netlink.LinkByName(link)
+	if err != nil {
+		return err
+	}
+
+	neighs, err := netlink.NeighList(l.Attrs().Index, netlink.FAMILY_ALL)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range neighs {
+		if n.Family != netlink.FAMILY_V4 && n.Family != netlink.FAMILY_V6 {
+			continue
+		}
+		if !permanent && n.State&(netlink.NUD_PERMANENT|netlink.NUD_NOARP) != 0 {
+			continue
+		}
+
+		if err := netlink.NeighDel(&n); err != nil && err != unix.ENOENT {
+			log.Errorf("Failed to flush neighbor IP='%s' link='%s': %v", n.IP, link, err)
+			return err
+		}
+	}
+
+	return nil
+}
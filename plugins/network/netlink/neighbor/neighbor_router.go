@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package neighbor
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
+)
+
+func routerAcquireNeighbors(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var nbs []Neighbor
+	err := namespace.Run(q.Get("netns"), func() (err error) {
+		nbs, err = AcquireNeighbors(&NeighborFilter{
+			Link:   q.Get("link"),
+			Family: q.Get("family"),
+			Proxy:  q.Get("proxy") == "true",
+		})
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(nbs, w)
+}
+
+func routerAddNeighbor(w http.ResponseWriter, r *http.Request) {
+	nb, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), nb.Add); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("added", w)
+}
+
+func routerRemoveNeighbor(w http.ResponseWriter, r *http.Request) {
+	nb, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), nb.Remove); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("removed", w)
+}
+
+func routerFlushNeighbors(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	err := namespace.Run(q.Get("netns"), func() error {
+		return FlushNeighbors(mux.Vars(r)["link"], q.Get("permanent") == "true")
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("flushed", w)
+}
+
+func RegisterRouterNeighbor(router *mux.Router) {
+	s := router.PathPrefix("/netlink").Subrouter().StrictSlash(false)
+
+	s.HandleFunc("/neighbor", routerAcquireNeighbors).Methods("GET")
+	s.HandleFunc("/neighbor", routerAddNeighbor).Methods("POST")
+	s.HandleFunc("/neighbor", routerRemoveNeighbor).Methods("DELETE")
+	s.HandleFunc("/neighbor/{link}/flush", routerFlushNeighbors).Methods("POST")
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package qdisc
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+type Stats struct {
+	Bytes      uint64 `json:"Bytes"`
+	Packets    uint32 `json:"Packets"`
+	Qlen       uint32 `json:"Qlen"`
+	Backlog    uint32 `json:"Backlog"`
+	Drops      uint32 `json:"Drops"`
+	Requeues   uint32 `json:"Requeues"`
+	Overlimits uint32 `json:"Overlimits"`
+}
+
+// Object is a queueing discipline or a class, both share the tcmsg layout
+type Object struct {
+	Kind   string `json:"Kind"`
+	Handle string `json:"Handle"`
+	Parent string `json:"Parent"`
+	Stats  Stats  `json:"Stats"`
+}
+
+type LinkTrafficControl struct {
+	Link    string   `json:"Link"`
+	Ifindex int      `json:"Ifindex"`
+	QDiscs  []Object `json:"QDiscs"`
+	Classes []Object `json:"Classes"`
+}
+
+func parseStats2(b []byte, s *Stats) error {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range attrs {
+		switch a.Attr.Type {
+		case nl.TCA_STATS_BASIC:
+			basic := netlink.GnetStatsBasic{}
+			if err := binary.Read(bytes.NewReader(a.Value), nl.NativeEndian(), &basic); err != nil {
+				return err
+			}
+			s.Bytes = basic.Bytes
+			s.Packets = basic.Packets
+		case nl.TCA_STATS_QUEUE:
+			queue := netlink.GnetStatsQueue{}
+			if err := binary.Read(bytes.NewReader(a.Value), nl.NativeEndian(), &queue); err != nil {
+				return err
+			}
+			s.Qlen = queue.Qlen
+			s.Backlog = queue.Backlog
+			s.Drops = queue.Drops
+			s.Requeues = queue.Requeues
+			s.Overlimits = queue.Overlimits
+		}
+	}
+
+	return nil
+}
+
+func dumpObjects(ifindex int, cmd int) ([]Object, error) {
+	req := nl.NewNetlinkRequest(cmd, unix.NLM_F_DUMP)
+	req.AddData(&nl.TcMsg{
+		Family:  nl.FAMILY_ALL,
+		Ifindex: int32(ifindex),
+	})
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []Object{}
+	for _, m := range msgs {
+		msg := nl.DeserializeTcMsg(m)
+
+		// Qdisc dumps are not filtered by the kernel
+		if int(msg.Ifindex) != ifindex {
+			continue
+		}
+
+		o := Object{
+			Handle: netlink.HandleStr(msg.Handle),
+			Parent: netlink.HandleStr(msg.Parent),
+		}
+
+		attrs, err := nl.ParseRouteAttr(m[msg.Len():])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range attrs {
+			switch a.Attr.Type {
+			case nl.TCA_KIND:
+				o.Kind = string(bytes.TrimRight(a.Value, "\x00"))
+			case nl.TCA_STATS2:
+				if err := parseStats2(a.Value, &o.Stats); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		objs = append(objs, o)
+	}
+
+	return objs, nil
+}
+
+func AcquireLinkTrafficControl(link string) (*LinkTrafficControl, error) {
+	l, err := netlink.LinkByName(link)
+	if err != nil {
+		return nil, err
+	}
+
+	tc := LinkTrafficControl{
+		Link:    link,
+		Ifindex: l.Attrs().Index,
+	}
+
+	tc.QDiscs, err = dumpObjects(tc.Ifindex, unix.RTM_GETQDISC)
+	if err != nil {
+		log.Errorf("Failed to acquire qdiscs of link='%s': %v", link, err)
+		return nil, err
+	}
+
+	tc.Classes, err = dumpObjects(tc.Ifindex, unix.RTM_GETTCLASS)
+	if err != nil {
+		log.Errorf("Failed to acquire classes of link='%s': %v", link, err)
+		return nil, err
+	}
+
+	return &tc, nil
+}
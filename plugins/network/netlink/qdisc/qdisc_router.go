@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package qdisc
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
+)
+
+func routerAcquireLinkTrafficControl(w http.ResponseWriter, r *http.Request) {
+	var tc *LinkTrafficControl
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		tc, err = AcquireLinkTrafficControl(mux.Vars(r)["link"])
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(tc, w)
+}
+
+func RegisterRouterQDisc(router *mux.Router) {
+	s := router.PathPrefix("/netlink").Subrouter().StrictSlash(false)
+
+	s.HandleFunc("/qdisc/{link}", routerAcquireLinkTrafficControl).Methods("GET")
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package route
+
+import (
+	"net"
+	"unsafe"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// NextHopObject is a nexthop created with RTM_NEWNEXTHOP, e.g. from a
+// networkd [NextHop] section
+type NextHopObject struct {
+	Id        uint32              `json:"Id"`
+	Family    string              `json:"Family"`
+	Protocol  int                 `json:"Protocol"`
+	LinkName  string              `json:"LinkName"`
+	LinkIndex int                 `json:"LinkIndex"`
+	Gateway   string              `json:"Gateway"`
+	Blackhole bool                `json:"Blackhole"`
+	OnLink    bool                `json:"OnLink"`
+	Group     []NextHopGroupEntry `json:"Group"`
+}
+
+type NextHopGroupEntry struct {
+	Id     uint32 `json:"Id"`
+	Weight int    `json:"Weight"`
+}
+
+// struct nhmsg from linux/nexthop.h
+type nhMsg struct {
+	Family   uint8
+	Scope    uint8
+	Protocol uint8
+	Resvd    uint8
+	Flags    uint32
+}
+
+const sizeofNhMsg = 8
+
+// struct nexthop_grp from linux/nexthop.h
+const sizeofNextHopGrp = 8
+
+func (m *nhMsg) Len() int {
+	return sizeofNhMsg
+}
+
+func (m *nhMsg) Serialize() []byte {
+	return (*(*[sizeofNhMsg]byte)(unsafe.Pointer(m)))[:]
+}
+
+func parseNextHopObject(b []byte) (*NextHopObject, error) {
+	msg := (*nhMsg)(unsafe.Pointer(&b[0:sizeofNhMsg][0]))
+
+	nh := NextHopObject{
+		Protocol: int(msg.Protocol),
+		OnLink:   msg.Flags&unix.RTNH_F_ONLINK != 0,
+	}
+
+	switch msg.Family {
+	case unix.AF_INET:
+		nh.Family = "ipv4"
+	case unix.AF_INET6:
+		nh.Family = "ipv6"
+	}
+
+	attrs, err := nl.ParseRouteAttr(b[sizeofNhMsg:])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range attrs {
+		switch a.Attr.Type {
+		case unix.NHA_ID:
+			nh.Id = nl.NativeEndian().Uint32(a.Value)
+		case unix.NHA_OIF:
+			nh.LinkIndex = int(nl.NativeEndian().Uint32(a.Value))
+			if l, err := netlink.LinkByIndex(nh.LinkIndex); err == nil {
+				nh.LinkName = l.Attrs().Name
+			}
+		case unix.NHA_GATEWAY:
+			nh.Gateway = net.IP(a.Value).String()
+		case unix.NHA_BLACKHOLE:
+			nh.Blackhole = true
+		case unix.NHA_GROUP:
+			for i := 0; i+sizeofNextHopGrp <= len(a.Value); i += sizeofNextHopGrp {
+				nh.Group = append(nh.Group, NextHopGroupEntry{
+					Id: nl.NativeEndian().Uint32(a.Value[i : i+4]),
+					// Like rtnh_hops the kernel keeps weight - 1
+					Weight: int(a.Value[i+4]) + 1,
+				})
+			}
+		}
+	}
+
+	return &nh, nil
+}
+
+func AcquireNextHops() ([]NextHopObject, error) {
+	req := nl.NewNetlinkRequest(unix.RTM_GETNEXTHOP, unix.NLM_F_DUMP)
+	req.AddData(&nhMsg{
+		Family: unix.AF_UNSPEC,
+	})
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWNEXTHOP)
+	if err != nil {
+		log.Errorf("Failed to acquire nexthops: %v", err)
+		return nil, err
+	}
+
+	nhs := []NextHopObject{}
+	for _, m := range msgs {
+		if len(m) < sizeofNhMsg {
+			continue
+		}
+
+		nh, err := parseNextHopObject(m)
+		if err != nil {
+			return nil, err
+		}
+
+		nhs = append(nhs, *nh)
+	}
+
+	return nhs, nil
+}
@@ -5,14 +5,18 @@ package route
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
 	"syscall"
 
-	"github.com/vmware/pmd-next-gen/pkg/parser"
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/vmware/pmd-next-gen/pkg/parser"
 )
 
 type Route struct {
@@ -20,6 +24,44 @@ type Route struct {
 	Link    string `json:"link"`
 	Gateway string `json:"gateway"`
 	OnLink  string `json:"onlink"`
+
+	Destination string         `json:"destination"`
+	Source      string         `json:"source"`
+	Table       string         `json:"table"`
+	Metric      int            `json:"metric"`
+	Protocol    string         `json:"protocol"`
+	Scope       string         `json:"scope"`
+	Type        string         `json:"type"`
+	Tos         int            `json:"tos"`
+	MTU         int            `json:"mtu"`
+	AdvMSS      int            `json:"advmss"`
+	Hoplimit    int            `json:"hoplimit"`
+	MultiPath   []RouteNextHop `json:"multipath"`
+	Encap       *RouteEncap    `json:"encap"`
+}
+
+type RouteNextHop struct {
+	Gateway string `json:"gateway"`
+	Link    string `json:"link"`
+	Weight  int    `json:"weight"`
+	OnLink  bool   `json:"onlink"`
+}
+
+// RouteEncap is a lightweight tunnel, either an MPLS label stack or SRv6 segments
+type RouteEncap struct {
+	Type     string   `json:"type"`
+	Labels   []int    `json:"labels"`
+	Segments []string `json:"segments"`
+	Mode     string   `json:"mode"`
+}
+
+// RouteFilter selects the routes returned by AcquireRoutesFiltered. An empty
+// Table means the main table like "ip route" does, "all" means every table.
+type RouteFilter struct {
+	Table    string
+	Family   string
+	Link     string
+	Protocol string
 }
 
 type RouteInfo struct {
@@ -27,25 +69,40 @@ type RouteInfo struct {
 	LinkIndex  int    `json:"LinkIndex"`
 	ILinkIndex int    `json:"ILinkIndex"`
 	Scope      int    `json:"Scope"`
+	ScopeName  string `json:"ScopeName"`
 	Dst        struct {
 		IP   string `json:"IP"`
 		Mask int    `json:"Mask"`
 	} `json:"Dst"`
-	Src       string   `json:"Src"`
+	Src       string    `json:"Src"`
+	Gw        string    `json:"Gw"`
+	MultiPath string    `json:"MultiPath"`
+	NextHops  []NextHop `json:"NextHops"`
+	Protocol  int       `json:"Protocol"`
+	Priority  int       `json:"Priority"`
+	Table     int       `json:"Table"`
+	Type      int       `json:"Type"`
+	Tos       int       `json:"Tos"`
+	Flags     []string  `json:"Flags"`
+	MPLSDst   string    `json:"MPLSDst"`
+	NewDst    string    `json:"NewDst"`
+	Encap     string    `json:"Encap"`
+	Mtu       int       `json:"MTU"`
+	AdvMSS    int       `json:"AdvMSS"`
+	Hoplimit  int       `json:"Hoplimit"`
+
+	ProtocolName string `json:"ProtocolName"`
+	TableName    string `json:"TableName"`
+	TypeName     string `json:"TypeName"`
+}
+
+// NextHop is one path of a multipath route
+type NextHop struct {
+	LinkName  string   `json:"LinkName"`
+	LinkIndex int      `json:"LinkIndex"`
 	Gw        string   `json:"Gw"`
-	MultiPath string   `json:"MultiPath"`
-	Protocol  int      `json:"Protocol"`
-	Priority  int      `json:"Priority"`
-	Table     int      `json:"Table"`
-	Type      int      `json:"Type"`
-	Tos       int      `json:"Tos"`
+	Weight    int      `json:"Weight"`
 	Flags     []string `json:"Flags"`
-	MPLSDst   string   `json:"MPLSDst"`
-	NewDst    string   `json:"NewDst"`
-	Encap     string   `json:"Encap"`
-	Mtu       int      `json:"MTU"`
-	AdvMSS    int      `json:"AdvMSS"`
-	Hoplimit  int      `json:"Hoplimit"`
 }
 
 func decodeJSONRequest(r *http.Request) (*Route, error) {
@@ -162,26 +219,62 @@ func (rt *Route) RemoveGateWay() error {
 	return nil
 }
 
-func fillOneRoute(rt *netlink.Route) *RouteInfo {
-	link, err := netlink.LinkByIndex(rt.LinkIndex)
-	if err != nil {
-		log.Debugf("Failed to acquire link ifindex='%d': %v", rt.LinkIndex, err)
-		return nil
+func fillNextHops(rt *netlink.Route) []NextHop {
+	var nhs []NextHop
+	for _, p := range rt.MultiPath {
+		nh := NextHop{
+			LinkIndex: p.LinkIndex,
+			// The kernel stores weight - 1 in rtnh_hops
+			Weight: p.Hops + 1,
+		}
+
+		if l, err := netlink.LinkByIndex(p.LinkIndex); err == nil {
+			nh.LinkName = l.Attrs().Name
+		}
+		if p.Gw != nil {
+			nh.Gw = p.Gw.String()
+		}
+		if p.Flags&syscall.RTNH_F_ONLINK != 0 {
+			nh.Flags = append(nh.Flags, "onlink")
+		}
+		if p.Flags&syscall.RTNH_F_PERVASIVE != 0 {
+			nh.Flags = append(nh.Flags, "pervasive")
+		}
+
+		nhs = append(nhs, nh)
+	}
+
+	return nhs
+}
+
+func fillOneRoute(rt *netlink.Route, names *routeNames) *RouteInfo {
+	name := ""
+	if rt.LinkIndex > 0 {
+		link, err := netlink.LinkByIndex(rt.LinkIndex)
+		if err != nil {
+			log.Debugf("Failed to acquire link ifindex='%d': %v", rt.LinkIndex, err)
+			return nil
+		}
+		name = link.Attrs().Name
 	}
 
 	route := RouteInfo{
-		LinkName:   link.Attrs().Name,
-		LinkIndex:  rt.LinkIndex,
-		ILinkIndex: rt.ILinkIndex,
-		Scope:      int(rt.Scope),
-		Protocol:   rt.Protocol,
-		Priority:   rt.Priority,
-		Table:      rt.Table,
-		Type:       rt.Type,
-		Tos:        rt.Tos,
-		Mtu:        rt.MTU,
-		AdvMSS:     rt.AdvMSS,
-		Hoplimit:   rt.Hoplimit,
+		LinkName:     name,
+		LinkIndex:    rt.LinkIndex,
+		ILinkIndex:   rt.ILinkIndex,
+		Scope:        int(rt.Scope),
+		ScopeName:    lookupName(names.scopes, int(rt.Scope)),
+		Protocol:     rt.Protocol,
+		Priority:     rt.Priority,
+		Table:        rt.Table,
+		Type:         rt.Type,
+		Tos:          rt.Tos,
+		Mtu:          rt.MTU,
+		AdvMSS:       rt.AdvMSS,
+		Hoplimit:     rt.Hoplimit,
+		ProtocolName: lookupName(names.protocols, rt.Protocol),
+		TableName:    lookupName(names.tables, rt.Table),
+		TypeName:     lookupName(names.types, rt.Type),
 	}
 
 	if rt.Gw != nil {
@@ -201,17 +294,43 @@ func fillOneRoute(rt *netlink.Route) *RouteInfo {
 		route.Flags = rt.ListFlags()
 	}
 
+	if rt.MPLSDst != nil {
+		route.MPLSDst = fmt.Sprintf("%d", *rt.MPLSDst)
+	}
+
+	if rt.NewDst != nil {
+		route.NewDst = rt.NewDst.String()
+	}
+
+	if rt.Encap != nil {
+		route.Encap = rt.Encap.String()
+	}
+
+	if len(rt.MultiPath) > 0 {
+		route.NextHops = fillNextHops(rt)
+
+		var paths []string
+		for _, p := range rt.MultiPath {
+			paths = append(paths, p.String())
+		}
+		route.MultiPath = strings.Join(paths, " ")
+	}
+
 	return &route
 }
 
 func buildRouteList(routes []netlink.Route) []RouteInfo {
+	names := loadRouteNames()
+
 	var rts []RouteInfo
 	for _, rt := range routes {
-		if rt.LinkIndex == 0 {
+		// Unicast routes without a device are cloned entries of no interest,
+		// blackhole and friends legitimately have none
+		if rt.LinkIndex == 0 && len(rt.MultiPath) == 0 && rt.Type == unix.RTN_UNICAST {
 			continue
 		}
 
-		route := fillOneRoute(&rt)
+		route := fillOneRoute(&rt, names)
 		if route != nil {
 			rts = append(rts, *route)
 		}
@@ -229,12 +348,342 @@ func AcquireRoutes() ([]RouteInfo, error) {
 	return buildRouteList(routes), nil
 }
 
+func parseFamily(family string) (int, error) {
+	switch family {
+	case "", "all":
+		return netlink.FAMILY_ALL, nil
+	case "ipv4", "inet":
+		return netlink.FAMILY_V4, nil
+	case "ipv6", "inet6":
+		return netlink.FAMILY_V6, nil
+	}
+
+	return 0, fmt.Errorf("invalid family='%s'", family)
+}
+
+func AcquireRoutesFiltered(f *RouteFilter) ([]RouteInfo, error) {
+	family, err := parseFamily(f.Family)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := netlink.Route{}
+	var mask uint64
+
+	switch f.Table {
+	case "":
+	case "all":
+		filter.Table = unix.RT_TABLE_UNSPEC
+		mask |= netlink.RT_FILTER_TABLE
+	default:
+		if filter.Table, err = ParseTable(f.Table); err != nil {
+			return nil, err
+		}
+		mask |= netlink.RT_FILTER_TABLE
+	}
+
+	if f.Protocol != "" {
+		if filter.Protocol, err = ParseProtocol(f.Protocol); err != nil {
+			return nil, err
+		}
+		mask |= netlink.RT_FILTER_PROTOCOL
+	}
+
+	routes, err := netlink.RouteListFiltered(family, &filter, mask)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Link == "" {
+		return buildRouteList(routes), nil
+	}
+
+	link, err := netlink.LinkByName(f.Link)
+	if err != nil {
+		return nil, err
+	}
+
+	// RT_FILTER_OIF misses multipath routes, they carry the links in their nexthops
+	var linkRoutes []netlink.Route
+	for _, rt := range routes {
+		if rt.LinkIndex == link.Attrs().Index {
+			linkRoutes = append(linkRoutes, rt)
+			continue
+		}
+
+		for _, nh := range rt.MultiPath {
+			if nh.LinkIndex == link.Attrs().Index {
+				linkRoutes = append(linkRoutes, rt)
+				break
+			}
+		}
+	}
+
+	return buildRouteList(linkRoutes), nil
+}
+
+// AcquireRouteGet resolves the route the kernel would use for the destination
+func AcquireRouteGet(destination string) ([]RouteInfo, error) {
+	ip := net.ParseIP(destination)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid destination='%s'", destination)
+	}
+
+	routes, err := netlink.RouteGet(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	names := loadRouteNames()
+
+	rts := []RouteInfo{}
+	for _, rt := range routes {
+		if route := fillOneRoute(&rt, names); route != nil {
+			rts = append(rts, *route)
+		}
+	}
+
+	return rts, nil
+}
+
+func parseRouteIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid address='%s'", s)
+	}
+
+	return ip, nil
+}
+
+func (e *RouteEncap) build() (netlink.Encap, error) {
+	switch e.Type {
+	case "mpls":
+		if len(e.Labels) == 0 {
+			return nil, fmt.Errorf("mpls encap needs at least one label")
+		}
+
+		return &netlink.MPLSEncap{Labels: e.Labels}, nil
+	case "seg6":
+		encap := netlink.SEG6Encap{}
+		switch e.Mode {
+		case "", "encap":
+			encap.Mode = nl.SEG6_IPTUN_MODE_ENCAP
+		case "inline":
+			encap.Mode = nl.SEG6_IPTUN_MODE_INLINE
+		default:
+			return nil, fmt.Errorf("invalid seg6 mode='%s'", e.Mode)
+		}
+
+		for _, seg := range e.Segments {
+			ip := net.ParseIP(seg)
+			if ip == nil || ip.To4() != nil {
+				return nil, fmt.Errorf("invalid seg6 segment='%s'", seg)
+			}
+			encap.Segments = append(encap.Segments, ip)
+		}
+		if len(encap.Segments) == 0 {
+			return nil, fmt.Errorf("seg6 encap needs at least one segment")
+		}
+
+		return &encap, nil
+	}
+
+	return nil, fmt.Errorf("invalid encap type='%s'", e.Type)
+}
+
+// buildRoute translates the request to a netlink route. Names are resolved
+// from /etc/iproute2 so that the tables and protocols of "ip route" work too.
+func (rt *Route) buildRoute() (*netlink.Route, error) {
+	route := netlink.Route{
+		Priority: rt.Metric,
+		Tos:      rt.Tos,
+		MTU:      rt.MTU,
+		AdvMSS:   rt.AdvMSS,
+		Hoplimit: rt.Hoplimit,
+	}
+	var err error
+
+	if rt.Link != "" {
+		link, err := netlink.LinkByName(rt.Link)
+		if err != nil {
+			return nil, err
+		}
+		route.LinkIndex = link.Attrs().Index
+	}
+
+	if rt.Gateway != "" {
+		if route.Gw, err = parseRouteIP(rt.Gateway); err != nil {
+			return nil, err
+		}
+	}
+
+	if rt.Source != "" {
+		if route.Src, err = parseRouteIP(rt.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	switch rt.Destination {
+	case "":
+		return nil, fmt.Errorf("missing destination")
+	case "default":
+		// The family follows the gateway or source, of the route or of its next hops
+		ipv6 := (route.Gw != nil && route.Gw.To4() == nil) || (route.Src != nil && route.Src.To4() == nil)
+		for _, nh := range rt.MultiPath {
+			if ip := net.ParseIP(nh.Gateway); ip != nil && ip.To4() == nil {
+				ipv6 = true
+			}
+		}
+
+		route.Dst = &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 32)}
+		if ipv6 {
+			route.Dst = &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, 128)}
+		}
+	default:
+		if !strings.Contains(rt.Destination, "/") {
+			ip, err := parseRouteIP(rt.Destination)
+			if err != nil {
+				return nil, err
+			}
+			bits := 128
+			if ip.To4() != nil {
+				bits = 32
+			}
+			route.Dst = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		} else if _, route.Dst, err = net.ParseCIDR(rt.Destination); err != nil {
+			return nil, err
+		}
+	}
+
+	if rt.Table != "" {
+		if route.Table, err = ParseTable(rt.Table); err != nil {
+			return nil, err
+		}
+	}
+
+	if rt.Protocol != "" {
+		if route.Protocol, err = ParseProtocol(rt.Protocol); err != nil {
+			return nil, err
+		}
+	}
+
+	if rt.Type != "" {
+		if route.Type, err = ParseType(rt.Type); err != nil {
+			return nil, err
+		}
+	}
+
+	if rt.Scope != "" {
+		scope, err := ParseScope(rt.Scope)
+		if err != nil {
+			return nil, err
+		}
+		route.Scope = netlink.Scope(scope)
+	}
+
+	b, err := parser.ParseBool(strings.TrimSpace(rt.OnLink))
+	if err == nil && b {
+		route.Flags |= syscall.RTNH_F_ONLINK
+	}
+
+	for _, nh := range rt.MultiPath {
+		n := netlink.NexthopInfo{}
+		if nh.Link != "" {
+			link, err := netlink.LinkByName(nh.Link)
+			if err != nil {
+				return nil, err
+			}
+			n.LinkIndex = link.Attrs().Index
+		}
+
+		if nh.Gateway != "" {
+			if n.Gw, err = parseRouteIP(nh.Gateway); err != nil {
+				return nil, err
+			}
+		}
+
+		// The kernel stores weight - 1 in rtnh_hops
+		if nh.Weight > 0 {
+			n.Hops = nh.Weight - 1
+		}
+
+		if nh.OnLink {
+			n.Flags |= syscall.RTNH_F_ONLINK
+		}
+
+		route.MultiPath = append(route.MultiPath, &n)
+	}
+
+	if rt.Encap != nil {
+		if route.Encap, err = rt.Encap.build(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &route, nil
+}
+
+// AddRoute adds or with replace set replaces a route like "ip route add|replace"
+func (rt *Route) AddRoute(replace bool) error {
+	route, err := rt.buildRoute()
+	if err != nil {
+		log.Errorf("Failed to parse route destination='%s': %v", rt.Destination, err)
+		return err
+	}
+
+	// Pick the scope "ip route" would when not given
+	if rt.Scope == "" {
+		switch {
+		case route.Type == unix.RTN_LOCAL:
+			route.Scope = netlink.SCOPE_HOST
+		case route.Gw == nil && len(route.MultiPath) == 0 && (route.Type == 0 || route.Type == unix.RTN_UNICAST) && route.LinkIndex > 0:
+			route.Scope = netlink.SCOPE_LINK
+		}
+	}
+
+	if replace {
+		err = netlink.RouteReplace(route)
+	} else {
+		err = netlink.RouteAdd(route)
+	}
+	if err != nil {
+		log.Errorf("Failed to configure route destination='%s': %v", rt.Destination, err)
+		return err
+	}
+
+	return nil
+}
+
+func (rt *Route) RemoveRoute() error {
+	route, err := rt.buildRoute()
+	if err != nil {
+		log.Errorf("Failed to parse route destination='%s': %v", rt.Destination, err)
+		return err
+	}
+
+	// Let the kernel match any scope unless asked for one
+	if rt.Scope == "" {
+		route.Scope = netlink.SCOPE_NOWHERE
+	}
+
+	if err := netlink.RouteDel(route); err != nil {
+		log.Errorf("Failed to remove route destination='%s': %v", rt.Destination, err)
+		return err
+	}
+
+	return nil
+}
+
 func (rt *Route) Configure() error {
 	switch rt.Action {
 	case "add-default-gw":
 		return rt.AddDefaultGateWay()
 	case "replace-default-gw":
 		return rt.ReplaceDefaultGateWay()
+	case "replace":
+		return rt.AddRoute(true)
+	case "", "add":
+		return rt.AddRoute(false)
 	}
 
 	return nil
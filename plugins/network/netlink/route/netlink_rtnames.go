@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package route
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// iproute2 keeps the names in /etc, newer versions ship the defaults in /usr.
+// /etc comes last so the admin's names win.
+var rtNamesDirs = []string{"/usr/lib/iproute2", "/usr/share/iproute2", "/etc/iproute2"}
+
+// rtNames maps the numbers of a routing attribute to the names iproute2 uses
+type rtNames struct {
+	kind     string
+	file     string
+	defaults map[int]string
+}
+
+var (
+	rtTables = rtNames{
+		kind: "table",
+		file: "rt_tables",
+		defaults: map[int]string{
+			unix.RT_TABLE_UNSPEC:  "unspec",
+			unix.RT_TABLE_DEFAULT: "default",
+			unix.RT_TABLE_MAIN:    "main",
+			unix.RT_TABLE_LOCAL:   "local",
+		},
+	}
+	rtProtos = rtNames{
+		kind: "protocol",
+		file: "rt_protos",
+		defaults: map[int]string{
+			unix.RTPROT_UNSPEC:   "unspec",
+			unix.RTPROT_REDIRECT: "redirect",
+			unix.RTPROT_KERNEL:   "kernel",
+			unix.RTPROT_BOOT:     "boot",
+			unix.RTPROT_STATIC:   "static",
+			unix.RTPROT_RA:       "ra",
+			unix.RTPROT_DHCP:     "dhcp",
+			unix.RTPROT_BABEL:    "babel",
+			unix.RTPROT_BGP:      "bgp",
+			unix.RTPROT_ISIS:     "isis",
+			unix.RTPROT_OSPF:     "ospf",
+			unix.RTPROT_RIP:      "rip",
+			unix.RTPROT_EIGRP:    "eigrp",
+		},
+	}
+	rtScopes = rtNames{
+		kind: "scope",
+		file: "rt_scopes",
+		defaults: map[int]string{
+			unix.RT_SCOPE_UNIVERSE: "global",
+			unix.RT_SCOPE_SITE:     "site",
+			unix.RT_SCOPE_LINK:     "link",
+			unix.RT_SCOPE_HOST:     "host",
+			unix.RT_SCOPE_NOWHERE:  "nowhere",
+		},
+	}
+	// Route types are fixed by the kernel, there is no file for them
+	rtTypes = rtNames{
+		kind: "type",
+		defaults: map[int]string{
+			unix.RTN_UNSPEC:      "none",
+			unix.RTN_UNICAST:     "unicast",
+			unix.RTN_LOCAL:       "local",
+			unix.RTN_BROADCAST:   "broadcast",
+			unix.RTN_ANYCAST:     "anycast",
+			unix.RTN_MULTICAST:   "multicast",
+			unix.RTN_BLACKHOLE:   "blackhole",
+			unix.RTN_UNREACHABLE: "unreachable",
+			unix.RTN_PROHIBIT:    "prohibit",
+			unix.RTN_THROW:       "throw",
+			unix.RTN_NAT:         "nat",
+		},
+	}
+)
+
+func readRtNamesFile(file string, names map[int]string) {
+	f, err := os.Open(file)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		n, err := strconv.ParseUint(fields[0], 0, 32)
+		if err != nil {
+			continue
+		}
+
+		names[int(n)] = fields[1]
+	}
+}
+
+// load reads the file and the .conf snippets of its .d directories on top of the defaults
+func (r *rtNames) load() map[int]string {
+	names := make(map[int]string, len(r.defaults))
+	for k, v := range r.defaults {
+		names[k] = v
+	}
+
+	if r.file == "" {
+		return names
+	}
+
+	// Like iproute2 the file in /etc replaces the shipped one, the snippets
+	// of every directory are read
+	_, err := os.Stat(path.Join("/etc/iproute2", r.file))
+	etc := err == nil
+
+	for _, d := range rtNamesDirs {
+		if !etc || d == "/etc/iproute2" {
+			readRtNamesFile(path.Join(d, r.file), names)
+		}
+
+		confs, _ := filepath.Glob(path.Join(d, r.file+".d", "*.conf"))
+		for _, c := range confs {
+			readRtNamesFile(c, names)
+		}
+	}
+
+	return names
+}
+
+func lookupName(names map[int]string, n int) string {
+	if s, ok := names[n]; ok {
+		return s
+	}
+
+	return strconv.Itoa(n)
+}
+
+// parse accepts either a name or a number
+func (r *rtNames) parse(s string) (int, error) {
+	if n, err := strconv.ParseUint(s, 0, 32); err == nil {
+		return int(n), nil
+	}
+
+	for k, v := range r.load() {
+		if v == s {
+			return k, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown %s='%s'", r.kind, s)
+}
+
+// routeNames holds the name maps loaded once for a whole route dump
+type routeNames struct {
+	tables    map[int]string
+	protocols map[int]string
+	scopes    map[int]string
+	types     map[int]string
+}
+
+func loadRouteNames() *routeNames {
+	return &routeNames{
+		tables:    rtTables.load(),
+		protocols: rtProtos.load(),
+		scopes:    rtScopes.load(),
+		types:     rtTypes.load(),
+	}
+}
+
+func TableName(table int) string {
+	return lookupName(rtTables.load(), table)
+}
+
+func ParseTable(s string) (int, error) {
+	return rtTables.parse(s)
+}
+
+func ProtocolName(proto int) string {
+	return lookupName(rtProtos.load(), proto)
+}
+
+func ParseProtocol(s string) (int, error) {
+	return rtProtos.parse(s)
+}
+
+func ParseScope(s string) (int, error) {
+	return rtScopes.parse(s)
+}
+
+func ParseType(s string) (int, error) {
+	return rtTypes.parse(s)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
 )
 
 func routerAddRoute(w http.ResponseWriter, r *http.Request) {
@@ -18,7 +19,7 @@ func routerAddRoute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := rt.Configure(); err != nil {
+	if err := namespace.Run(r.URL.Query().Get("netns"), rt.Configure); err != nil {
 		web.JSONResponseError(err, w)
 	}
 }
@@ -30,24 +31,100 @@ func routerDeleteRoute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err = rt.RemoveGateWay(); err != nil {
+	if err = namespace.Run(r.URL.Query().Get("netns"), rt.RemoveGateWay); err != nil {
 		web.JSONResponseError(err, w)
 	}
 }
 
+func routerConfigureRoute(w http.ResponseWriter, r *http.Request) {
+	rt, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), rt.Configure); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("configured", w)
+}
+
+func routerRemoveRoute(w http.ResponseWriter, r *http.Request) {
+	rt, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), rt.RemoveRoute); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("removed", w)
+}
+
 func routerAcquireRoute(w http.ResponseWriter, r *http.Request) {
-	rts, err := AcquireRoutes()
+	q := r.URL.Query()
+
+	var rts []RouteInfo
+	err := namespace.Run(q.Get("netns"), func() (err error) {
+		rts, err = AcquireRoutesFiltered(&RouteFilter{
+			Table:    q.Get("table"),
+			Family:   q.Get("family"),
+			Link:     q.Get("link"),
+			Protocol: q.Get("protocol"),
+		})
+		return err
+	})
 	if err != nil {
 		web.JSONResponseError(err, w)
+		return
 	}
 
 	web.JSONResponse(rts, w)
 }
 
+func routerAcquireRouteGet(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var rts []RouteInfo
+	err := namespace.Run(q.Get("netns"), func() (err error) {
+		rts, err = AcquireRouteGet(q.Get("destination"))
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(rts, w)
+}
+
+func routerAcquireNextHops(w http.ResponseWriter, r *http.Request) {
+	var nhs []NextHopObject
+	err := namespace.Run(r.URL.Query().Get("netns"), func() (err error) {
+		nhs, err = AcquireNextHops()
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(nhs, w)
+}
+
 func RegisterRouterRoute(router *mux.Router) {
 	s := router.PathPrefix("/netlink").Subrouter().StrictSlash(false)
 
 	s.HandleFunc("/route/{link}", routerAddRoute).Methods("POST")
 	s.HandleFunc("/route/{link}", routerDeleteRoute).Methods("DELETE")
+	s.HandleFunc("/route", routerConfigureRoute).Methods("POST")
+	s.HandleFunc("/route", routerRemoveRoute).Methods("DELETE")
 	s.HandleFunc("/route", routerAcquireRoute).Methods("GET")
+	s.HandleFunc("/route/get", routerAcquireRouteGet).Methods("GET")
+	s.HandleFunc("/route/nexthop", routerAcquireNextHops).Methods("GET")
 }
@@ -0,0 +1,554 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/route"
+)
+
+// FIB_RULE_INVERT from linux/fib_rules.h
+const fibRuleInvert = 0x2
+
+// Rule is a routing policy rule as "ip rule" shows it. The keys follow the
+// [RoutingPolicyRule] section so that the same request can be applied at
+// runtime or persisted by systemd-networkd.
+type Rule struct {
+	Family                 string `json:"Family"`
+	Priority               string `json:"Priority"`
+	From                   string `json:"From"`
+	To                     string `json:"To"`
+	TypeOfService          string `json:"TypeOfService"`
+	FirewallMark           string `json:"FirewallMark"`
+	IncomingInterface      string `json:"IncomingInterface"`
+	OutgoingInterface      string `json:"OutgoingInterface"`
+	Table                  string `json:"Table"`
+	Goto                   string `json:"Goto"`
+	User                   string `json:"User"`
+	IPProtocol             string `json:"IPProtocol"`
+	SourcePort             string `json:"SourcePort"`
+	DestinationPort        string `json:"DestinationPort"`
+	SuppressPrefixLength   string `json:"SuppressPrefixLength"`
+	SuppressInterfaceGroup string `json:"SuppressInterfaceGroup"`
+	InvertRule             bool   `json:"InvertRule"`
+	Type                   string `json:"Type"`
+	Protocol               string `json:"Protocol"`
+}
+
+var ruleActions = map[string]uint8{
+	"table":       unix.FR_ACT_TO_TBL,
+	"goto":        unix.FR_ACT_GOTO,
+	"nop":         unix.FR_ACT_NOP,
+	"blackhole":   unix.FR_ACT_BLACKHOLE,
+	"unreachable": unix.FR_ACT_UNREACHABLE,
+	"prohibit":    unix.FR_ACT_PROHIBIT,
+}
+
+var ipProtocols = map[string]uint8{
+	"icmp":   unix.IPPROTO_ICMP,
+	"tcp":    unix.IPPROTO_TCP,
+	"udp":    unix.IPPROTO_UDP,
+	"sctp":   unix.IPPROTO_SCTP,
+	"icmpv6": unix.IPPROTO_ICMPV6,
+}
+
+func decodeJSONRequest(r *http.Request) (*Rule, error) {
+	rule := Rule{}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func parseUint32(key string, value string) (uint32, error) {
+	v, err := strconv.ParseUint(value, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s='%s'", key, value)
+	}
+
+	return uint32(v), nil
+}
+
+// parseRange parses "start[-end]" as used by the uid and port ranges
+func parseRange(key string, value string, bits int) (uint64, uint64, error) {
+	start, end, ok := strings.Cut(value, "-")
+	if !ok {
+		end = start
+	}
+
+	s, err := strconv.ParseUint(start, 10, bits)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid %s='%s'", key, value)
+	}
+	e, err := strconv.ParseUint(end, 10, bits)
+	if err != nil || e < s {
+		return 0, 0, fmt.Errorf("invalid %s='%s'", key, value)
+	}
+
+	return s, e, nil
+}
+
+func parsePrefix(key string, value string) (*net.IPNet, error) {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid %s='%s'", key, value)
+		}
+		if ip.To4() != nil {
+			return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	_, ipnet, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s='%s'", key, value)
+	}
+
+	return ipnet, nil
+}
+
+func prefixFamily(ipnet *net.IPNet) uint8 {
+	if ipnet.IP.To4() != nil {
+		return unix.AF_INET
+	}
+
+	return unix.AF_INET6
+}
+
+// families picks the address families the rule goes to. Without a family or
+// a prefix telling otherwise the rule is IPv4 only like "ip rule" does.
+func (r *Rule) families() ([]uint8, error) {
+	switch r.Family {
+	case "", "ipv4":
+		if r.Family == "" {
+			for _, p := range []string{r.From, r.To} {
+				if p == "" {
+					continue
+				}
+				ipnet, err := parsePrefix("prefix", p)
+				if err != nil {
+					return nil, err
+				}
+				return []uint8{prefixFamily(ipnet)}, nil
+			}
+		}
+		return []uint8{unix.AF_INET}, nil
+	case "ipv6":
+		return []uint8{unix.AF_INET6}, nil
+	case "both", "any":
+		if r.From != "" || r.To != "" {
+			return nil, fmt.Errorf("family both can not be used with from or to")
+		}
+		return []uint8{unix.AF_INET, unix.AF_INET6}, nil
+	}
+
+	return nil, fmt.Errorf("invalid family='%s'", r.Family)
+}
+
+func (r *Rule) buildRequest(cmd int, flags int, family uint8) (*nl.NetlinkRequest, error) {
+	req := nl.NewNetlinkRequest(cmd, flags|unix.NLM_F_ACK)
+
+	msg := nl.RtMsg{}
+	msg.Family = family
+	if r.InvertRule {
+		msg.Flags |= fibRuleInvert
+	}
+
+	var attrs []*nl.RtAttr
+	native := nl.NativeEndian()
+
+	if r.Priority != "" {
+		prio, err := parseUint32("priority", r.Priority)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_PRIORITY, nl.Uint32Attr(prio)))
+	}
+
+	for _, p := range []struct {
+		key  string
+		attr int
+		len  *uint8
+		v    string
+	}{
+		{"from", unix.FRA_SRC, &msg.Src_len, r.From},
+		{"to", unix.FRA_DST, &msg.Dst_len, r.To},
+	} {
+		if p.v == "" {
+			continue
+		}
+
+		ipnet, err := parsePrefix(p.key, p.v)
+		if err != nil {
+			return nil, err
+		}
+		if prefixFamily(ipnet) != family {
+			return nil, fmt.Errorf("%s='%s' does not match the rule family", p.key, p.v)
+		}
+
+		ip := ipnet.IP.To4()
+		if family == unix.AF_INET6 {
+			ip = ipnet.IP.To16()
+		}
+		l, _ := ipnet.Mask.Size()
+		*p.len = uint8(l)
+		attrs = append(attrs, nl.NewRtAttr(p.attr, ip))
+	}
+
+	if r.TypeOfService != "" {
+		tos, err := strconv.ParseUint(r.TypeOfService, 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tos='%s'", r.TypeOfService)
+		}
+		msg.Tos = uint8(tos)
+	}
+
+	if r.FirewallMark != "" {
+		mark, mask, ok := strings.Cut(r.FirewallMark, "/")
+		m, err := parseUint32("fwmark", mark)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_FWMARK, nl.Uint32Attr(m)))
+
+		if ok {
+			m, err := parseUint32("fwmask", mask)
+			if err != nil {
+				return nil, err
+			}
+			attrs = append(attrs, nl.NewRtAttr(unix.FRA_FWMASK, nl.Uint32Attr(m)))
+		}
+	}
+
+	if r.IncomingInterface != "" {
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_IIFNAME, nl.ZeroTerminated(r.IncomingInterface)))
+	}
+	if r.OutgoingInterface != "" {
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_OIFNAME, nl.ZeroTerminated(r.OutgoingInterface)))
+	}
+
+	if r.Table != "" {
+		table, err := route.ParseTable(r.Table)
+		if err != nil {
+			return nil, err
+		}
+
+		msg.Table = unix.RT_TABLE_UNSPEC
+		if table < 256 {
+			msg.Table = uint8(table)
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_TABLE, nl.Uint32Attr(uint32(table))))
+		msg.Type = unix.FR_ACT_TO_TBL
+	}
+
+	if r.Goto != "" {
+		target, err := parseUint32("goto", r.Goto)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_GOTO, nl.Uint32Attr(target)))
+		msg.Type = unix.FR_ACT_GOTO
+	}
+
+	if r.Type != "" {
+		action, ok := ruleActions[r.Type]
+		if !ok {
+			return nil, fmt.Errorf("invalid type='%s'", r.Type)
+		}
+		msg.Type = action
+	}
+
+	if r.User != "" {
+		start, end, err := parseRange("user", r.User, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		// struct fib_rule_uid_range
+		b := make([]byte, 8)
+		native.PutUint32(b[0:4], uint32(start))
+		native.PutUint32(b[4:8], uint32(end))
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_UID_RANGE, b))
+	}
+
+	if r.IPProtocol != "" {
+		proto, ok := ipProtocols[r.IPProtocol]
+		if !ok {
+			p, err := strconv.ParseUint(r.IPProtocol, 10, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ipproto='%s'", r.IPProtocol)
+			}
+			proto = uint8(p)
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_IP_PROTO, nl.Uint8Attr(proto)))
+	}
+
+	if r.Protocol != "" {
+		proto, err := route.ParseProtocol(r.Protocol)
+		if err != nil {
+			return nil, err
+		}
+		if proto > 255 {
+			return nil, fmt.Errorf("invalid protocol='%s'", r.Protocol)
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_PROTOCOL, nl.Uint8Attr(uint8(proto))))
+	}
+
+	for _, p := range []struct {
+		key  string
+		attr int
+		v    string
+	}{
+		{"sport", unix.FRA_SPORT_RANGE, r.SourcePort},
+		{"dport", unix.FRA_DPORT_RANGE, r.DestinationPort},
+	} {
+		if p.v == "" {
+			continue
+		}
+
+		start, end, err := parseRange(p.key, p.v, 16)
+		if err != nil {
+			return nil, err
+		}
+
+		// struct fib_rule_port_range
+		b := make([]byte, 4)
+		native.PutUint16(b[0:2], uint16(start))
+		native.PutUint16(b[2:4], uint16(end))
+		attrs = append(attrs, nl.NewRtAttr(p.attr, b))
+	}
+
+	if r.SuppressPrefixLength != "" {
+		l, err := parseUint32("suppress_prefixlength", r.SuppressPrefixLength)
+		if err != nil || l > 128 {
+			return nil, fmt.Errorf("invalid suppress_prefixlength='%s'", r.SuppressPrefixLength)
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_SUPPRESS_PREFIXLEN, nl.Uint32Attr(l)))
+	}
+
+	if r.SuppressInterfaceGroup != "" {
+		g, err := parseUint32("suppress_ifgroup", r.SuppressInterfaceGroup)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, nl.NewRtAttr(unix.FRA_SUPPRESS_IFGROUP, nl.Uint32Attr(g)))
+	}
+
+	// A rule with neither table nor action looks up the main table
+	if cmd == unix.RTM_NEWRULE && msg.Type == unix.FR_ACT_UNSPEC {
+		msg.Table = unix.RT_TABLE_MAIN
+		msg.Type = unix.FR_ACT_TO_TBL
+	}
+
+	req.AddData(&msg)
+	for _, a := range attrs {
+		req.AddData(a)
+	}
+
+	return req, nil
+}
+
+func (r *Rule) execute(cmd int, flags int) error {
+	families, err := r.families()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range families {
+		req, err := r.buildRequest(cmd, flags, f)
+		if err != nil {
+			return err
+		}
+
+		if _, err := req.Execute(unix.NETLINK_ROUTE, 0); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Rule) Add() error {
+	if err := r.execute(unix.RTM_NEWRULE, unix.NLM_F_CREATE|unix.NLM_F_EXCL); err != nil {
+		log.Errorf("Failed to add routing policy rule: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *Rule) Remove() error {
+	if err := r.execute(unix.RTM_DELRULE, 0); err != nil {
+		log.Errorf("Failed to remove routing policy rule: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func actionName(action uint8) string {
+	for k, v := range ruleActions {
+		if v == action {
+			return k
+		}
+	}
+
+	return strconv.Itoa(int(action))
+}
+
+func ipProtocolName(proto uint8) string {
+	for k, v := range ipProtocols {
+		if v == proto {
+			return k
+		}
+	}
+
+	return strconv.Itoa(int(proto))
+}
+
+func formatRange(start uint64, end uint64) string {
+	if start == end {
+		return strconv.FormatUint(start, 10)
+	}
+
+	return fmt.Sprintf("%d-%d", start, end)
+}
+
+func parseRule(b []byte) (*Rule, error) {
+	msg := nl.DeserializeRtMsg(b)
+
+	attrs, err := nl.ParseRouteAttr(b[msg.Len():])
+	if err != nil {
+		return nil, err
+	}
+
+	// The kernel leaves out a zero priority
+	r := Rule{
+		Priority:   "0",
+		Family:     "ipv4",
+		InvertRule: msg.Flags&fibRuleInvert != 0,
+		Type:       actionName(msg.Type),
+		Table:      route.TableName(int(msg.Table)),
+	}
+	if msg.Family == unix.AF_INET6 {
+		r.Family = "ipv6"
+	}
+	if msg.Tos != 0 {
+		r.TypeOfService = strconv.Itoa(int(msg.Tos))
+	}
+
+	native := nl.NativeEndian()
+	mark, mask := "", ""
+	for _, a := range attrs {
+		switch a.Attr.Type {
+		case unix.FRA_PRIORITY:
+			r.Priority = strconv.FormatUint(uint64(native.Uint32(a.Value)), 10)
+		case unix.FRA_SRC:
+			r.From = fmt.Sprintf("%s/%d", net.IP(a.Value), msg.Src_len)
+		case unix.FRA_DST:
+			r.To = fmt.Sprintf("%s/%d", net.IP(a.Value), msg.Dst_len)
+		case unix.FRA_FWMARK:
+			mark = fmt.Sprintf("0x%x", native.Uint32(a.Value))
+		case unix.FRA_FWMASK:
+			mask = fmt.Sprintf("0x%x", native.Uint32(a.Value))
+		case unix.FRA_IIFNAME:
+			r.IncomingInterface = strings.TrimRight(string(a.Value), "\x00")
+		case unix.FRA_OIFNAME:
+			r.OutgoingInterface = strings.TrimRight(string(a.Value), "\x00")
+		case unix.FRA_TABLE:
+			r.Table = route.TableName(int(native.Uint32(a.Value)))
+		case unix.FRA_GOTO:
+			r.Goto = strconv.FormatUint(uint64(native.Uint32(a.Value)), 10)
+		case unix.FRA_UID_RANGE:
+			if len(a.Value) >= 8 {
+				r.User = formatRange(uint64(native.Uint32(a.Value[0:4])), uint64(native.Uint32(a.Value[4:8])))
+			}
+		case unix.FRA_IP_PROTO:
+			r.IPProtocol = ipProtocolName(a.Value[0])
+		case unix.FRA_SPORT_RANGE, unix.FRA_DPORT_RANGE:
+			if len(a.Value) < 4 {
+				continue
+			}
+			ports := formatRange(uint64(native.Uint16(a.Value[0:2])), uint64(native.Uint16(a.Value[2:4])))
+			if a.Attr.Type == unix.FRA_SPORT_RANGE {
+				r.SourcePort = ports
+			} else {
+				r.DestinationPort = ports
+			}
+		case unix.FRA_SUPPRESS_PREFIXLEN:
+			// Unset is reported as -1
+			if l := native.Uint32(a.Value); l != 0xffffffff {
+				r.SuppressPrefixLength = strconv.FormatUint(uint64(l), 10)
+			}
+		case unix.FRA_SUPPRESS_IFGROUP:
+			if g := native.Uint32(a.Value); g != 0xffffffff {
+				r.SuppressInterfaceGroup = strconv.FormatUint(uint64(g), 10)
+			}
+		case unix.FRA_PROTOCOL:
+			r.Protocol = route.ProtocolName(int(a.Value[0]))
+		}
+	}
+
+	if mark != "" {
+		r.FirewallMark = mark
+		if mask != "" && mask != "0xffffffff" {
+			r.FirewallMark += "/" + mask
+		}
+	}
+
+	// The table of goto, nop and the other actions is meaningless
+	if msg.Type != unix.FR_ACT_TO_TBL {
+		r.Table = ""
+	}
+
+	return &r, nil
+}
+
+// AcquireRules dumps the rules of the family, "ipv4", "ipv6" or both when empty
+func AcquireRules(family string) ([]Rule, error) {
+	f := unix.AF_UNSPEC
+	switch family {
+	case "", "all":
+	case "ipv4":
+		f = unix.AF_INET
+	case "ipv6":
+		f = unix.AF_INET6
+	default:
+		return nil, fmt.Errorf("invalid family='%s'", family)
+	}
+
+	req := nl.NewNetlinkRequest(unix.RTM_GETRULE, unix.NLM_F_DUMP)
+	req.AddData(nl.NewIfInfomsg(f))
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWRULE)
+	if err != nil {
+		log.Errorf("Failed to acquire routing policy rules: %v", err)
+		return nil, err
+	}
+
+	rules := []Rule{}
+	for _, m := range msgs {
+		r, err := parseRule(m)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, *r)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package rule
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
+)
+
+func routerAcquireRules(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var rules []Rule
+	err := namespace.Run(q.Get("netns"), func() (err error) {
+		rules, err = AcquireRules(q.Get("family"))
+		return err
+	})
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(rules, w)
+}
+
+func routerAddRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), rule.Add); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("added", w)
+}
+
+func routerRemoveRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := decodeJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := namespace.Run(r.URL.Query().Get("netns"), rule.Remove); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("removed", w)
+}
+
+func RegisterRouterRule(router *mux.Router) {
+	router.HandleFunc("/rule", routerAcquireRules).Methods("GET")
+	router.HandleFunc("/rule", routerAddRule).Methods("POST")
+	router.HandleFunc("/rule", routerRemoveRule).Methods("DELETE")
+}
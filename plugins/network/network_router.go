@@ -11,11 +11,17 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/vmware/pmd-next-gen/pkg/web"
+	"github.com/vmware/pmd-next-gen/plugins/network/diag"
 	"github.com/vmware/pmd-next-gen/plugins/network/ethtool"
 	"github.com/vmware/pmd-next-gen/plugins/network/firewall"
 	"github.com/vmware/pmd-next-gen/plugins/network/netlink/address"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/bridge"
 	"github.com/vmware/pmd-next-gen/plugins/network/netlink/link"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/namespace"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/neighbor"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/qdisc"
 	"github.com/vmware/pmd-next-gen/plugins/network/netlink/route"
+	"github.com/vmware/pmd-next-gen/plugins/network/netlink/rule"
 	"github.com/vmware/pmd-next-gen/plugins/network/networkd"
 	"github.com/vmware/pmd-next-gen/plugins/network/resolved"
 	"github.com/vmware/pmd-next-gen/plugins/network/timesyncd"
@@ -94,6 +100,11 @@ func RegisterRouterNetwork(router *mux.Router) {
 	link.RegisterRouterLink(n)
 	address.RegisterRouterAddress(n)
 	route.RegisterRouterRoute(n)
+	bridge.RegisterRouterBridge(n)
+	qdisc.RegisterRouterQDisc(n)
+	rule.RegisterRouterRule(n)
+	neighbor.RegisterRouterNeighbor(n)
+	namespace.RegisterRouterNamespace(n)
 
 	// ethtool
 	ethtool.RegisterRouterEthTool(n)
@@ -106,6 +117,8 @@ func RegisterRouterNetwork(router *mux.Router) {
 	timesyncd.RegisterRouterTimeSyncd(n)
 	// firewall
 	firewall.RegisterRouterNft(n)
+	// diagnostics
+	diag.RegisterRouterDiag(n)
 
 	n.HandleFunc("/describe", routerDescribeNetwork).Methods("GET")
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/godbus/dbus/v5"
 
@@ -72,3 +73,47 @@ func (c *SDConnection) DBusLinkDescribe(ctx context.Context) (*LinksDescribe, er
 
 	return &m, nil
 }
+
+func (c *SDConnection) DBusNetworkRenewLink(ctx context.Context, index int) error {
+	if err := c.object.CallWithContext(ctx, dbusManagerinterface+"."+"RenewLink", 0, index).Err; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *SDConnection) DBusNetworkForceRenewLink(ctx context.Context, index int) error {
+	if err := c.object.CallWithContext(ctx, dbusManagerinterface+"."+"ForceRenewLink", 0, index).Err; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *SDConnection) DBusNetworkDescribeLink(ctx context.Context, index int) (*LinkDBusDescribe, error) {
+	var props string
+
+	err := c.object.CallWithContext(ctx, dbusManagerinterface+"."+"DescribeLink", 0, index).Store(&props)
+	if err != nil {
+		return nil, err
+	}
+
+	m := LinkDBusDescribe{}
+	if err := json.Unmarshal([]byte(props), &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// Leases handed out by the DHCP server running on the link
+func (c *SDConnection) DBusNetworkDHCPServerLeases(ctx context.Context, index int) ([]dbusDHCPServerLease, error) {
+	o := c.conn.Object(dbusInterface, dbus.ObjectPath(dbusPath+"/link/_3"+strconv.Itoa(index)))
+
+	var leases []dbusDHCPServerLease
+	if err := o.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, dbusInterface+".DHCPServer", "Leases").Store(&leases); err != nil {
+		return nil, err
+	}
+
+	return leases, nil
+}
@@ -5,8 +5,12 @@ package networkd
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -15,6 +19,7 @@ import (
 
 	"github.com/vmware/pmd-next-gen/pkg/configfile"
 	"github.com/vmware/pmd-next-gen/pkg/system"
+	"github.com/vmware/pmd-next-gen/pkg/validator"
 )
 
 func ParseLinkString(ifindex int, key string) (string, error) {
@@ -47,6 +52,26 @@ func ParseLinkNetworkFile(ifindex int) (string, error) {
 	return ParseLinkString(ifindex, "NETWORK_FILE")
 }
 
+// ParseLinkUdevProperty reads a property of the link from the udev database
+func ParseLinkUdevProperty(ifindex int, key string) (string, error) {
+	lines, err := system.ReadFullFile(path.Join("/run/udev/data", "n"+strconv.Itoa(ifindex)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range lines {
+		if v, found := strings.CutPrefix(line, "E:"+key+"="); found {
+			return v, nil
+		}
+	}
+
+	return "", fmt.Errorf("property '%s' not found", key)
+}
+
+func ParseLinkUdevLinkFile(ifindex int) (string, error) {
+	return ParseLinkUdevProperty(ifindex, "ID_NET_LINK_FILE")
+}
+
 func ParseLinkOperationalState(ifindex int) (string, error) {
 	return ParseLinkString(ifindex, "OPER_STATE")
 }
@@ -168,6 +193,83 @@ func CreateMatchSection(m *configfile.Meta, link string) error {
 	return nil
 }
 
+func (mt *MatchSection) isEmpty() bool {
+	return mt.Name == "" && mt.MACAddress == "" && mt.PermanentMACAddress == "" && mt.Path == "" &&
+		mt.Driver == "" && mt.Type == "" && mt.Kind == "" && len(mt.Property) == 0
+}
+
+func (mt *MatchSection) validate() error {
+	if !validator.IsEmpty(mt.MACAddress) && !validator.IsMatchMACAddress(mt.MACAddress) {
+		log.Errorf("Failed to parse MACAddress='%s'", mt.MACAddress)
+		return fmt.Errorf("invalid MACAddress='%s'", mt.MACAddress)
+	}
+
+	if !validator.IsEmpty(mt.PermanentMACAddress) && !validator.IsMatchMACAddress(mt.PermanentMACAddress) {
+		log.Errorf("Failed to parse PermanentMACAddress='%s'", mt.PermanentMACAddress)
+		return fmt.Errorf("invalid PermanentMACAddress='%s'", mt.PermanentMACAddress)
+	}
+
+	for _, kv := range []struct{ key, value string }{
+		{"Name", mt.Name},
+		{"Path", mt.Path},
+		{"Driver", mt.Driver},
+		{"Type", mt.Type},
+		{"Kind", mt.Kind},
+	} {
+		if !validator.IsEmpty(kv.value) && !validator.IsMatchPattern(kv.value) {
+			log.Errorf("Failed to parse %s='%s'", kv.key, kv.value)
+			return fmt.Errorf("invalid %s='%s'", kv.key, kv.value)
+		}
+	}
+
+	for _, p := range mt.Property {
+		if !validator.IsMatchProperty(p) {
+			log.Errorf("Failed to parse Property='%s'", p)
+			return fmt.Errorf("invalid Property='%s'", p)
+		}
+	}
+
+	return nil
+}
+
+// BuildMatchSection replaces the keys of the [Match] section with the ones set in mt.
+func (mt *MatchSection) BuildMatchSection(m *configfile.Meta) error {
+	if err := mt.validate(); err != nil {
+		return err
+	}
+
+	s, err := m.Cfg.GetSection("Match")
+	if err != nil {
+		if s, err = m.Cfg.NewSection("Match"); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range s.KeyStrings() {
+		s.DeleteKey(k)
+	}
+
+	for _, kv := range []struct{ key, value string }{
+		{"Name", mt.Name},
+		{"MACAddress", mt.MACAddress},
+		{"PermanentMACAddress", mt.PermanentMACAddress},
+		{"Path", mt.Path},
+		{"Driver", mt.Driver},
+		{"Type", mt.Type},
+		{"Kind", mt.Kind},
+	} {
+		if !validator.IsEmpty(kv.value) {
+			s.Key(kv.key).SetValue(kv.value)
+		}
+	}
+
+	for _, p := range mt.Property {
+		s.NewKey("Property", p)
+	}
+
+	return nil
+}
+
 func CreateNetworkFile(link string) (*configfile.Meta, error) {
 	file := "10-" + link + ".network"
 
@@ -220,6 +322,130 @@ func CreateOrParseNetworkFile(l string) (*configfile.Meta, error) {
 	return configfile.Load(n)
 }
 
+// Drop-ins of a .network file are looked up in the same directories as the
+// .network files, the ones in /etc override those with the same name in /run and /lib.
+var networkDropinDirs = []string{"/etc/systemd/network", "/run/systemd/network", "/usr/lib/systemd/network", "/lib/systemd/network"}
+
+const networkDropinFile = "50-photon-mgmt.conf"
+
+func buildNetworkDropinFilePath(networkFile string) string {
+	return path.Join("/etc/systemd/network", path.Base(networkFile)+".d", networkDropinFile)
+}
+
+func AcquireLinkNetworkFile(l string) (string, error) {
+	link, err := netlink.LinkByName(l)
+	if err != nil {
+		return "", err
+	}
+
+	return ParseLinkNetworkFile(link.Attrs().Index)
+}
+
+func AcquireNetworkDropinFiles(networkFile string) []string {
+	dropins := make(map[string]string)
+	for i := len(networkDropinDirs) - 1; i >= 0; i-- {
+		matches, _ := filepath.Glob(path.Join(networkDropinDirs[i], path.Base(networkFile)+".d", "*.conf"))
+		for _, f := range matches {
+			dropins[path.Base(f)] = f
+		}
+	}
+
+	names := make([]string, 0, len(dropins))
+	for n := range dropins {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	files := make([]string, 0, len(names))
+	for _, n := range names {
+		files = append(files, dropins[n])
+	}
+
+	return files
+}
+
+// ParseNetworkFileWithDropins loads the .network file applied to the link merged
+// with all its drop-ins in the order systemd-networkd reads them.
+func ParseNetworkFileWithDropins(l string) (*configfile.Meta, error) {
+	n, err := AcquireLinkNetworkFile(l)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := configfile.Load(n)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Append(AcquireNetworkDropinFiles(n)...); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// CreateOrParseNetworkDropinFile returns the managed drop-in of the .network file
+// applied to the link, leaving the file itself untouched. When the link has no
+// .network file yet a minimal one matching the link is created first.
+func CreateOrParseNetworkDropinFile(l string) (*configfile.Meta, error) {
+	link, err := netlink.LinkByName(l)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := ParseLinkNetworkFile(link.Attrs().Index)
+	if err != nil {
+		m, err := CreateNetworkFile(link.Attrs().Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := m.Save(); err != nil {
+			return nil, err
+		}
+
+		system.ChangePermission("systemd-network", m.Path)
+		n = m.Path
+	}
+
+	p := buildNetworkDropinFilePath(n)
+	if !system.PathExists(p) {
+		if err := system.CreateDirectoryNested(path.Dir(p), 0755); err != nil {
+			return nil, err
+		}
+
+		f, err := os.Create(p)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		system.ChangePermission("systemd-network", p)
+	}
+
+	return configfile.Load(p)
+}
+
+func RemoveNetworkDropinFile(l string) error {
+	n, err := AcquireLinkNetworkFile(l)
+	if err != nil {
+		return err
+	}
+
+	p := buildNetworkDropinFilePath(n)
+	if !system.PathExists(p) {
+		return errors.New("drop-in does not exist")
+	}
+
+	if err := os.Remove(p); err != nil {
+		return err
+	}
+
+	// Leave no empty drop-in directory behind
+	os.Remove(path.Dir(p))
+	return nil
+}
+
 func buildNetDevFilePath(link string, kind string) string {
 	return path.Join("/etc/systemd/network", "10-"+link+"-"+kind+".netdev")
 }
@@ -243,7 +469,172 @@ func CreateOrParseNetDevFile(link string, kind string) (*configfile.Meta, string
 	return m, buildNetDevFilePath(link, kind), nil
 }
 
+func ParseNetDevFile(link string, kind string) (*configfile.Meta, error) {
+	if !system.PathExists(buildNetDevFilePath(link, kind)) {
+		return nil, errors.New("netdev does not exist")
+	}
+
+	return configfile.Load(buildNetDevFilePath(link, kind))
+}
+
+// acquireNetworkdFiles returns the files matching the pattern in the
+// directories networkd reads, sorted by name. A file in /etc masks those with
+// the same name in /run and /lib.
+func acquireNetworkdFiles(pattern string) ([]string, error) {
+	files := make(map[string]string)
+	for i := len(networkDropinDirs) - 1; i >= 0; i-- {
+		matches, err := filepath.Glob(path.Join(networkDropinDirs[i], pattern))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range matches {
+			files[path.Base(f)] = f
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	paths := make([]string, 0, len(names))
+	for _, n := range names {
+		paths = append(paths, files[n])
+	}
+
+	return paths, nil
+}
+
+// FindNetDevFile looks up the .netdev file declaring the netdev regardless of its kind
+func FindNetDevFile(name string) (*configfile.Meta, error) {
+	matches, err := acquireNetworkdFiles("*.netdev")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range matches {
+		m, err := configfile.Load(f)
+		if err != nil {
+			continue
+		}
+
+		if m.GetKeySectionString("NetDev", "Name") == name {
+			return m, nil
+		}
+	}
+
+	return nil, errors.New("netdev does not exist")
+}
+
+func stacksNetDev(m *configfile.Meta, key string, name string) bool {
+	for _, s := range m.Sections("Network") {
+		if !s.HasKey(key) {
+			continue
+		}
+
+		for _, v := range s.Key(key).ValueWithShadows() {
+			if v == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func findNetDevLinkFiles(name string, kind string) ([]*configfile.Meta, error) {
+	key := netDevKindToNetworkKind(kind)
+	if key == "" {
+		return nil, nil
+	}
+
+	matches, err := acquireNetworkdFiles("*.network")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*configfile.Meta
+	for _, f := range matches {
+		m, err := configfile.Load(f)
+		if err != nil {
+			continue
+		}
+
+		if stacksNetDev(m, key, name) {
+			files = append(files, m)
+		}
+	}
+
+	return files, nil
+}
+
+// findNetDevLinkFile picks the file of the link among the files stacking the
+// netdev. The file networkd applied to the link is preferred, whatever it
+// matches on, files not applied yet are matched by Name=.
+func findNetDevLinkFile(files []*configfile.Meta, link string) *configfile.Meta {
+	if n, err := AcquireLinkNetworkFile(link); err == nil {
+		for _, m := range files {
+			if m.Path == n {
+				return m
+			}
+		}
+	}
+
+	for _, m := range files {
+		if m.GetKeySectionString("Match", "Name") == link {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// FindNetDevLinks returns the links whose .network file stacks the netdev on them
+func FindNetDevLinks(name string, kind string) ([]string, error) {
+	files, err := findNetDevLinkFiles(name, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	links := []string{}
+	applied := make(map[string]bool)
+	if linkList, err := netlink.LinkList(); err == nil {
+		for _, l := range linkList {
+			n, err := ParseLinkNetworkFile(l.Attrs().Index)
+			if err != nil {
+				continue
+			}
+
+			for _, m := range files {
+				if m.Path == n {
+					links = append(links, l.Attrs().Name)
+					applied[n] = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, m := range files {
+		if applied[m.Path] {
+			continue
+		}
+
+		if l := m.GetKeySectionString("Match", "Name"); !validator.IsEmpty(l) && !slices.Contains(links, l) {
+			links = append(links, l)
+		}
+	}
+
+	return links, nil
+}
+
 func RemoveNetDev(link string, kind string) error {
+	if m, err := FindNetDevFile(link); err == nil && m.GetKeySectionString("NetDev", "Kind") == "wireguard" {
+		removeWireGuardKeyFiles(m, link)
+	}
+
 	// remove .netdev file
 	configfile.RemoveFilesGlob("/etc/systemd/network", "*.netdev", "NetDev", "Name", link)
 	configfile.RemoveFilesGlob("/lib/systemd/network", "*.netdev", "NetDev", "Name", link)
@@ -305,6 +696,55 @@ func RemoveNetDevNetworkFile(link string, kind string) error {
 	return os.Remove(buildNetDevNetworkFilePath(link, kind))
 }
 
+func buildLinkFilePath(link string) string {
+	return path.Join("/etc/systemd/network", "10-"+link+".link")
+}
+
+func AcquireLinkFiles() ([]string, error) {
+	seen := make(map[string]bool)
+	files := []string{}
+
+	// Files with the same name in earlier directories mask the later ones
+	for _, d := range networkDropinDirs {
+		matches, err := filepath.Glob(path.Join(d, "*.link"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range matches {
+			if seen[path.Base(f)] {
+				continue
+			}
+
+			seen[path.Base(f)] = true
+			files = append(files, f)
+		}
+	}
+
+	// udev applies the files in lexical order
+	sort.SliceStable(files, func(i, j int) bool {
+		return path.Base(files[i]) < path.Base(files[j])
+	})
+
+	return files, nil
+}
+
+// applyLinkFiles has udev re-read the .link files and apply them to the link,
+// networkd does not handle them
+func applyLinkFiles(link string) error {
+	if s, err := system.ExecAndCapture("udevadm", "control", "--reload"); err != nil {
+		log.Errorf("Failed to reload udev rules: %s %v", s, err)
+		return err
+	}
+
+	if s, err := system.ExecAndCapture("udevadm", "trigger", "--action=add", path.Join("/sys/class/net", link)); err != nil {
+		log.Errorf("Failed to trigger udev for link='%s': %s %v", link, s, err)
+		return err
+	}
+
+	return nil
+}
+
 func CreateOrParseLinkFile(link string) (*configfile.Meta, error) {
 	file := "10-" + link + ".link"
 
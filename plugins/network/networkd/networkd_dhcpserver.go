@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package networkd
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/vmware/pmd-next-gen/pkg/web"
+)
+
+// Layout of the org.freedesktop.network1.DHCPServer Leases property, a(uayayayayt)
+type dbusDHCPServerLease struct {
+	Family         uint32
+	ClientId       []byte
+	Address        []byte
+	Gateway        []byte
+	HardwareAddr   []byte
+	ExpirationUSec uint64
+}
+
+type DHCPServerLease struct {
+	ClientId   string `json:"ClientId"`
+	Address    string `json:"Address"`
+	Gateway    string `json:"Gateway"`
+	MACAddress string `json:"MACAddress"`
+	Expiration string `json:"Expiration"`
+	Static     bool   `json:"Static"`
+}
+
+type DHCPServerLeases struct {
+	Link   string            `json:"Link"`
+	Leases []DHCPServerLease `json:"Leases"`
+}
+
+// bootTimeToTime converts a CLOCK_BOOTTIME timestamp, the clock lease
+// expirations are kept on, to wall clock time
+func bootTimeToTime(usec uint64) (time.Time, error) {
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_BOOTTIME, &ts); err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Now().Add(time.Duration(usec)*time.Microsecond - time.Duration(ts.Nano())), nil
+}
+
+func AcquireDHCPServerLeases(ctx context.Context, link string, w http.ResponseWriter) error {
+	lnk, err := netlink.LinkByName(link)
+	if err != nil {
+		return err
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	leases, err := c.DBusNetworkDHCPServerLeases(ctx, lnk.Attrs().Index)
+	if err != nil {
+		log.Errorf("Failed to acquire DHCP server leases of link='%s': %v", link, err)
+		return err
+	}
+
+	l := DHCPServerLeases{
+		Link:   link,
+		Leases: []DHCPServerLease{},
+	}
+
+	// Reservations from [DHCPServerStaticLease] are flagged as such
+	static := make(map[string]bool)
+	if m, err := ParseNetworkFileWithDropins(link); err == nil {
+		n, err := parseNetworkSections(m)
+		if err != nil {
+			log.Errorf("Failed to parse network file for link='%s': %v", link, err)
+			return err
+		}
+
+		for _, s := range n.DHCPServerStaticLeases {
+			static[strings.ToLower(s.MACAddress)] = true
+		}
+	}
+
+	for _, d := range leases {
+		s := DHCPServerLease{
+			ClientId:   net.HardwareAddr(d.ClientId).String(),
+			Address:    net.IP(d.Address).String(),
+			Gateway:    net.IP(d.Gateway).String(),
+			MACAddress: net.HardwareAddr(d.HardwareAddr).String(),
+		}
+
+		s.Static = static[s.MACAddress]
+		if d.ExpirationUSec > 0 && d.ExpirationUSec != ^uint64(0) {
+			if t, err := bootTimeToTime(d.ExpirationUSec); err == nil {
+				s.Expiration = t.Format(time.RFC3339)
+			}
+		}
+
+		l.Leases = append(l.Leases, s)
+	}
+
+	return web.JSONResponse(l, w)
+}
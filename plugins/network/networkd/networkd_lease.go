@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package networkd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/vmware/pmd-next-gen/pkg/configfile"
+	"github.com/vmware/pmd-next-gen/pkg/system"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+)
+
+type DHCPv6Prefix struct {
+	Prefix                string `json:"Prefix"`
+	PreferredLifetimeUSec uint64 `json:"PreferredLifetimeUSec"`
+	ValidLifetimeUSec     uint64 `json:"ValidLifetimeUSec"`
+}
+
+type DHCPLease struct {
+	Link             string         `json:"Link"`
+	Index            int            `json:"Index"`
+	Address          string         `json:"Address"`
+	Netmask          string         `json:"Netmask"`
+	Router           []string       `json:"Router"`
+	ServerAddress    string         `json:"ServerAddress"`
+	NextServer       string         `json:"NextServer"`
+	Broadcast        string         `json:"Broadcast"`
+	MTU              string         `json:"MTU"`
+	DNS              []string       `json:"DNS"`
+	NTP              []string       `json:"NTP"`
+	SIP              []string       `json:"SIP"`
+	DomainName       string         `json:"DomainName"`
+	DomainSearchList []string       `json:"DomainSearchList"`
+	Hostname         string         `json:"Hostname"`
+	Routes           []string       `json:"Routes"`
+	Timezone         string         `json:"Timezone"`
+	ClientId         string         `json:"ClientId"`
+	LifetimeSec      uint32         `json:"LifetimeSec"`
+	T1Sec            uint32         `json:"T1Sec"`
+	T2Sec            uint32         `json:"T2Sec"`
+	DHCPv6Prefixes   []DHCPv6Prefix `json:"DHCPv6Prefixes"`
+}
+
+// Subset of org.freedesktop.network1.Manager.DescribeLink()
+type LinkDBusDescribe struct {
+	DHCPv6Client struct {
+		Prefixes []struct {
+			Prefix                []int  `json:"Prefix"`
+			PrefixLength          int    `json:"PrefixLength"`
+			PreferredLifetimeUSec uint64 `json:"PreferredLifetimeUSec"`
+			ValidLifetimeUSec     uint64 `json:"ValidLifetimeUSec"`
+		} `json:"Prefixes"`
+	} `json:"DHCPv6Client"`
+}
+
+func buildLeaseFilePath(ifindex int) string {
+	return path.Join("/run/systemd/netif/leases", strconv.Itoa(ifindex))
+}
+
+func parseLeaseUint32(s string) uint32 {
+	v, _ := strconv.ParseUint(s, 10, 32)
+	return uint32(v)
+}
+
+// ParseDHCPLease reads the DHCPv4 lease networkd saves for the link
+func ParseDHCPLease(ifindex int) (*DHCPLease, error) {
+	l := DHCPLease{
+		Index: ifindex,
+	}
+
+	if !system.PathExists(buildLeaseFilePath(ifindex)) {
+		return &l, nil
+	}
+
+	m, err := configfile.Load(buildLeaseFilePath(ifindex))
+	if err != nil {
+		return nil, err
+	}
+
+	s := m.Cfg.Section("")
+	l.Address = s.Key("ADDRESS").String()
+	l.Netmask = s.Key("NETMASK").String()
+	l.Router = strings.Fields(s.Key("ROUTER").String())
+	l.ServerAddress = s.Key("SERVER_ADDRESS").String()
+	l.NextServer = s.Key("NEXT_SERVER").String()
+	l.Broadcast = s.Key("BROADCAST").String()
+	l.MTU = s.Key("MTU").String()
+	l.DNS = strings.Fields(s.Key("DNS").String())
+	l.NTP = strings.Fields(s.Key("NTP").String())
+	l.SIP = strings.Fields(s.Key("SIP").String())
+	l.DomainName = s.Key("DOMAINNAME").String()
+	l.DomainSearchList = strings.Fields(s.Key("DOMAIN_SEARCH_LIST").String())
+	l.Hostname = s.Key("HOSTNAME").String()
+	l.Routes = strings.Fields(s.Key("ROUTES").String())
+	l.Timezone = s.Key("TIMEZONE").String()
+	l.ClientId = s.Key("CLIENTID").String()
+	l.LifetimeSec = parseLeaseUint32(s.Key("LIFETIME").String())
+	l.T1Sec = parseLeaseUint32(s.Key("T1").String())
+	l.T2Sec = parseLeaseUint32(s.Key("T2").String())
+
+	return &l, nil
+}
+
+func AcquireDHCPLease(ctx context.Context, link string, w http.ResponseWriter) error {
+	lnk, err := netlink.LinkByName(link)
+	if err != nil {
+		return err
+	}
+
+	l, err := ParseDHCPLease(lnk.Attrs().Index)
+	if err != nil {
+		log.Errorf("Failed to parse DHCP lease of link='%s': %v", link, err)
+		return err
+	}
+	l.Link = link
+	l.DHCPv6Prefixes = []DHCPv6Prefix{}
+
+	// DHCPv6 leases are not saved, networkd only tells about them over the bus
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	d, err := c.DBusNetworkDescribeLink(ctx, lnk.Attrs().Index)
+	if err == nil {
+		for _, p := range d.DHCPv6Client.Prefixes {
+			ip := make(net.IP, len(p.Prefix))
+			for i, b := range p.Prefix {
+				ip[i] = byte(b)
+			}
+
+			l.DHCPv6Prefixes = append(l.DHCPv6Prefixes, DHCPv6Prefix{
+				Prefix:                fmt.Sprintf("%s/%d", ip.String(), p.PrefixLength),
+				PreferredLifetimeUSec: p.PreferredLifetimeUSec,
+				ValidLifetimeUSec:     p.ValidLifetimeUSec,
+			})
+		}
+	}
+
+	return web.JSONResponse(l, w)
+}
+
+func RenewDHCPLease(ctx context.Context, link string, force bool, w http.ResponseWriter) error {
+	lnk, err := netlink.LinkByName(link)
+	if err != nil {
+		return err
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	if force {
+		err = c.DBusNetworkForceRenewLink(ctx, lnk.Attrs().Index)
+	} else {
+		err = c.DBusNetworkRenewLink(ctx, lnk.Attrs().Index)
+	}
+	if err != nil {
+		log.Errorf("Failed to renew DHCP lease of link='%s': %v", link, err)
+		return err
+	}
+
+	return web.JSONResponse("renewed", w)
+}
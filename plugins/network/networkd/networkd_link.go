@@ -6,13 +6,17 @@ package networkd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
 
 	"github.com/vmware/pmd-next-gen/pkg/configfile"
+	"github.com/vmware/pmd-next-gen/pkg/system"
 	"github.com/vmware/pmd-next-gen/pkg/validator"
 	"github.com/vmware/pmd-next-gen/pkg/web"
 )
@@ -533,12 +537,63 @@ func (l *Link) BuildLinkSection(m *configfile.Meta) error {
 	return nil
 }
 
+// buildLinkMatchSection updates the [Match] section of a .link file. udev
+// matches the kernel name with OriginalName=, Name= is not known there. Keys
+// not set in mt, such as the generated MACAddress=, are kept.
+func (mt *MatchSection) buildLinkMatchSection(m *configfile.Meta) error {
+	if err := mt.validate(); err != nil {
+		return err
+	}
+
+	s, err := m.Cfg.GetSection("Match")
+	if err != nil {
+		if s, err = m.Cfg.NewSection("Match"); err != nil {
+			return err
+		}
+	}
+
+	for _, kv := range []struct{ key, value string }{
+		{"OriginalName", mt.Name},
+		{"MACAddress", mt.MACAddress},
+		{"PermanentMACAddress", mt.PermanentMACAddress},
+		{"Path", mt.Path},
+		{"Driver", mt.Driver},
+		{"Type", mt.Type},
+		{"Kind", mt.Kind},
+	} {
+		if !validator.IsEmpty(kv.value) {
+			s.Key(kv.key).SetValue(kv.value)
+		}
+	}
+
+	if len(mt.Property) > 0 {
+		s.DeleteKey("Property")
+		for _, p := range mt.Property {
+			s.NewKey("Property", p)
+		}
+	}
+
+	// Without a match key the file applies to every interface
+	for _, k := range s.Keys() {
+		if !validator.IsEmpty(k.String()) {
+			return nil
+		}
+	}
+
+	log.Errorf("Failed to configure .link file='%s'. Empty [Match] section", m.Path)
+	return errors.New("empty match section")
+}
+
 func (l *Link) ConfigureLink(ctx context.Context, w http.ResponseWriter) error {
 	m, err := CreateOrParseLinkFile(l.Link)
 	if err != nil {
 		return err
 	}
 
+	if err := l.MatchSection.buildLinkMatchSection(m); err != nil {
+		return err
+	}
+
 	if err := l.BuildLinkSection(m); err != nil {
 		return err
 	}
@@ -561,3 +616,139 @@ func (l *Link) ConfigureLink(ctx context.Context, w http.ResponseWriter) error {
 
 	return web.JSONResponse("configured", w)
 }
+
+type LinkFileDescribe struct {
+	Path      string   `json:"Path"`
+	AppliedTo []string `json:"AppliedTo"`
+	Config    *Link    `json:"Config"`
+}
+
+type LinkConfigDescribe struct {
+	Name     string `json:"Name"`
+	Index    int    `json:"Index"`
+	LinkFile string `json:"LinkFile"`
+	Config   *Link  `json:"Config"`
+}
+
+func parseLinkSections(m *configfile.Meta) (*Link, error) {
+	l := Link{}
+
+	var errs []error
+
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Match"), &l.MatchSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Link"), &l))
+	if validator.IsEmpty(l.MatchSection.Name) {
+		l.MatchSection.Name = m.GetKeySectionString("Match", "OriginalName")
+	}
+
+	// Never served back
+	l.WakeOnLanPassword = ""
+
+	return &l, errors.Join(errs...)
+}
+
+// acquireUdevLinkFiles maps each .link file to the interfaces udev applied it to
+func acquireUdevLinkFiles() map[string][]string {
+	applied := make(map[string][]string)
+
+	links, err := netlink.LinkList()
+	if err != nil {
+		return applied
+	}
+
+	for _, l := range links {
+		f, err := ParseLinkUdevLinkFile(l.Attrs().Index)
+		if err != nil {
+			continue
+		}
+
+		applied[f] = append(applied[f], l.Attrs().Name)
+	}
+
+	return applied
+}
+
+func AcquireLinkConfigs(ctx context.Context, w http.ResponseWriter) error {
+	files, err := AcquireLinkFiles()
+	if err != nil {
+		log.Errorf("Failed to acquire .link files: %v", err)
+		return err
+	}
+
+	applied := acquireUdevLinkFiles()
+
+	links := []LinkFileDescribe{}
+	for _, f := range files {
+		m, err := configfile.Load(f)
+		if err != nil {
+			log.Errorf("Failed to parse .link file='%s': %v", f, err)
+			continue
+		}
+
+		l, err := parseLinkSections(m)
+		if err != nil {
+			log.Errorf("Failed to parse .link file='%s': %v", f, err)
+			continue
+		}
+
+		d := LinkFileDescribe{
+			Path:      f,
+			AppliedTo: applied[f],
+			Config:    l,
+		}
+		if d.AppliedTo == nil {
+			d.AppliedTo = []string{}
+		}
+
+		links = append(links, d)
+	}
+
+	return web.JSONResponse(links, w)
+}
+
+func AcquireLinkConfig(ctx context.Context, name string, w http.ResponseWriter) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return err
+	}
+
+	d := LinkConfigDescribe{
+		Name:  name,
+		Index: link.Attrs().Index,
+	}
+
+	d.LinkFile, err = ParseLinkUdevLinkFile(link.Attrs().Index)
+	if err == nil && system.PathExists(d.LinkFile) {
+		m, err := configfile.Load(d.LinkFile)
+		if err != nil {
+			log.Errorf("Failed to parse .link file='%s': %v", d.LinkFile, err)
+			return err
+		}
+
+		d.Config, err = parseLinkSections(m)
+		if err != nil {
+			log.Errorf("Failed to parse .link file='%s': %v", d.LinkFile, err)
+			return err
+		}
+		d.Config.Link = name
+	}
+
+	return web.JSONResponse(d, w)
+}
+
+func RemoveLinkConfig(ctx context.Context, name string, w http.ResponseWriter) error {
+	if !system.PathExists(buildLinkFilePath(name)) {
+		return errors.New(".link file does not exist")
+	}
+
+	if err := os.Remove(buildLinkFilePath(name)); err != nil {
+		log.Errorf("Failed to remove .link file='%s': %v", buildLinkFilePath(name), err)
+		return err
+	}
+
+	if err := applyLinkFiles(name); err != nil {
+		return err
+	}
+
+	return web.JSONResponse("removed", w)
+}
@@ -8,12 +8,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"path"
+	"reflect"
+	"strconv"
 	"strings"
 
+	"github.com/go-ini/ini"
 	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
 
 	"github.com/vmware/pmd-next-gen/pkg/configfile"
+	"github.com/vmware/pmd-next-gen/pkg/system"
 	"github.com/vmware/pmd-next-gen/pkg/validator"
 	"github.com/vmware/pmd-next-gen/pkg/web"
 )
@@ -78,7 +85,7 @@ type Bridge struct {
 	DefaultPVID          int    `json:"DefaultPVID"`
 	MulticastQuerier     bool   `json:"MulticastQuerier"`
 	MulticastSnooping    bool   `json:"MulticastSnooping"`
-	VLANFiltering        bool   `json:"VLANFiltering"`
+	VLANFiltering        string `json:"VLANFiltering"`
 	VLANProtocol         string `json:"VLANProtocol"`
 	STP                  string `json:"STP"`
 	MulticastIGMPVersion int    `json:"MulticastIGMPVersion"`
@@ -102,6 +109,8 @@ type WireGuardPeer struct {
 	PersistentKeepalive string   `json:"PersistentKeepalive"`
 	RouteTable          string   `json:"RouteTable"`
 	RouteMetric         string   `json:"RouteMetric"`
+
+	GeneratePresharedKey bool `json:"GeneratePresharedKey"`
 }
 
 type TunOrTap struct {
@@ -113,6 +122,68 @@ type TunOrTap struct {
 	KeepCarrier string `json:"KeepCarrier"`
 }
 
+type VRF struct {
+	Table string `json:"Table"`
+}
+
+type Peer struct {
+	Name       string `json:"Name"`
+	MACAddress string `json:"MACAddress"`
+}
+
+type Geneve struct {
+	Id              string `json:"Id"`
+	Remote          string `json:"Remote"`
+	TOS             string `json:"TOS"`
+	TTL             string `json:"TTL"`
+	UDPChecksum     string `json:"UDPChecksum"`
+	DestinationPort string `json:"DestinationPort"`
+	FlowLabel       string `json:"FlowLabel"`
+	IPDoNotFragment string `json:"IPDoNotFragment"`
+}
+
+type MACsec struct {
+	Port    string `json:"Port"`
+	Encrypt string `json:"Encrypt"`
+}
+
+type MACsecReceiveChannel struct {
+	Port       string `json:"Port"`
+	MACAddress string `json:"MACAddress"`
+}
+
+type MACsecTransmitAssociation struct {
+	PacketNumber   string `json:"PacketNumber"`
+	KeyId          string `json:"KeyId"`
+	Key            string `json:"Key"`
+	KeyFile        string `json:"KeyFile"`
+	Activate       string `json:"Activate"`
+	UseForEncoding string `json:"UseForEncoding"`
+}
+
+type MACsecReceiveAssociation struct {
+	Port         string `json:"Port"`
+	MACAddress   string `json:"MACAddress"`
+	PacketNumber string `json:"PacketNumber"`
+	KeyId        string `json:"KeyId"`
+	Key          string `json:"Key"`
+	KeyFile      string `json:"KeyFile"`
+	Activate     string `json:"Activate"`
+}
+
+type Tunnel struct {
+	Local            string `json:"Local"`
+	Remote           string `json:"Remote"`
+	TOS              string `json:"TOS"`
+	TTL              string `json:"TTL"`
+	DiscoverPathMTU  string `json:"DiscoverPathMTU"`
+	Key              string `json:"Key"`
+	InputKey         string `json:"InputKey"`
+	OutputKey        string `json:"OutputKey"`
+	Independent      string `json:"Independent"`
+	AssignToLoopback string `json:"AssignToLoopback"`
+}
+
 type NetDev struct {
 	Links []string `json:"Link"` // Master device
 
@@ -125,15 +196,30 @@ type NetDev struct {
 	MTUBytes    string `json:"MTUBytes"`
 	MACAddress  string `json:"MACAddress"`
 	// [Kind]
-	VLanSection          VLan          `json:"VLanSection"`
-	MacVLanSection       MacVLan       `json:"MacVLanSection"`
-	IpVLanSection        IpVLan        `json:"IpVLanSection"`
-	VxLanSection         VxLan         `json:"VxLanSection"`
-	BondSection          Bond          `json:"BondSection"`
-	BridgeSection        Bridge        `json:"BridgeSection"`
-	WireGuardSection     WireGuard     `json:"WireGuardSection"`
-	WireGuardPeerSection WireGuardPeer `json:"WireGuardPeerSection"`
-	TunOrTapSection      TunOrTap      `json:"TunOrTapSection"`
+	VLanSection           VLan            `json:"VLanSection"`
+	MacVLanSection        MacVLan         `json:"MacVLanSection"`
+	IpVLanSection         IpVLan          `json:"IpVLanSection"`
+	VxLanSection          VxLan           `json:"VxLanSection"`
+	BondSection           Bond            `json:"BondSection"`
+	BridgeSection         Bridge          `json:"BridgeSection"`
+	WireGuardSection      WireGuard       `json:"WireGuardSection"`
+	WireGuardPeerSections []WireGuardPeer `json:"WireGuardPeerSections"`
+	TunOrTapSection       TunOrTap        `json:"TunOrTapSection"`
+
+	// Deprecated: the single peer of older clients, use WireGuardPeerSections
+	WireGuardPeerSection *WireGuardPeer `json:"WireGuardPeerSection,omitempty"`
+
+	VRFSection                       VRF                       `json:"VRFSection"`
+	PeerSection                      Peer                      `json:"PeerSection"`
+	GeneveSection                    Geneve                    `json:"GeneveSection"`
+	MACsecSection                    MACsec                    `json:"MACsecSection"`
+	MACsecReceiveChannelSection      MACsecReceiveChannel      `json:"MACsecReceiveChannelSection"`
+	MACsecTransmitAssociationSection MACsecTransmitAssociation `json:"MACsecTransmitAssociationSection"`
+	MACsecReceiveAssociationSection  MACsecReceiveAssociation  `json:"MACsecReceiveAssociationSection"`
+	TunnelSection                    Tunnel                    `json:"TunnelSection"`
+
+	// Generated secrets, written once the whole document is valid
+	keyFiles map[string]string
 }
 
 func netDevKindToNetworkKind(s string) string {
@@ -161,6 +247,12 @@ func netDevKindToNetworkKind(s string) string {
 		kind = "Tun"
 	case "tap":
 		kind = "Tap"
+	case "vrf":
+		kind = "VRF"
+	case "macsec":
+		kind = "MACsec"
+	case "ipip", "gre", "gretap", "ip6gre", "sit", "vti":
+		kind = "Tunnel"
 	}
 
 	return kind
@@ -171,10 +263,20 @@ func decodeNetDevJSONRequest(r *http.Request) (*NetDev, error) {
 	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
 		return nil, err
 	}
+	n.mergeLegacyWireGuardPeer()
 
 	return &n, nil
 }
 
+// mergeLegacyWireGuardPeer adds the peer sent as WireGuardPeerSection by
+// older clients to the peer list
+func (n *NetDev) mergeLegacyWireGuardPeer() {
+	if n.WireGuardPeerSection != nil {
+		n.WireGuardPeerSections = append(n.WireGuardPeerSections, *n.WireGuardPeerSection)
+		n.WireGuardPeerSection = nil
+	}
+}
+
 func (n *NetDev) BuildNetDevSection(m *configfile.Meta) error {
 	m.NewSection("NetDev")
 
@@ -270,6 +372,22 @@ func (n *NetDev) buildBridgeSection(m *configfile.Meta) error {
 		m.SetKeyToNewSectionString("STP", validator.BoolToString(n.BridgeSection.STP))
 	}
 
+	if !validator.IsEmpty(n.BridgeSection.VLANFiltering) {
+		if !validator.IsBool(n.BridgeSection.VLANFiltering) {
+			log.Errorf("Failed to create Bridge='%s'. Invalid VLANFiltering='%s'", n.Name, n.BridgeSection.VLANFiltering)
+			return fmt.Errorf("invalid vlanfiltering='%s'", n.BridgeSection.VLANFiltering)
+		}
+		m.SetKeyToNewSectionString("VLANFiltering", validator.BoolToString(n.BridgeSection.VLANFiltering))
+	}
+
+	if n.BridgeSection.DefaultPVID > 0 {
+		if !validator.IsVLANId(strconv.Itoa(n.BridgeSection.DefaultPVID)) {
+			log.Errorf("Failed to create Bridge='%s'. Invalid DefaultPVID='%d'", n.Name, n.BridgeSection.DefaultPVID)
+			return fmt.Errorf("invalid defaultpvid='%d'", n.BridgeSection.DefaultPVID)
+		}
+		m.SetKeyToNewSectionString("DefaultPVID", strconv.Itoa(n.BridgeSection.DefaultPVID))
+	}
+
 	return nil
 }
 
@@ -383,14 +501,26 @@ func (n *NetDev) buildVxLanSection(m *configfile.Meta) error {
 func (n *NetDev) buildWireGuardSection(m *configfile.Meta) error {
 	m.NewSection("WireGuard")
 
-	// Mandatory Argument Check
+	// Without a key one is generated and kept out of the .netdev file. A key
+	// generated earlier is reused, rotating it would lock out every peer.
 	if validator.IsEmpty(n.WireGuardSection.PrivateKey) && validator.IsEmpty(n.WireGuardSection.PrivateKeyFile) {
-		log.Errorf("Failed to create WireGuard='%s'. Missing PrivateKey and PrivateKeyFile,", n.Name)
-		return errors.New("missing wireguard privatekey and privatekeyfile")
+		n.WireGuardSection.PrivateKeyFile = buildWireGuardKeyFilePath(n.Name)
+		if _, err := readWireGuardKeyFile(n.WireGuardSection.PrivateKeyFile); err != nil {
+			private, _, err := GenerateWireGuardKey()
+			if err != nil {
+				log.Errorf("Failed to create WireGuard='%s'. Failed to generate private key: %v", n.Name, err)
+				return err
+			}
+			n.addKeyFile(n.WireGuardSection.PrivateKeyFile, private)
+		}
 	}
 
 	// PrivateKey Validate
 	if !validator.IsEmpty(n.WireGuardSection.PrivateKey) {
+		if !validator.IsWireGuardKey(n.WireGuardSection.PrivateKey) {
+			log.Errorf("Failed to create WireGuard='%s'. Invalid PrivateKey", n.Name)
+			return errors.New("invalid privatekey")
+		}
 		m.SetKeyToNewSectionString("PrivateKey", n.WireGuardSection.PrivateKey)
 	}
 	// PrivateKeyFile Validate
@@ -405,49 +535,116 @@ func (n *NetDev) buildWireGuardSection(m *configfile.Meta) error {
 		}
 		m.SetKeyToNewSectionString("ListenPort", n.WireGuardSection.ListenPort)
 	}
+	// FirewallMark Validate
+	if !validator.IsEmpty(n.WireGuardSection.FirewallMark) {
+		if !validator.IsUint32(n.WireGuardSection.FirewallMark) {
+			log.Errorf("Failed to create WireGuard='%s'. Invalid FirewallMark='%s'", n.Name, n.WireGuardSection.FirewallMark)
+			return fmt.Errorf("invalid firewallmark='%s'", n.WireGuardSection.FirewallMark)
+		}
+		m.SetKeyToNewSectionString("FirewallMark", n.WireGuardSection.FirewallMark)
+	}
+	// RouteTable Validate
+	if !validator.IsEmpty(n.WireGuardSection.RouteTable) {
+		if !validator.IsWireGuardRouteTable(n.WireGuardSection.RouteTable) {
+			log.Errorf("Failed to create WireGuard='%s'. Invalid RouteTable='%s'", n.Name, n.WireGuardSection.RouteTable)
+			return fmt.Errorf("invalid routetable='%s'", n.WireGuardSection.RouteTable)
+		}
+		m.SetKeyToNewSectionString("RouteTable", n.WireGuardSection.RouteTable)
+	}
+	// RouteMetric Validate
+	if !validator.IsEmpty(n.WireGuardSection.RouteMetric) {
+		if !validator.IsUint32(n.WireGuardSection.RouteMetric) {
+			log.Errorf("Failed to create WireGuard='%s'. Invalid RouteMetric='%s'", n.Name, n.WireGuardSection.RouteMetric)
+			return fmt.Errorf("invalid routemetric='%s'", n.WireGuardSection.RouteMetric)
+		}
+		m.SetKeyToNewSectionString("RouteMetric", n.WireGuardSection.RouteMetric)
+	}
 
 	return nil
 }
 
 func (n *NetDev) buildWireGuardPeerSection(m *configfile.Meta) error {
-	m.NewSection("WireGuardPeer")
+	for _, p := range n.WireGuardPeerSections {
+		// PublicKey Validate
+		if validator.IsEmpty(p.PublicKey) {
+			log.Errorf("Failed to create WireGuardPeer='%s'. Missing PublicKey,", n.Name)
+			return errors.New("missing wireguardpeer publickey")
+		}
+		if !validator.IsWireGuardKey(p.PublicKey) {
+			log.Errorf("Failed to create WireGuardPeer='%s'. Invalid PublicKey='%s'", n.Name, p.PublicKey)
+			return fmt.Errorf("invalid publickey='%s'", p.PublicKey)
+		}
 
-	// PublicKey Validate
-	if validator.IsEmpty(n.WireGuardPeerSection.PublicKey) {
-		log.Errorf("Failed to create WireGuardPeer='%s'. Missing PublicKey,", n.Name)
-		return errors.New("missing wireguardpeer publickey")
-	}
-	m.SetKeyToNewSectionString("PublicKey", n.WireGuardPeerSection.PublicKey)
+		// Endpoint Validate
+		if !validator.IsEmpty(p.Endpoint) && !validator.IsWireGuardPeerEndpoint(p.Endpoint) {
+			log.Errorf("Failed to create WireGuard='%s'. Invalid Endpoint='%s'", n.Name, p.Endpoint)
+			return fmt.Errorf("invalid endpoint='%s'", p.Endpoint)
+		}
 
-	// Endpoint Validate
-	if validator.IsEmpty(n.WireGuardPeerSection.Endpoint) {
-		log.Errorf("Failed to create WireGuardPeer='%s'. Missing Endpoint,", n.Name)
-		return errors.New("missing wireguardpeer endpoint")
-	}
+		// AllowedIPs Validate
+		for _, ip := range p.AllowedIPs {
+			if !validator.IsIP(ip) {
+				log.Errorf("Failed to create WireGuardPeer='%s'. Invalid AllowedIPs='%s'", n.Name, p.AllowedIPs)
+				return fmt.Errorf("invalid allowedips='%s'", p.AllowedIPs)
+			}
+		}
 
-	if !validator.IsWireGuardPeerEndpoint(n.WireGuardPeerSection.Endpoint) {
-		log.Errorf("Failed to create WireGuard='%s'. Invalid Endpoint='%s'", n.Name, n.WireGuardPeerSection.Endpoint)
-		return fmt.Errorf("invalid endpoint='%s'", n.WireGuardPeerSection.Endpoint)
-	}
-	m.SetKeyToNewSectionString("Endpoint", n.WireGuardPeerSection.Endpoint)
+		// PersistentKeepalive Validate
+		if !validator.IsEmpty(p.PersistentKeepalive) && !validator.IsWireGuardPersistentKeepalive(p.PersistentKeepalive) {
+			log.Errorf("Failed to create WireGuardPeer='%s'. Invalid PersistentKeepalive='%s'", n.Name, p.PersistentKeepalive)
+			return fmt.Errorf("invalid persistentkeepalive='%s'", p.PersistentKeepalive)
+		}
 
-	// PresharedKey Validate
-	if !validator.IsEmpty(n.WireGuardPeerSection.PresharedKey) {
-		m.SetKeyToNewSectionString("PresharedKey", n.WireGuardPeerSection.PresharedKey)
-	}
-	// PresharedKeyFile Validate
-	if !validator.IsEmpty(n.WireGuardPeerSection.PresharedKeyFile) {
-		m.SetKeyToNewSectionString("PresharedKeyFile", n.WireGuardPeerSection.PresharedKeyFile)
-	}
-	// AllowedIPs Validate
-	if !validator.IsArrayEmpty(n.WireGuardPeerSection.AllowedIPs) {
-		for _, ip := range n.WireGuardPeerSection.AllowedIPs {
-			if !validator.IsIP(ip) {
-				log.Errorf("Failed to create WireGuardPeer='%s'. Invalid AllowedIPs='%s'", n.Name, n.WireGuardPeerSection.AllowedIPs)
-				return fmt.Errorf("invalid allowedips='%s'", n.WireGuardPeerSection.AllowedIPs)
+		if !validator.IsEmpty(p.RouteTable) && !validator.IsWireGuardRouteTable(p.RouteTable) {
+			log.Errorf("Failed to create WireGuardPeer='%s'. Invalid RouteTable='%s'", n.Name, p.RouteTable)
+			return fmt.Errorf("invalid routetable='%s'", p.RouteTable)
+		}
+		if !validator.IsEmpty(p.RouteMetric) && !validator.IsUint32(p.RouteMetric) {
+			log.Errorf("Failed to create WireGuardPeer='%s'. Invalid RouteMetric='%s'", n.Name, p.RouteMetric)
+			return fmt.Errorf("invalid routemetric='%s'", p.RouteMetric)
+		}
+
+		if !validator.IsEmpty(p.PresharedKey) && !validator.IsWireGuardKey(p.PresharedKey) {
+			log.Errorf("Failed to create WireGuardPeer='%s'. Invalid PresharedKey", n.Name)
+			return errors.New("invalid presharedkey")
+		}
+
+		if p.GeneratePresharedKey && validator.IsEmpty(p.PresharedKey) && validator.IsEmpty(p.PresharedKeyFile) {
+			p.PresharedKeyFile = buildWireGuardPresharedKeyFilePath(n.Name, p.PublicKey)
+			if _, err := readWireGuardKeyFile(p.PresharedKeyFile); err != nil {
+				psk, err := GenerateWireGuardPresharedKey()
+				if err != nil {
+					log.Errorf("Failed to create WireGuardPeer='%s'. Failed to generate preshared key: %v", n.Name, err)
+					return err
+				}
+				n.addKeyFile(p.PresharedKeyFile, psk)
 			}
 		}
-		m.SetKeyToNewSectionString("AllowedIPs", strings.Join(n.WireGuardPeerSection.AllowedIPs, " "))
+
+		m.NewSection("WireGuardPeer")
+		m.SetKeyToNewSectionString("PublicKey", p.PublicKey)
+
+		if !validator.IsEmpty(p.Endpoint) {
+			m.SetKeyToNewSectionString("Endpoint", p.Endpoint)
+		}
+		if !validator.IsEmpty(p.PresharedKey) {
+			m.SetKeyToNewSectionString("PresharedKey", p.PresharedKey)
+		}
+		if !validator.IsEmpty(p.PresharedKeyFile) {
+			m.SetKeyToNewSectionString("PresharedKeyFile", p.PresharedKeyFile)
+		}
+		if !validator.IsArrayEmpty(p.AllowedIPs) {
+			m.SetKeyToNewSectionString("AllowedIPs", strings.Join(p.AllowedIPs, " "))
+		}
+		if !validator.IsEmpty(p.PersistentKeepalive) {
+			m.SetKeyToNewSectionString("PersistentKeepalive", p.PersistentKeepalive)
+		}
+		if !validator.IsEmpty(p.RouteTable) {
+			m.SetKeyToNewSectionString("RouteTable", p.RouteTable)
+		}
+		if !validator.IsEmpty(p.RouteMetric) {
+			m.SetKeyToNewSectionString("RouteMetric", p.RouteMetric)
+		}
 	}
 
 	return nil
@@ -499,7 +696,313 @@ func (n *NetDev) buildTunOrTapSection(kind string, m *configfile.Meta) error {
 	return nil
 }
 
+func (n *NetDev) buildVRFSection(m *configfile.Meta) error {
+	m.NewSection("VRF")
+
+	if validator.IsEmpty(n.VRFSection.Table) {
+		log.Errorf("Failed to create VRF='%s'. Missing Table", n.Name)
+		return errors.New("missing vrf table")
+	}
+	if !validator.IsVRFTable(n.VRFSection.Table) {
+		log.Errorf("Failed to create VRF='%s'. Invalid Table='%s'", n.Name, n.VRFSection.Table)
+		return fmt.Errorf("invalid table='%s'", n.VRFSection.Table)
+	}
+	m.SetKeyToNewSectionString("Table", n.VRFSection.Table)
+
+	return nil
+}
+
+func (n *NetDev) buildPeerSection(m *configfile.Meta) error {
+	m.NewSection("Peer")
+
+	if validator.IsEmpty(n.PeerSection.Name) {
+		log.Errorf("Failed to create Veth='%s'. Missing Peer Name", n.Name)
+		return errors.New("missing veth peer name")
+	}
+	m.SetKeyToNewSectionString("Name", n.PeerSection.Name)
+
+	if !validator.IsEmpty(n.PeerSection.MACAddress) {
+		if validator.IsNotMAC(n.PeerSection.MACAddress) {
+			log.Errorf("Failed to create Veth='%s'. Invalid Peer MACAddress='%s'", n.Name, n.PeerSection.MACAddress)
+			return fmt.Errorf("invalid macaddress='%s'", n.PeerSection.MACAddress)
+		}
+		m.SetKeyToNewSectionString("MACAddress", n.PeerSection.MACAddress)
+	}
+
+	return nil
+}
+
+func (n *NetDev) buildGeneveSection(m *configfile.Meta) error {
+	m.NewSection("GENEVE")
+
+	// Mandatory Argument Check Id
+	if validator.IsEmpty(n.GeneveSection.Id) {
+		log.Errorf("Failed to create Geneve='%s'. Missing Id", n.Name)
+		return errors.New("missing geneve id")
+	}
+	if !validator.IsGeneveId(n.GeneveSection.Id) {
+		log.Errorf("Failed to create Geneve='%s'. Invalid Id='%s'", n.Name, n.GeneveSection.Id)
+		return fmt.Errorf("invalid id='%s'", n.GeneveSection.Id)
+	}
+	m.SetKeyToNewSectionString("Id", n.GeneveSection.Id)
+
+	if !validator.IsEmpty(n.GeneveSection.Remote) {
+		if !validator.IsIP(n.GeneveSection.Remote) {
+			log.Errorf("Failed to create Geneve='%s'. Invalid Remote='%s'", n.Name, n.GeneveSection.Remote)
+			return fmt.Errorf("invalid remote='%s'", n.GeneveSection.Remote)
+		}
+		m.SetKeyToNewSectionString("Remote", n.GeneveSection.Remote)
+	}
+
+	if !validator.IsEmpty(n.GeneveSection.TOS) {
+		if !validator.IsUint8(n.GeneveSection.TOS) {
+			log.Errorf("Failed to create Geneve='%s'. Invalid TOS='%s'", n.Name, n.GeneveSection.TOS)
+			return fmt.Errorf("invalid tos='%s'", n.GeneveSection.TOS)
+		}
+		m.SetKeyToNewSectionString("TOS", n.GeneveSection.TOS)
+	}
+
+	if !validator.IsEmpty(n.GeneveSection.TTL) {
+		if !validator.IsTunnelTTL(n.GeneveSection.TTL) {
+			log.Errorf("Failed to create Geneve='%s'. Invalid TTL='%s'", n.Name, n.GeneveSection.TTL)
+			return fmt.Errorf("invalid ttl='%s'", n.GeneveSection.TTL)
+		}
+		m.SetKeyToNewSectionString("TTL", n.GeneveSection.TTL)
+	}
+
+	if !validator.IsEmpty(n.GeneveSection.UDPChecksum) {
+		if !validator.IsBool(n.GeneveSection.UDPChecksum) {
+			log.Errorf("Failed to create Geneve='%s'. Invalid UDPChecksum='%s'", n.Name, n.GeneveSection.UDPChecksum)
+			return fmt.Errorf("invalid udpchecksum='%s'", n.GeneveSection.UDPChecksum)
+		}
+		m.SetKeyToNewSectionString("UDPChecksum", validator.BoolToString(n.GeneveSection.UDPChecksum))
+	}
+
+	if !validator.IsEmpty(n.GeneveSection.DestinationPort) {
+		if !validator.IsPort(n.GeneveSection.DestinationPort) {
+			log.Errorf("Failed to create Geneve='%s'. Invalid DestinationPort='%s'", n.Name, n.GeneveSection.DestinationPort)
+			return fmt.Errorf("invalid destinationport='%s'", n.GeneveSection.DestinationPort)
+		}
+		m.SetKeyToNewSectionString("DestinationPort", n.GeneveSection.DestinationPort)
+	}
+
+	if !validator.IsEmpty(n.GeneveSection.FlowLabel) {
+		if !validator.IsGeneveFlowLabel(n.GeneveSection.FlowLabel) {
+			log.Errorf("Failed to create Geneve='%s'. Invalid FlowLabel='%s'", n.Name, n.GeneveSection.FlowLabel)
+			return fmt.Errorf("invalid flowlabel='%s'", n.GeneveSection.FlowLabel)
+		}
+		m.SetKeyToNewSectionString("FlowLabel", n.GeneveSection.FlowLabel)
+	}
+
+	if !validator.IsEmpty(n.GeneveSection.IPDoNotFragment) {
+		if !validator.IsGeneveIPDoNotFragment(n.GeneveSection.IPDoNotFragment) {
+			log.Errorf("Failed to create Geneve='%s'. Invalid IPDoNotFragment='%s'", n.Name, n.GeneveSection.IPDoNotFragment)
+			return fmt.Errorf("invalid ipdonotfragment='%s'", n.GeneveSection.IPDoNotFragment)
+		}
+		m.SetKeyToNewSectionString("IPDoNotFragment", n.GeneveSection.IPDoNotFragment)
+	}
+
+	return nil
+}
+
+func (n *NetDev) buildMACsecSection(m *configfile.Meta) error {
+	m.NewSection("MACsec")
+
+	if !validator.IsEmpty(n.MACsecSection.Port) {
+		if !validator.IsMACsecPort(n.MACsecSection.Port) {
+			log.Errorf("Failed to create MACsec='%s'. Invalid Port='%s'", n.Name, n.MACsecSection.Port)
+			return fmt.Errorf("invalid port='%s'", n.MACsecSection.Port)
+		}
+		m.SetKeyToNewSectionString("Port", n.MACsecSection.Port)
+	}
+
+	if !validator.IsEmpty(n.MACsecSection.Encrypt) {
+		if !validator.IsBool(n.MACsecSection.Encrypt) {
+			log.Errorf("Failed to create MACsec='%s'. Invalid Encrypt='%s'", n.Name, n.MACsecSection.Encrypt)
+			return fmt.Errorf("invalid encrypt='%s'", n.MACsecSection.Encrypt)
+		}
+		m.SetKeyToNewSectionString("Encrypt", validator.BoolToString(n.MACsecSection.Encrypt))
+	}
+
+	rc := n.MACsecReceiveChannelSection
+	if !validator.IsEmpty(rc.Port) || !validator.IsEmpty(rc.MACAddress) {
+		if validator.IsEmpty(rc.Port) || validator.IsEmpty(rc.MACAddress) {
+			log.Errorf("Failed to create MACsec='%s'. ReceiveChannel needs Port and MACAddress", n.Name)
+			return errors.New("missing macsec receive channel port or macaddress")
+		}
+		if !validator.IsMACsecPort(rc.Port) {
+			log.Errorf("Failed to create MACsec='%s'. Invalid ReceiveChannel Port='%s'", n.Name, rc.Port)
+			return fmt.Errorf("invalid port='%s'", rc.Port)
+		}
+		if validator.IsNotMAC(rc.MACAddress) {
+			log.Errorf("Failed to create MACsec='%s'. Invalid ReceiveChannel MACAddress='%s'", n.Name, rc.MACAddress)
+			return fmt.Errorf("invalid macaddress='%s'", rc.MACAddress)
+		}
+
+		m.NewSection("MACsecReceiveChannel")
+		m.SetKeyToNewSectionString("Port", rc.Port)
+		m.SetKeyToNewSectionString("MACAddress", rc.MACAddress)
+	}
+
+	ta := n.MACsecTransmitAssociationSection
+	if !validator.IsEmpty(ta.Key) || !validator.IsEmpty(ta.KeyFile) {
+		if err := n.validateMACsecAssociation(ta.PacketNumber, ta.KeyId, ta.Key, ta.Activate); err != nil {
+			return err
+		}
+		if !validator.IsEmpty(ta.UseForEncoding) && !validator.IsBool(ta.UseForEncoding) {
+			log.Errorf("Failed to create MACsec='%s'. Invalid UseForEncoding='%s'", n.Name, ta.UseForEncoding)
+			return fmt.Errorf("invalid useforencoding='%s'", ta.UseForEncoding)
+		}
+
+		m.NewSection("MACsecTransmitAssociation")
+		n.setMACsecAssociation(m, ta.PacketNumber, ta.KeyId, ta.Key, ta.KeyFile, ta.Activate)
+		if !validator.IsEmpty(ta.UseForEncoding) {
+			m.SetKeyToNewSectionString("UseForEncoding", validator.BoolToString(ta.UseForEncoding))
+		}
+	}
+
+	ra := n.MACsecReceiveAssociationSection
+	if !validator.IsEmpty(ra.Key) || !validator.IsEmpty(ra.KeyFile) {
+		if validator.IsEmpty(ra.Port) || validator.IsEmpty(ra.MACAddress) {
+			log.Errorf("Failed to create MACsec='%s'. ReceiveAssociation needs Port and MACAddress", n.Name)
+			return errors.New("missing macsec receive association port or macaddress")
+		}
+		if !validator.IsMACsecPort(ra.Port) {
+			log.Errorf("Failed to create MACsec='%s'. Invalid ReceiveAssociation Port='%s'", n.Name, ra.Port)
+			return fmt.Errorf("invalid port='%s'", ra.Port)
+		}
+		if validator.IsNotMAC(ra.MACAddress) {
+			log.Errorf("Failed to create MACsec='%s'. Invalid ReceiveAssociation MACAddress='%s'", n.Name, ra.MACAddress)
+			return fmt.Errorf("invalid macaddress='%s'", ra.MACAddress)
+		}
+		if err := n.validateMACsecAssociation(ra.PacketNumber, ra.KeyId, ra.Key, ra.Activate); err != nil {
+			return err
+		}
+
+		m.NewSection("MACsecReceiveAssociation")
+		m.SetKeyToNewSectionString("Port", ra.Port)
+		m.SetKeyToNewSectionString("MACAddress", ra.MACAddress)
+		n.setMACsecAssociation(m, ra.PacketNumber, ra.KeyId, ra.Key, ra.KeyFile, ra.Activate)
+	}
+
+	return nil
+}
+
+func (n *NetDev) validateMACsecAssociation(packetNumber, keyId, key, activate string) error {
+	if !validator.IsEmpty(packetNumber) && !validator.IsMACsecPacketNumber(packetNumber) {
+		log.Errorf("Failed to create MACsec='%s'. Invalid PacketNumber='%s'", n.Name, packetNumber)
+		return fmt.Errorf("invalid packetnumber='%s'", packetNumber)
+	}
+	if !validator.IsEmpty(keyId) && !validator.IsMACsecKeyId(keyId) {
+		log.Errorf("Failed to create MACsec='%s'. Invalid KeyId='%s'", n.Name, keyId)
+		return fmt.Errorf("invalid keyid='%s'", keyId)
+	}
+	if !validator.IsEmpty(key) && !validator.IsMACsecKey(key) {
+		log.Errorf("Failed to create MACsec='%s'. Invalid Key", n.Name)
+		return errors.New("invalid key")
+	}
+	if !validator.IsEmpty(activate) && !validator.IsBool(activate) {
+		log.Errorf("Failed to create MACsec='%s'. Invalid Activate='%s'", n.Name, activate)
+		return fmt.Errorf("invalid activate='%s'", activate)
+	}
+
+	return nil
+}
+
+func (n *NetDev) setMACsecAssociation(m *configfile.Meta, packetNumber, keyId, key, keyFile, activate string) {
+	if !validator.IsEmpty(packetNumber) {
+		m.SetKeyToNewSectionString("PacketNumber", packetNumber)
+	}
+	if !validator.IsEmpty(keyId) {
+		m.SetKeyToNewSectionString("KeyId", keyId)
+	}
+	if !validator.IsEmpty(key) {
+		m.SetKeyToNewSectionString("Key", key)
+	}
+	if !validator.IsEmpty(keyFile) {
+		m.SetKeyToNewSectionString("KeyFile", keyFile)
+	}
+	if !validator.IsEmpty(activate) {
+		m.SetKeyToNewSectionString("Activate", validator.BoolToString(activate))
+	}
+}
+
+func (n *NetDev) buildTunnelSection(m *configfile.Meta) error {
+	m.NewSection("Tunnel")
+
+	if !validator.IsEmpty(n.TunnelSection.Local) {
+		if !validator.IsTunnelAddress(n.TunnelSection.Local) {
+			log.Errorf("Failed to create %s='%s'. Invalid Local='%s'", n.Kind, n.Name, n.TunnelSection.Local)
+			return fmt.Errorf("invalid local='%s'", n.TunnelSection.Local)
+		}
+		m.SetKeyToNewSectionString("Local", n.TunnelSection.Local)
+	}
+
+	if !validator.IsEmpty(n.TunnelSection.Remote) {
+		if !validator.IsTunnelAddress(n.TunnelSection.Remote) {
+			log.Errorf("Failed to create %s='%s'. Invalid Remote='%s'", n.Kind, n.Name, n.TunnelSection.Remote)
+			return fmt.Errorf("invalid remote='%s'", n.TunnelSection.Remote)
+		}
+		m.SetKeyToNewSectionString("Remote", n.TunnelSection.Remote)
+	}
+
+	if !validator.IsEmpty(n.TunnelSection.TOS) {
+		if !validator.IsUint8(n.TunnelSection.TOS) {
+			log.Errorf("Failed to create %s='%s'. Invalid TOS='%s'", n.Kind, n.Name, n.TunnelSection.TOS)
+			return fmt.Errorf("invalid tos='%s'", n.TunnelSection.TOS)
+		}
+		m.SetKeyToNewSectionString("TOS", n.TunnelSection.TOS)
+	}
+
+	if !validator.IsEmpty(n.TunnelSection.TTL) {
+		if !validator.IsTunnelTTL(n.TunnelSection.TTL) {
+			log.Errorf("Failed to create %s='%s'. Invalid TTL='%s'", n.Kind, n.Name, n.TunnelSection.TTL)
+			return fmt.Errorf("invalid ttl='%s'", n.TunnelSection.TTL)
+		}
+		m.SetKeyToNewSectionString("TTL", n.TunnelSection.TTL)
+	}
+
+	for _, kv := range []struct{ key, value string }{
+		{"Key", n.TunnelSection.Key},
+		{"InputKey", n.TunnelSection.InputKey},
+		{"OutputKey", n.TunnelSection.OutputKey},
+	} {
+		if validator.IsEmpty(kv.value) {
+			continue
+		}
+
+		if !validator.IsTunnelKey(kv.value) {
+			log.Errorf("Failed to create %s='%s'. Invalid %s='%s'", n.Kind, n.Name, kv.key, kv.value)
+			return fmt.Errorf("invalid %s='%s'", strings.ToLower(kv.key), kv.value)
+		}
+		m.SetKeyToNewSectionString(kv.key, kv.value)
+	}
+
+	for _, kv := range []struct{ key, value string }{
+		{"DiscoverPathMTU", n.TunnelSection.DiscoverPathMTU},
+		{"Independent", n.TunnelSection.Independent},
+		{"AssignToLoopback", n.TunnelSection.AssignToLoopback},
+	} {
+		if validator.IsEmpty(kv.value) {
+			continue
+		}
+
+		if !validator.IsBool(kv.value) {
+			log.Errorf("Failed to create %s='%s'. Invalid %s='%s'", n.Kind, n.Name, kv.key, kv.value)
+			return fmt.Errorf("invalid %s='%s'", strings.ToLower(kv.key), kv.value)
+		}
+		m.SetKeyToNewSectionString(kv.key, validator.BoolToString(kv.value))
+	}
+
+	return nil
+}
+
 func (n *NetDev) BuildKindInLinkNetworkFile() error {
+	// veth, dummy and geneve are not stacked on another link
+	if netDevKindToNetworkKind(n.Kind) == "" {
+		return nil
+	}
+
 	for _, l := range n.Links {
 		m, err := CreateOrParseNetworkFile(l)
 		if err != nil {
@@ -572,6 +1075,32 @@ func (n *NetDev) BuildKindSection(m *configfile.Meta) error {
 			log.Errorf("Failed to create %s ='%s': %v", n.Kind, n.Name, err)
 			return err
 		}
+	case "vrf":
+		if err := n.buildVRFSection(m); err != nil {
+			log.Errorf("Failed to create VRF ='%s': %v", n.Name, err)
+			return err
+		}
+	case "veth":
+		if err := n.buildPeerSection(m); err != nil {
+			log.Errorf("Failed to create Veth ='%s': %v", n.Name, err)
+			return err
+		}
+	case "dummy":
+	case "geneve":
+		if err := n.buildGeneveSection(m); err != nil {
+			log.Errorf("Failed to create Geneve ='%s': %v", n.Name, err)
+			return err
+		}
+	case "macsec":
+		if err := n.buildMACsecSection(m); err != nil {
+			log.Errorf("Failed to create MACsec ='%s': %v", n.Name, err)
+			return err
+		}
+	case "ipip", "gre", "gretap", "ip6gre", "sit", "vti":
+		if err := n.buildTunnelSection(m); err != nil {
+			log.Errorf("Failed to create %s ='%s': %v", n.Kind, n.Name, err)
+			return err
+		}
 	}
 
 	return nil
@@ -594,8 +1123,13 @@ func (n *NetDev) ConfigureNetDev(ctx context.Context, w http.ResponseWriter) err
 		return err
 	}
 
+	if err := n.writeKeyFiles(); err != nil {
+		return err
+	}
+
 	if err := m.Save(); err != nil {
 		log.Errorf("Failed to update config file='%s': %v", m.Path, err)
+		n.removeKeyFiles()
 		return err
 	}
 
@@ -615,6 +1149,16 @@ func (n *NetDev) ConfigureNetDev(ctx context.Context, w http.ResponseWriter) err
 		return err
 	}
 
+	// The public key and generated preshared keys are needed to configure
+	// the remote side
+	if n.Kind == "wireguard" {
+		return web.JSONResponse(WireGuardPublicKey{
+			Name:          n.Name,
+			PublicKey:     n.wireGuardPublicKey(),
+			PresharedKeys: n.wireGuardPresharedKeys(),
+		}, w)
+	}
+
 	return web.JSONResponse("configured", w)
 }
 
@@ -634,3 +1178,689 @@ func (n *NetDev) RemoveNetDev(ctx context.Context, w http.ResponseWriter) error
 
 	return web.JSONResponse("removed", w)
 }
+
+type NetDevBondAdInfo struct {
+	AggregatorId int    `json:"AggregatorId"`
+	NumPorts     int    `json:"NumPorts"`
+	ActorKey     int    `json:"ActorKey"`
+	PartnerKey   int    `json:"PartnerKey"`
+	PartnerMAC   string `json:"PartnerMAC"`
+}
+
+type NetDevMemberDescribe struct {
+	Name      string `json:"Name"`
+	Index     int    `json:"Index"`
+	OperState string `json:"OperState"`
+
+	// Bond slave
+	State                  string `json:"State,omitempty"`
+	MiiStatus              string `json:"MiiStatus,omitempty"`
+	LinkFailureCount       uint32 `json:"LinkFailureCount,omitempty"`
+	PermanentMACAddress    string `json:"PermanentMACAddress,omitempty"`
+	AggregatorId           uint16 `json:"AggregatorId,omitempty"`
+	AdActorOperPortState   uint8  `json:"AdActorOperPortState,omitempty"`
+	AdPartnerOperPortState uint16 `json:"AdPartnerOperPortState,omitempty"`
+
+	// Bridge port
+	STPState string `json:"STPState,omitempty"`
+	Cost     string `json:"Cost,omitempty"`
+	Priority string `json:"Priority,omitempty"`
+}
+
+type NetDevDescribe struct {
+	Name       string  `json:"Name"`
+	Kind       string  `json:"Kind"`
+	Index      int     `json:"Index"`
+	OperState  string  `json:"OperState"`
+	MTU        int     `json:"MTU"`
+	MACAddress string  `json:"MACAddress"`
+	NetDevFile string  `json:"NetDevFile"`
+	Config     *NetDev `json:"Config"`
+
+	// Bond
+	BondMode           string            `json:"BondMode,omitempty"`
+	ActiveSlave        string            `json:"ActiveSlave,omitempty"`
+	TransmitHashPolicy string            `json:"TransmitHashPolicy,omitempty"`
+	LACPTransmitRate   string            `json:"LACPTransmitRate,omitempty"`
+	MIIMonitorMSec     int               `json:"MIIMonitorMSec,omitempty"`
+	AdInfo             *NetDevBondAdInfo `json:"AdInfo,omitempty"`
+
+	// Bridge
+	STP           string `json:"STP,omitempty"`
+	VLANFiltering bool   `json:"VLANFiltering,omitempty"`
+
+	// VLAN
+	VLANId int    `json:"VLANId,omitempty"`
+	Parent string `json:"Parent,omitempty"`
+
+	// VXLAN
+	VNI             int    `json:"VNI,omitempty"`
+	Remote          string `json:"Remote,omitempty"`
+	Local           string `json:"Local,omitempty"`
+	DestinationPort int    `json:"DestinationPort,omitempty"`
+
+	Members []NetDevMemberDescribe `json:"Members"`
+}
+
+func parseNetDevSections(m *configfile.Meta) (*NetDev, error) {
+	n := NetDev{}
+
+	var errs []error
+
+	n.Name = m.GetKeySectionString("NetDev", "Name")
+	n.Kind = m.GetKeySectionString("NetDev", "Kind")
+	n.Description = m.GetKeySectionString("NetDev", "Description")
+	n.MTUBytes = m.GetKeySectionString("NetDev", "MTUBytes")
+	n.MACAddress = m.GetKeySectionString("NetDev", "MACAddress")
+
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Match"), &n.MatchSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("VLAN"), &n.VLanSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("MACVLAN"), &n.MacVLanSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("MACVTAP"), &n.MacVLanSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("IPVLAN"), &n.IpVLanSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("VXLAN"), &n.VxLanSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Bond"), &n.BondSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Bridge"), &n.BridgeSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("WireGuard"), &n.WireGuardSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Tun"), &n.TunOrTapSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Tap"), &n.TunOrTapSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("VRF"), &n.VRFSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Peer"), &n.PeerSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("GENEVE"), &n.GeneveSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("MACsec"), &n.MACsecSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("MACsecReceiveChannel"), &n.MACsecReceiveChannelSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("MACsecTransmitAssociation"), &n.MACsecTransmitAssociationSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("MACsecReceiveAssociation"), &n.MACsecReceiveAssociationSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Tunnel"), &n.TunnelSection))
+
+	for _, s := range m.Sections("WireGuardPeer") {
+		p := WireGuardPeer{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &p))
+		n.WireGuardPeerSections = append(n.WireGuardPeerSections, p)
+	}
+
+	return &n, errors.Join(errs...)
+}
+
+func bridgeSTPStateToString(state string) string {
+	switch state {
+	case "0":
+		return "disabled"
+	case "1":
+		return "listening"
+	case "2":
+		return "learning"
+	case "3":
+		return "forwarding"
+	case "4":
+		return "blocking"
+	}
+
+	return state
+}
+
+func fillNetDevMember(link netlink.Link) NetDevMemberDescribe {
+	d := NetDevMemberDescribe{
+		Name:      link.Attrs().Name,
+		Index:     link.Attrs().Index,
+		OperState: link.Attrs().OperState.String(),
+	}
+
+	if s, ok := link.Attrs().Slave.(*netlink.BondSlave); ok {
+		d.State = s.State.String()
+		d.MiiStatus = s.MiiStatus.String()
+		d.LinkFailureCount = s.LinkFailureCount
+		d.PermanentMACAddress = s.PermHardwareAddr.String()
+		d.AggregatorId = s.AggregatorId
+		d.AdActorOperPortState = s.AdActorOperPortState
+		d.AdPartnerOperPortState = s.AdPartnerOperPortState
+	}
+
+	brport := path.Join("/sys/class/net", link.Attrs().Name, "brport")
+	if system.PathExists(brport) {
+		state, _ := system.ReadOneLineFile(path.Join(brport, "state"))
+		d.STPState = bridgeSTPStateToString(state)
+		d.Cost, _ = system.ReadOneLineFile(path.Join(brport, "path_cost"))
+		d.Priority, _ = system.ReadOneLineFile(path.Join(brport, "priority"))
+	}
+
+	return d
+}
+
+func fillNetDevLink(link netlink.Link, d *NetDevDescribe) error {
+	d.Kind = link.Type()
+	d.Index = link.Attrs().Index
+	d.OperState = link.Attrs().OperState.String()
+	d.MTU = link.Attrs().MTU
+	d.MACAddress = link.Attrs().HardwareAddr.String()
+
+	switch l := link.(type) {
+	case *netlink.Bond:
+		d.BondMode = l.Mode.String()
+		d.TransmitHashPolicy = l.XmitHashPolicy.String()
+		d.MIIMonitorMSec = l.Miimon
+		if l.Mode == netlink.BOND_MODE_802_3AD {
+			d.LACPTransmitRate = l.LacpRate.String()
+		}
+		if l.ActiveSlave > 0 {
+			if s, err := netlink.LinkByIndex(l.ActiveSlave); err == nil {
+				d.ActiveSlave = s.Attrs().Name
+			}
+		}
+		if l.AdInfo != nil {
+			d.AdInfo = &NetDevBondAdInfo{
+				AggregatorId: l.AdInfo.AggregatorId,
+				NumPorts:     l.AdInfo.NumPorts,
+				ActorKey:     l.AdInfo.ActorKey,
+				PartnerKey:   l.AdInfo.PartnerKey,
+				PartnerMAC:   l.AdInfo.PartnerMac.String(),
+			}
+		}
+	case *netlink.Bridge:
+		if l.VlanFiltering != nil {
+			d.VLANFiltering = *l.VlanFiltering
+		}
+		if stp, err := system.ReadOneLineFile(path.Join("/sys/class/net", l.Name, "bridge/stp_state")); err == nil {
+			if stp == "0" {
+				d.STP = "no"
+			} else {
+				d.STP = "yes"
+			}
+		}
+	case *netlink.Vlan:
+		d.VLANId = l.VlanId
+	case *netlink.Vxlan:
+		d.VNI = l.VxlanId
+		d.DestinationPort = l.Port
+		if l.Group != nil {
+			d.Remote = l.Group.String()
+		}
+		if l.SrcAddr != nil {
+			d.Local = l.SrcAddr.String()
+		}
+		if l.VtepDevIndex > 0 {
+			if p, err := netlink.LinkByIndex(l.VtepDevIndex); err == nil {
+				d.Parent = p.Attrs().Name
+			}
+		}
+	}
+
+	if link.Attrs().ParentIndex > 0 {
+		if p, err := netlink.LinkByIndex(link.Attrs().ParentIndex); err == nil {
+			d.Parent = p.Attrs().Name
+		}
+	}
+
+	links, err := netlink.LinkList()
+	if err != nil {
+		return err
+	}
+
+	for _, l := range links {
+		if l.Attrs().MasterIndex == link.Attrs().Index {
+			d.Members = append(d.Members, fillNetDevMember(l))
+		}
+	}
+
+	return nil
+}
+
+func AcquireNetDev(ctx context.Context, name string, w http.ResponseWriter) error {
+	d := NetDevDescribe{
+		Name:    name,
+		Members: []NetDevMemberDescribe{},
+	}
+
+	m, err := FindNetDevFile(name)
+	if err == nil {
+		d.NetDevFile = m.Path
+		d.Config, err = parseNetDevSections(m)
+		if err != nil {
+			log.Errorf("Failed to parse .netdev file='%s': %v", m.Path, err)
+			return err
+		}
+		d.Config.Links, _ = FindNetDevLinks(name, d.Config.Kind)
+		d.Kind = d.Config.Kind
+
+		// Keys are never served back
+		d.Config.WireGuardSection.PrivateKey = ""
+		for i := range d.Config.WireGuardPeerSections {
+			d.Config.WireGuardPeerSections[i].PresharedKey = ""
+		}
+		d.Config.MACsecTransmitAssociationSection.Key = ""
+		d.Config.MACsecReceiveAssociationSection.Key = ""
+	}
+
+	link, lerr := netlink.LinkByName(name)
+	if lerr != nil && d.Config == nil {
+		log.Errorf("Failed to acquire netdev='%s': %v", name, lerr)
+		return fmt.Errorf("netdev='%s' not found", name)
+	}
+
+	if lerr == nil {
+		if err := fillNetDevLink(link, &d); err != nil {
+			log.Errorf("Failed to acquire members of netdev='%s': %v", name, err)
+			return err
+		}
+	}
+
+	return web.JSONResponse(d, w)
+}
+
+// NetDevPatch is an in place update of an existing netdev. Fields present in
+// the request override the .netdev, a field set to its zero value removes the
+// key. Links are attached or detached incrementally.
+type NetDevPatch struct {
+	NetDev
+
+	AddLinks    []string `json:"AddLinks"`
+	RemoveLinks []string `json:"RemoveLinks"`
+
+	// The request as sent, tells fields left out from fields set to zero
+	raw map[string]json.RawMessage
+}
+
+// The .netdev sections of the NetDev kind sections, MacVLanSection and
+// TunOrTapSection depend on the kind
+var netDevPatchSections = map[string]string{
+	"VLanSection":                      "VLAN",
+	"IpVLanSection":                    "IPVLAN",
+	"VxLanSection":                     "VXLAN",
+	"BondSection":                      "Bond",
+	"BridgeSection":                    "Bridge",
+	"WireGuardSection":                 "WireGuard",
+	"VRFSection":                       "VRF",
+	"PeerSection":                      "Peer",
+	"GeneveSection":                    "GENEVE",
+	"MACsecSection":                    "MACsec",
+	"MACsecReceiveChannelSection":      "MACsecReceiveChannel",
+	"MACsecTransmitAssociationSection": "MACsecTransmitAssociation",
+	"MACsecReceiveAssociationSection":  "MACsecReceiveAssociation",
+	"TunnelSection":                    "Tunnel",
+}
+
+func decodeNetDevPatchJSONRequest(r *http.Request) (*NetDevPatch, error) {
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NetDevPatch{}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &p.raw); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// MarshalJSON leaves out the zero fields, so a patch built from the struct
+// only carries what is set
+func (p NetDevPatch) MarshalJSON() ([]byte, error) {
+	type netDevPatch NetDevPatch
+
+	b, err := json.Marshal(netDevPatch(p))
+	if err != nil {
+		return nil, err
+	}
+
+	v := make(map[string]interface{})
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(pruneZeroJSON(v))
+}
+
+func pruneZeroJSON(v map[string]interface{}) map[string]interface{} {
+	for k, e := range v {
+		if o, ok := e.(map[string]interface{}); ok {
+			pruneZeroJSON(o)
+		}
+		if isZeroJSON(v[k]) {
+			delete(v, k)
+		}
+	}
+
+	return v
+}
+
+func isZeroJSON(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !t
+	case string:
+		return t == ""
+	case float64:
+		return t == 0
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+
+	return false
+}
+
+// patchedKey is a .netdev key present in the patch. A key set to its zero
+// value is removed.
+type patchedKey struct {
+	Section string
+	Key     string
+	Remove  bool
+}
+
+func newPatchedKey(section string, key string, raw json.RawMessage) (patchedKey, error) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return patchedKey{}, err
+	}
+
+	return patchedKey{Section: section, Key: key, Remove: isZeroJSON(v)}, nil
+}
+
+// patchedKeys returns the .netdev keys present in the patch. Field names are
+// the key names. Fields that can not be patched are refused.
+func (p *NetDevPatch) patchedKeys(n *NetDev) ([]patchedKey, error) {
+	keys := []patchedKey{}
+	for field, raw := range p.raw {
+		switch field {
+		case "AddLinks", "RemoveLinks":
+			continue
+		case "Name", "Kind":
+			var s string
+			if err := json.Unmarshal(raw, &s); err != nil {
+				return nil, fmt.Errorf("invalid %s", field)
+			}
+
+			if field == "Name" && !validator.IsEmpty(s) && s != n.Name {
+				log.Errorf("Failed to update netdev='%s'. Name can not be changed to '%s'", n.Name, s)
+				return nil, fmt.Errorf("netdev='%s' can not be renamed", n.Name)
+			}
+			if field == "Kind" && !validator.IsEmpty(s) && s != n.Kind {
+				log.Errorf("Failed to update netdev='%s'. Kind can not be changed from '%s' to '%s'", n.Name, n.Kind, s)
+				return nil, fmt.Errorf("kind of netdev='%s' can not be changed", n.Name)
+			}
+			continue
+		case "Description", "MTUBytes", "MACAddress":
+			k, err := newPatchedKey("NetDev", field, raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s", field)
+			}
+			keys = append(keys, k)
+			continue
+		}
+
+		section, ok := netDevPatchSections[field]
+		switch field {
+		case "MacVLanSection", "TunOrTapSection":
+			section, ok = netDevKindToNetworkKind(n.Kind), true
+		}
+		t, found := reflect.TypeOf(NetDev{}).FieldByName(field)
+		if !ok || !found {
+			log.Errorf("Failed to update netdev='%s'. %s can not be patched", n.Name, field)
+			return nil, fmt.Errorf("%s of netdev='%s' can not be patched", field, n.Name)
+		}
+
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, fmt.Errorf("invalid %s", field)
+		}
+		for f, v := range fields {
+			if _, ok := t.Type.FieldByName(f); !ok {
+				log.Errorf("Failed to update netdev='%s'. Unknown key %s.%s", n.Name, field, f)
+				return nil, fmt.Errorf("unknown key %s.%s", field, f)
+			}
+
+			k, err := newPatchedKey(section, f, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s.%s", field, f)
+			}
+			keys = append(keys, k)
+		}
+	}
+
+	return keys, nil
+}
+
+// mergeNetDev overlays the fields present in the patch on the netdev, zero
+// values included
+func (p *NetDevPatch) mergeNetDev(n *NetDev) error {
+	b, err := json.Marshal(p.raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, n)
+}
+
+// setNetDevKey updates the key where it is declared, so its position and
+// comment stay
+func setNetDevKey(m *configfile.Meta, section string, key string, value string) error {
+	for _, s := range m.Sections(section) {
+		if s.HasKey(key) {
+			s.Key(key).SetValue(value)
+			return nil
+		}
+	}
+
+	return m.SetKeySectionString(section, key, value)
+}
+
+// removeNetDevKey drops every assignment of the key in the section
+func removeNetDevKey(m *configfile.Meta, section string, key string) {
+	for _, s := range m.Sections(section) {
+		s.DeleteKey(key)
+	}
+}
+
+// overrideVendorFile points a file shipped outside /etc at its override in
+// /etc/systemd/network, so saving it leaves the vendor copy alone
+func overrideVendorFile(m *configfile.Meta) {
+	if path.Dir(m.Path) != "/etc/systemd/network" {
+		m.Path = path.Join("/etc/systemd/network", path.Base(m.Path))
+	}
+}
+
+// attachLinks stacks the netdev on the added links. The files are only
+// updated in memory.
+func (p *NetDevPatch) attachLinks(name string, kind string) ([]*configfile.Meta, error) {
+	key := netDevKindToNetworkKind(kind)
+	if key == "" && len(p.AddLinks) > 0 {
+		return nil, fmt.Errorf("netdev kind='%s' has no member links", kind)
+	}
+
+	var files []*configfile.Meta
+	for _, l := range p.AddLinks {
+		m, err := CreateOrParseNetworkFile(l)
+		if err != nil {
+			log.Errorf("Failed to parse network file for link='%s': %v", l, err)
+			return nil, fmt.Errorf("link='%s' %v", l, err.Error())
+		}
+
+		if !stacksNetDev(m, key, name) {
+			if err := m.NewKeyToSectionString("Network", key, name); err != nil {
+				log.Errorf("Failed to update .network file of link='%s': %v", l, err)
+				return nil, err
+			}
+
+			overrideVendorFile(m)
+			files = append(files, m)
+		}
+	}
+
+	return files, nil
+}
+
+// detachLinks unstacks the netdev from the removed links. The files are only
+// updated in memory.
+func (p *NetDevPatch) detachLinks(name string, kind string) ([]*configfile.Meta, error) {
+	key := netDevKindToNetworkKind(kind)
+	if key == "" && len(p.RemoveLinks) > 0 {
+		return nil, fmt.Errorf("netdev kind='%s' has no member links", kind)
+	}
+
+	members, err := findNetDevLinkFiles(name, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*configfile.Meta
+	for _, l := range p.RemoveLinks {
+		m := findNetDevLinkFile(members, l)
+		if m == nil {
+			log.Errorf("Failed to remove link='%s' from netdev='%s': not a member", l, name)
+			return nil, fmt.Errorf("link='%s' is not a member of netdev='%s'", l, name)
+		}
+
+		// A link may carry several VLAN= and the like, keep the others
+		for _, s := range m.Sections("Network") {
+			if !s.HasKey(key) {
+				continue
+			}
+
+			values := s.Key(key).ValueWithShadows()
+			s.DeleteKey(key)
+			for _, v := range values {
+				if v != name {
+					s.NewKey(key, v)
+				}
+			}
+		}
+		overrideVendorFile(m)
+
+		files = append(files, m)
+	}
+
+	return files, nil
+}
+
+// releaseLinks detaches the removed ports, networkd never releases a port
+// from its master on its own
+func (p *NetDevPatch) releaseLinks(name string, kind string) error {
+	if kind != "bond" && kind != "bridge" && kind != "vrf" {
+		return nil
+	}
+
+	for _, l := range p.RemoveLinks {
+		if link, err := netlink.LinkByName(l); err == nil && link.Attrs().MasterIndex > 0 {
+			if err := netlink.LinkSetNoMaster(link); err != nil {
+				log.Errorf("Failed to release link='%s' from netdev='%s': %v", l, name, err)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (p *NetDevPatch) PatchNetDev(ctx context.Context, w http.ResponseWriter) error {
+	m, err := FindNetDevFile(p.Name)
+	if err != nil {
+		log.Errorf("Failed to find netdev='%s': %v", p.Name, err)
+		return err
+	}
+
+	n, err := parseNetDevSections(m)
+	if err != nil {
+		log.Errorf("Failed to parse .netdev file='%s': %v", m.Path, err)
+		return err
+	}
+	keys, err := p.patchedKeys(n)
+	if err != nil {
+		return err
+	}
+	if err := p.mergeNetDev(n); err != nil {
+		return err
+	}
+
+	// The merged netdev is built aside to validate the whole document. Only
+	// the keys present in the patch are carried over, keys and sections pmd
+	// does not know about are left as they are.
+	v := &configfile.Meta{Cfg: ini.Empty(ini.LoadOptions{AllowNonUniqueSections: true, AllowShadows: true})}
+	if err := n.BuildNetDevSection(v); err != nil {
+		return err
+	}
+	if err := n.BuildKindSection(v); err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if k.Remove {
+			removeNetDevKey(m, k.Section, k.Key)
+			continue
+		}
+
+		value := v.GetKeySectionString(k.Section, k.Key)
+		if validator.IsEmpty(value) {
+			log.Errorf("Failed to update netdev='%s'. [%s] %s can not be patched", p.Name, k.Section, k.Key)
+			return fmt.Errorf("[%s] %s of netdev='%s' can not be patched", k.Section, k.Key, p.Name)
+		}
+
+		if err := setNetDevKey(m, k.Section, k.Key, value); err != nil {
+			log.Errorf("Failed to update netdev='%s' %s=%s: %v", p.Name, k.Key, value, err)
+			return err
+		}
+	}
+	overrideVendorFile(m)
+
+	// Nothing is written unless every file could be prepared
+	detached, err := p.detachLinks(n.Name, n.Kind)
+	if err != nil {
+		return err
+	}
+	attached, err := p.attachLinks(n.Name, n.Kind)
+	if err != nil {
+		return err
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	files := append([]*configfile.Meta{m}, detached...)
+	files = append(files, attached...)
+	for _, f := range files {
+		created := !system.PathExists(f.Path)
+		if err := f.Save(); err != nil {
+			log.Errorf("Failed to update config file='%s': %v", f.Path, err)
+			return err
+		}
+
+		if created {
+			system.ChangePermission("systemd-network", f.Path)
+		}
+	}
+
+	if err := p.releaseLinks(n.Name, n.Kind); err != nil {
+		return err
+	}
+
+	if err := c.DBusNetworkReload(ctx); err != nil {
+		return err
+	}
+
+	// Reconfigure rather than recreate, so the netdev keeps forwarding
+	links := append([]string{n.Name}, p.AddLinks...)
+	links = append(links, p.RemoveLinks...)
+	for _, l := range links {
+		link, err := netlink.LinkByName(l)
+		if err != nil {
+			continue
+		}
+
+		if err := c.DBusNetworkReconfigureLink(ctx, link.Attrs().Index); err != nil {
+			log.Errorf("Failed to reconfigure link='%s': %v", l, err)
+			return err
+		}
+	}
+
+	return web.JSONResponse("configured", w)
+}
@@ -6,12 +6,16 @@ package networkd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/go-ini/ini"
 	"github.com/jaypipes/ghw"
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
@@ -23,7 +27,14 @@ import (
 )
 
 type MatchSection struct {
-	Name string `json:"Name"`
+	Name                string   `json:"Name"`
+	MACAddress          string   `json:"MACAddress"`
+	PermanentMACAddress string   `json:"PermanentMACAddress"`
+	Path                string   `json:"Path"`
+	Driver              string   `json:"Driver"`
+	Type                string   `json:"Type"`
+	Kind                string   `json:"Kind"`
+	Property            []string `json:"Property"`
 }
 
 type LinkSection struct {
@@ -53,23 +64,53 @@ type NetworkSection struct {
 	LinkLocalAddressing string   `json:"LinkLocalAddressing"`
 	MulticastDNS        string   `json:"MulticastDNS"`
 
-	VLAN string `json:"VLAN"`
+	VLAN   string `json:"VLAN"`
+	Bridge string `json:"Bridge"`
 }
 type AddressSection struct {
-	Address string `json:"Address"`
-	Peer    string `json:"Peer"`
-	Label   string `json:"Label"`
-	Scope   string `json:"Scope"`
+	Address                   string `json:"Address"`
+	Peer                      string `json:"Peer"`
+	Broadcast                 string `json:"Broadcast"`
+	Label                     string `json:"Label"`
+	Scope                     string `json:"Scope"`
+	PreferredLifetime         string `json:"PreferredLifetime"`
+	HomeAddress               string `json:"HomeAddress"`
+	DuplicateAddressDetection string `json:"DuplicateAddressDetection"`
+	ManageTemporaryAddress    string `json:"ManageTemporaryAddress"`
+	AddPrefixRoute            string `json:"AddPrefixRoute"`
+	AutoJoin                  string `json:"AutoJoin"`
+	RouteMetric               string `json:"RouteMetric"`
+	NetLabel                  string `json:"NetLabel"`
 }
 
 type RouteSection struct {
-	Gateway         string `json:"Gateway"`
-	GatewayOnlink   string `json:"GatewayOnlink"`
-	Destination     string `json:"Destination"`
-	Source          string `json:"Source"`
-	PreferredSource string `json:"PreferredSource"`
-	Table           string `json:"Table"`
-	Scope           string `json:"Scope"`
+	Gateway                        string   `json:"Gateway"`
+	GatewayOnlink                  string   `json:"GatewayOnlink"`
+	Destination                    string   `json:"Destination"`
+	Source                         string   `json:"Source"`
+	PreferredSource                string   `json:"PreferredSource"`
+	Table                          string   `json:"Table"`
+	Scope                          string   `json:"Scope"`
+	Metric                         string   `json:"Metric"`
+	Protocol                       string   `json:"Protocol"`
+	Type                           string   `json:"Type"`
+	IPv6Preference                 string   `json:"IPv6Preference"`
+	MTUBytes                       string   `json:"MTUBytes"`
+	InitialCongestionWindow        string   `json:"InitialCongestionWindow"`
+	InitialAdvertisedReceiveWindow string   `json:"InitialAdvertisedReceiveWindow"`
+	QuickAck                       string   `json:"QuickAck"`
+	FastOpenNoCookie               string   `json:"FastOpenNoCookie"`
+	MultiPathRoute                 []string `json:"MultiPathRoute"`
+	NextHop                        string   `json:"NextHop"`
+}
+
+type NextHopSection struct {
+	Id        string   `json:"Id"`
+	Gateway   string   `json:"Gateway"`
+	Family    string   `json:"Family"`
+	OnLink    string   `json:"OnLink"`
+	Blackhole string   `json:"Blackhole"`
+	Group     []string `json:"Group"`
 }
 
 type DHCPv4Section struct {
@@ -120,6 +161,22 @@ type DHCPv4ServerSection struct {
 	EmitDNS             string   `json:"EmitDNS"`
 	EmitNTP             string   `json:"EmitNTP"`
 	EmitRouter          string   `json:"EmitRouter"`
+	ServerAddress       string   `json:"ServerAddress"`
+	UplinkInterface     string   `json:"UplinkInterface"`
+	NTP                 []string `json:"NTP"`
+	EmitTimezone        string   `json:"EmitTimezone"`
+	Timezone            string   `json:"Timezone"`
+	BootServerAddress   string   `json:"BootServerAddress"`
+	BootServerName      string   `json:"BootServerName"`
+	BootFilename        string   `json:"BootFilename"`
+	RelayTarget         string   `json:"RelayTarget"`
+	RelayAgentCircuitId string   `json:"RelayAgentCircuitId"`
+	RelayAgentRemoteId  string   `json:"RelayAgentRemoteId"`
+}
+
+type DHCPServerStaticLeaseSection struct {
+	MACAddress string `json:"MACAddress"`
+	Address    string `json:"Address"`
 }
 
 type RoutingPolicyRuleSection struct {
@@ -175,21 +232,44 @@ type SRIOVSection struct {
 	MACAddress              string `json:"MACAddress"`
 }
 
+type BridgePortSection struct {
+	Cost     string `json:"Cost"`
+	Priority string `json:"Priority"`
+	HairPin  string `json:"HairPin"`
+	Isolated string `json:"Isolated"`
+	UseBPDU  string `json:"UseBPDU"`
+	Learning string `json:"Learning"`
+}
+
+type BridgeVLANSection struct {
+	VLAN           string `json:"VLAN"`
+	PVID           string `json:"PVID"`
+	EgressUntagged string `json:"EgressUntagged"`
+}
+
 type Network struct {
-	Link                      string                     `json:"Link"`
-	LinkSection               LinkSection                `json:"LinkSection"`
-	MatchSection              MatchSection               `json:"MatchSection"`
-	NetworkSection            NetworkSection             `json:"NetworkSection"`
-	DHCPv4Section             DHCPv4Section              `json:"DHCPv4Section"`
-	DHCPv4ServerSection       DHCPv4ServerSection        `json:"DHCPv4ServerSection"`
-	DHCPv6Section             DHCPv6Section              `json:"DHCPv6Section"`
-	AddressSections           []AddressSection           `json:"AddressSections"`
-	RouteSections             []RouteSection             `json:"RouteSections"`
-	RoutingPolicyRuleSections []RoutingPolicyRuleSection `json:"RoutingPolicyRuleSections"`
-	IPv6SendRASection         IPv6SendRASection          `json:"IPv6SendRASection"`
-	IPv6PrefixSections        []IPv6PrefixSection        `json:"IPv6PrefixSections"`
-	IPv6RoutePrefixSections   []IPv6RoutePrefixSection   `json:"IPv6RoutePrefixSections"`
-	SRIOVSections             []SRIOVSection             `json:"SRIOVSections"`
+	Link                      string                         `json:"Link"`
+	DropIn                    bool                           `json:"DropIn"`
+	LinkSection               LinkSection                    `json:"LinkSection"`
+	MatchSection              MatchSection                   `json:"MatchSection"`
+	NetworkSection            NetworkSection                 `json:"NetworkSection"`
+	DHCPv4Section             DHCPv4Section                  `json:"DHCPv4Section"`
+	DHCPv4ServerSection       DHCPv4ServerSection            `json:"DHCPv4ServerSection"`
+	DHCPServerStaticLeases    []DHCPServerStaticLeaseSection `json:"DHCPServerStaticLeases"`
+	DHCPv6Section             DHCPv6Section                  `json:"DHCPv6Section"`
+	AddressSections           []AddressSection               `json:"AddressSections"`
+	RouteSections             []RouteSection                 `json:"RouteSections"`
+	NextHopSections           []NextHopSection               `json:"NextHopSections"`
+	RoutingPolicyRuleSections []RoutingPolicyRuleSection     `json:"RoutingPolicyRuleSections"`
+	IPv6SendRASection         IPv6SendRASection              `json:"IPv6SendRASection"`
+	IPv6PrefixSections        []IPv6PrefixSection            `json:"IPv6PrefixSections"`
+	IPv6RoutePrefixSections   []IPv6RoutePrefixSection       `json:"IPv6RoutePrefixSections"`
+	SRIOVSections             []SRIOVSection                 `json:"SRIOVSections"`
+	BridgePortSection         BridgePortSection              `json:"BridgePortSection"`
+	BridgeVLANSections        []BridgeVLANSection            `json:"BridgeVLANSections"`
+	TrafficControl            TrafficControlSections         `json:"TrafficControl"`
+
+	Wait WaitSection `json:"Wait"`
 }
 
 type LinkDescribe struct {
@@ -239,6 +319,103 @@ func decodeNetworkJSONRequest(r *http.Request) (*Network, error) {
 	return &n, nil
 }
 
+func parseNetworkSections(m *configfile.Meta) (*Network, error) {
+	n := Network{}
+
+	var errs []error
+
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Match"), &n.MatchSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Link"), &n.LinkSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Network"), &n.NetworkSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("DHCPv4"), &n.DHCPv4Section))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("DHCPServer"), &n.DHCPv4ServerSection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("DHCPv6"), &n.DHCPv6Section))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("IPv6SendRA"), &n.IPv6SendRASection))
+	errs = append(errs, configfile.MapSectionsTo(m.Sections("Bridge"), &n.BridgePortSection))
+
+	for _, s := range m.Sections("Address") {
+		a := AddressSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &a))
+		n.AddressSections = append(n.AddressSections, a)
+	}
+
+	for _, s := range m.Sections("Route") {
+		rt := RouteSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &rt))
+
+		// "address@interface weight" holds a space, keep each entry whole
+		if s.HasKey("MultiPathRoute") {
+			rt.MultiPathRoute = s.Key("MultiPathRoute").ValueWithShadows()
+		}
+		n.RouteSections = append(n.RouteSections, rt)
+	}
+
+	for _, s := range m.Sections("NextHop") {
+		nh := NextHopSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &nh))
+		n.NextHopSections = append(n.NextHopSections, nh)
+	}
+
+	for _, s := range m.Sections("RoutingPolicyRule") {
+		rtpr := RoutingPolicyRuleSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &rtpr))
+		n.RoutingPolicyRuleSections = append(n.RoutingPolicyRuleSections, rtpr)
+	}
+
+	for _, s := range m.Sections("IPv6Prefix") {
+		p := IPv6PrefixSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &p))
+		n.IPv6PrefixSections = append(n.IPv6PrefixSections, p)
+	}
+
+	for _, s := range m.Sections("IPv6RoutePrefix") {
+		r := IPv6RoutePrefixSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &r))
+		n.IPv6RoutePrefixSections = append(n.IPv6RoutePrefixSections, r)
+	}
+
+	for _, s := range m.Sections("SR-IOV") {
+		sr := SRIOVSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &sr))
+		n.SRIOVSections = append(n.SRIOVSections, sr)
+	}
+
+	for _, s := range m.Sections("BridgeVLAN") {
+		v := BridgeVLANSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &v))
+		n.BridgeVLANSections = append(n.BridgeVLANSections, v)
+	}
+
+	for _, s := range m.Sections("DHCPServerStaticLease") {
+		l := DHCPServerStaticLeaseSection{}
+		errs = append(errs, configfile.MapSectionsTo([]*ini.Section{s}, &l))
+		n.DHCPServerStaticLeases = append(n.DHCPServerStaticLeases, l)
+	}
+
+	tc, err := parseTrafficControlSections(m)
+	n.TrafficControl = tc
+	errs = append(errs, err)
+
+	return &n, errors.Join(errs...)
+}
+
+func AcquireNetworkConfig(ctx context.Context, link string, w http.ResponseWriter) error {
+	m, err := ParseNetworkFileWithDropins(link)
+	if err != nil {
+		log.Errorf("Failed to parse network file for link='%s': %v", link, err)
+		return err
+	}
+
+	n, err := parseNetworkSections(m)
+	if err != nil {
+		log.Errorf("Failed to parse network file for link='%s': %v", link, err)
+		return err
+	}
+	n.Link = link
+
+	return web.JSONResponse(n, w)
+}
+
 func fillOneLink(link netlink.Link) LinkDescribe {
 	l := LinkDescribe{
 		Index: link.Attrs().Index,
@@ -254,6 +431,7 @@ func fillOneLink(link netlink.Link) LinkDescribe {
 	l.OperationalState, _ = ParseLinkOperationalState(link.Attrs().Index)
 	l.SetupState, _ = ParseLinkSetupState(link.Attrs().Index)
 	l.NetworkFile, _ = ParseLinkNetworkFile(link.Attrs().Index)
+	l.LinkFile, _ = ParseLinkUdevLinkFile(link.Attrs().Index)
 
 	c, err := configfile.ParseKeyFromSectionString(path.Join("/sys/class/net", link.Attrs().Name, "device/uevent"), "", "PCI_SLOT_NAME")
 	if err == nil {
@@ -348,7 +526,11 @@ func (n *Network) buildNetworkSection(m *configfile.Meta) error {
 		}
 	}
 
-	if !validator.IsEmpty(n.NetworkSection.MulticastDNS) && validator.IsMulticastDNS(n.NetworkSection.MulticastDNS) {
+	if !validator.IsEmpty(n.NetworkSection.MulticastDNS) {
+		if !validator.IsMulticastDNS(n.NetworkSection.MulticastDNS) {
+			log.Errorf("Failed to parse MulticastDNS='%s'", n.NetworkSection.MulticastDNS)
+			return fmt.Errorf("invalid MulticastDNS='%s'", n.NetworkSection.MulticastDNS)
+		}
 		m.SetKeySectionString("Network", "MulticastDNS", n.NetworkSection.MulticastDNS)
 	}
 
@@ -394,11 +576,23 @@ func (n *Network) buildNetworkSection(m *configfile.Meta) error {
 		m.SetKeySectionString("Network", "NTP", strings.Join(t[:], " "))
 	}
 
-	if !validator.IsEmpty(n.NetworkSection.IPv6AcceptRA) && validator.IsBool(n.NetworkSection.IPv6AcceptRA) {
+	if !validator.IsEmpty(n.NetworkSection.IPv6AcceptRA) {
+		if !validator.IsBool(n.NetworkSection.IPv6AcceptRA) {
+			log.Errorf("Failed to parse IPv6AcceptRA='%s'", n.NetworkSection.IPv6AcceptRA)
+			return fmt.Errorf("invalid IPv6AcceptRA='%s'", n.NetworkSection.IPv6AcceptRA)
+		}
 		m.SetKeySectionString("Network", "IPv6AcceptRA", n.NetworkSection.IPv6AcceptRA)
 	}
 
-	if !validator.IsEmpty(n.NetworkSection.IPv6SendRA) && validator.IsBool(n.NetworkSection.IPv6SendRA) {
+	if !validator.IsEmpty(n.NetworkSection.Bridge) {
+		m.SetKeySectionString("Network", "Bridge", n.NetworkSection.Bridge)
+	}
+
+	if !validator.IsEmpty(n.NetworkSection.IPv6SendRA) {
+		if !validator.IsBool(n.NetworkSection.IPv6SendRA) {
+			log.Errorf("Failed to parse IPv6SendRA='%s'", n.NetworkSection.IPv6SendRA)
+			return fmt.Errorf("invalid IPv6SendRA='%s'", n.NetworkSection.IPv6SendRA)
+		}
 		m.SetKeySectionString("Network", "IPv6SendRA", n.NetworkSection.IPv6SendRA)
 	}
 
@@ -426,6 +620,10 @@ func (n *Network) removeNetworkSection(m *configfile.Meta) error {
 		m.RemoveKeyFromSectionString("Network", "IPv6AcceptRA", n.NetworkSection.IPv6AcceptRA)
 	}
 
+	if !validator.IsEmpty(n.NetworkSection.Bridge) {
+		m.RemoveKeyFromSectionString("Network", "Bridge", n.NetworkSection.Bridge)
+	}
+
 	if !validator.IsEmpty(n.NetworkSection.LinkLocalAddressing) {
 		if validator.IsLinkLocalAddressing(n.NetworkSection.LinkLocalAddressing) {
 			m.RemoveKeyFromSectionString("Network", "LinkLocalAddressing", n.NetworkSection.LinkLocalAddressing)
@@ -490,23 +688,43 @@ func (n *Network) buildLinkSection(m *configfile.Meta) error {
 		}
 	}
 
-	if !validator.IsEmpty(n.LinkSection.ARP) && validator.IsBool(n.LinkSection.ARP) {
+	if !validator.IsEmpty(n.LinkSection.ARP) {
+		if !validator.IsBool(n.LinkSection.ARP) {
+			log.Errorf("Failed to parse ARP='%s'", n.LinkSection.ARP)
+			return fmt.Errorf("invalid ARP='%s'", n.LinkSection.ARP)
+		}
 		m.SetKeySectionString("Link", "ARP", n.LinkSection.ARP)
 	}
 
-	if !validator.IsEmpty(n.LinkSection.Multicast) && validator.IsBool(n.LinkSection.Multicast) {
+	if !validator.IsEmpty(n.LinkSection.Multicast) {
+		if !validator.IsBool(n.LinkSection.Multicast) {
+			log.Errorf("Failed to parse Multicast='%s'", n.LinkSection.Multicast)
+			return fmt.Errorf("invalid Multicast='%s'", n.LinkSection.Multicast)
+		}
 		m.SetKeySectionString("Link", "Multicast", n.LinkSection.Multicast)
 	}
 
-	if !validator.IsEmpty(n.LinkSection.AllMulticast) && validator.IsBool(n.LinkSection.AllMulticast) {
+	if !validator.IsEmpty(n.LinkSection.AllMulticast) {
+		if !validator.IsBool(n.LinkSection.AllMulticast) {
+			log.Errorf("Failed to parse AllMulticast='%s'", n.LinkSection.AllMulticast)
+			return fmt.Errorf("invalid AllMulticast='%s'", n.LinkSection.AllMulticast)
+		}
 		m.SetKeySectionString("Link", "AllMulticast", n.LinkSection.AllMulticast)
 	}
 
-	if !validator.IsEmpty(n.LinkSection.Promiscuous) && validator.IsBool(n.LinkSection.Promiscuous) {
+	if !validator.IsEmpty(n.LinkSection.Promiscuous) {
+		if !validator.IsBool(n.LinkSection.Promiscuous) {
+			log.Errorf("Failed to parse Promiscuous='%s'", n.LinkSection.Promiscuous)
+			return fmt.Errorf("invalid Promiscuous='%s'", n.LinkSection.Promiscuous)
+		}
 		m.SetKeySectionString("Link", "Promiscuous", n.LinkSection.Promiscuous)
 	}
 
-	if !validator.IsEmpty(n.LinkSection.Unmanaged) && validator.IsBool(n.LinkSection.Unmanaged) {
+	if !validator.IsEmpty(n.LinkSection.Unmanaged) {
+		if !validator.IsBool(n.LinkSection.Unmanaged) {
+			log.Errorf("Failed to parse Unmanaged='%s'", n.LinkSection.Unmanaged)
+			return fmt.Errorf("invalid Unmanaged='%s'", n.LinkSection.Unmanaged)
+		}
 		m.SetKeySectionString("Link", "Unmanaged", n.LinkSection.Unmanaged)
 	}
 
@@ -519,7 +737,11 @@ func (n *Network) buildLinkSection(m *configfile.Meta) error {
 		m.SetKeySectionString("Link", "Group", n.LinkSection.Group)
 	}
 
-	if !validator.IsEmpty(n.LinkSection.RequiredForOnline) && validator.IsBool(n.LinkSection.RequiredForOnline) {
+	if !validator.IsEmpty(n.LinkSection.RequiredForOnline) {
+		if !validator.IsBool(n.LinkSection.RequiredForOnline) {
+			log.Errorf("Failed to parse RequiredForOnline='%s'", n.LinkSection.RequiredForOnline)
+			return fmt.Errorf("invalid RequiredForOnline='%s'", n.LinkSection.RequiredForOnline)
+		}
 		m.SetKeySectionString("Link", "RequiredForOnline", n.LinkSection.RequiredForOnline)
 	}
 
@@ -545,8 +767,11 @@ func (n *Network) buildLinkSection(m *configfile.Meta) error {
 }
 
 func (n *Network) buildDHCPv4Section(m *configfile.Meta) error {
-	if !validator.IsEmpty(n.DHCPv4Section.ClientIdentifier) &&
-		validator.IsDHCPv4ClientIdentifier(n.DHCPv4Section.ClientIdentifier) {
+	if !validator.IsEmpty(n.DHCPv4Section.ClientIdentifier) {
+		if !validator.IsDHCPv4ClientIdentifier(n.DHCPv4Section.ClientIdentifier) {
+			log.Errorf("Failed to parse ClientIdentifier='%s'", n.DHCPv4Section.ClientIdentifier)
+			return fmt.Errorf("invalid ClientIdentifier='%s'", n.DHCPv4Section.ClientIdentifier)
+		}
 		m.SetKeySectionString("DHCPv4", "ClientIdentifier", n.DHCPv4Section.ClientIdentifier)
 	}
 
@@ -554,11 +779,19 @@ func (n *Network) buildDHCPv4Section(m *configfile.Meta) error {
 		m.SetKeySectionString("DHCPv4", "VendorClassIdentifier", n.DHCPv4Section.VendorClassIdentifier)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.IAID) && validator.IsUint32(n.DHCPv4Section.IAID) {
+	if !validator.IsEmpty(n.DHCPv4Section.IAID) {
+		if !validator.IsUint32(n.DHCPv4Section.IAID) {
+			log.Errorf("Failed to parse IAID='%s'", n.DHCPv4Section.IAID)
+			return fmt.Errorf("invalid IAID='%s'", n.DHCPv4Section.IAID)
+		}
 		m.SetKeySectionString("DHCPv4", "IAID", n.DHCPv4Section.IAID)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.DUIDType) && validator.IsDHCPDUIDType(n.DHCPv4Section.DUIDType) {
+	if !validator.IsEmpty(n.DHCPv4Section.DUIDType) {
+		if !validator.IsDHCPDUIDType(n.DHCPv4Section.DUIDType) {
+			log.Errorf("Failed to parse DUIDType='%s'", n.DHCPv4Section.DUIDType)
+			return fmt.Errorf("invalid DUIDType='%s'", n.DHCPv4Section.DUIDType)
+		}
 		m.SetKeySectionString("DHCPv4", "DUIDType", n.DHCPv4Section.DUIDType)
 	}
 
@@ -576,43 +809,83 @@ func (n *Network) buildDHCPv4Section(m *configfile.Meta) error {
 		m.SetKeySectionString("DHCPv4", "RequestOptions", strings.Join(n.DHCPv4Section.RequestOptions, " "))
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.SendOption) && validator.IsDHCPv4SendOption(n.DHCPv4Section.SendOption) {
+	if !validator.IsEmpty(n.DHCPv4Section.SendOption) {
+		if !validator.IsDHCPv4SendOption(n.DHCPv4Section.SendOption) {
+			log.Errorf("Failed to parse SendOption='%s'", n.DHCPv4Section.SendOption)
+			return fmt.Errorf("invalid SendOption='%s'", n.DHCPv4Section.SendOption)
+		}
 		m.SetKeySectionString("DHCPv4", "SendOption", n.DHCPv4Section.SendOption)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseDNS) && validator.IsBool(n.DHCPv4Section.UseDNS) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseDNS) {
+		if !validator.IsBool(n.DHCPv4Section.UseDNS) {
+			log.Errorf("Failed to parse UseDNS='%s'", n.DHCPv4Section.UseDNS)
+			return fmt.Errorf("invalid UseDNS='%s'", n.DHCPv4Section.UseDNS)
+		}
 		m.SetKeySectionString("DHCPv4", "UseDNS", n.DHCPv4Section.UseDNS)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseNTP) && validator.IsBool(n.DHCPv4Section.UseNTP) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseNTP) {
+		if !validator.IsBool(n.DHCPv4Section.UseNTP) {
+			log.Errorf("Failed to parse UseNTP='%s'", n.DHCPv4Section.UseNTP)
+			return fmt.Errorf("invalid UseNTP='%s'", n.DHCPv4Section.UseNTP)
+		}
 		m.SetKeySectionString("DHCPv4", "UseNTP", n.DHCPv4Section.UseNTP)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseSIP) && validator.IsBool(n.DHCPv4Section.UseSIP) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseSIP) {
+		if !validator.IsBool(n.DHCPv4Section.UseSIP) {
+			log.Errorf("Failed to parse UseSIP='%s'", n.DHCPv4Section.UseSIP)
+			return fmt.Errorf("invalid UseSIP='%s'", n.DHCPv4Section.UseSIP)
+		}
 		m.SetKeySectionString("DHCPv4", "UseSIP", n.DHCPv4Section.UseSIP)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseMTU) && validator.IsBool(n.DHCPv4Section.UseMTU) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseMTU) {
+		if !validator.IsBool(n.DHCPv4Section.UseMTU) {
+			log.Errorf("Failed to parse UseMTU='%s'", n.DHCPv4Section.UseMTU)
+			return fmt.Errorf("invalid UseMTU='%s'", n.DHCPv4Section.UseMTU)
+		}
 		m.SetKeySectionString("DHCPv4", "UseMTU", n.DHCPv4Section.UseMTU)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseHostname) && validator.IsBool(n.DHCPv4Section.UseHostname) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseHostname) {
+		if !validator.IsBool(n.DHCPv4Section.UseHostname) {
+			log.Errorf("Failed to parse UseHostname='%s'", n.DHCPv4Section.UseHostname)
+			return fmt.Errorf("invalid UseHostname='%s'", n.DHCPv4Section.UseHostname)
+		}
 		m.SetKeySectionString("DHCPv4", "UseHostname", n.DHCPv4Section.UseHostname)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseDomains) && validator.IsBool(n.DHCPv4Section.UseDomains) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseDomains) {
+		if !validator.IsBool(n.DHCPv4Section.UseDomains) {
+			log.Errorf("Failed to parse UseDomains='%s'", n.DHCPv4Section.UseDomains)
+			return fmt.Errorf("invalid UseDomains='%s'", n.DHCPv4Section.UseDomains)
+		}
 		m.SetKeySectionString("DHCPv4", "UseDomains", n.DHCPv4Section.UseDomains)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseRoutes) && validator.IsBool(n.DHCPv4Section.UseRoutes) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseRoutes) {
+		if !validator.IsBool(n.DHCPv4Section.UseRoutes) {
+			log.Errorf("Failed to parse UseRoutes='%s'", n.DHCPv4Section.UseRoutes)
+			return fmt.Errorf("invalid UseRoutes='%s'", n.DHCPv4Section.UseRoutes)
+		}
 		m.SetKeySectionString("DHCPv4", "UseRoutes", n.DHCPv4Section.UseRoutes)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseGateway) && validator.IsBool(n.DHCPv4Section.UseGateway) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseGateway) {
+		if !validator.IsBool(n.DHCPv4Section.UseGateway) {
+			log.Errorf("Failed to parse UseGateway='%s'", n.DHCPv4Section.UseGateway)
+			return fmt.Errorf("invalid UseGateway='%s'", n.DHCPv4Section.UseGateway)
+		}
 		m.SetKeySectionString("DHCPv4", "UseGateway", n.DHCPv4Section.UseGateway)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4Section.UseTimezone) && validator.IsBool(n.DHCPv4Section.UseTimezone) {
+	if !validator.IsEmpty(n.DHCPv4Section.UseTimezone) {
+		if !validator.IsBool(n.DHCPv4Section.UseTimezone) {
+			log.Errorf("Failed to parse UseTimezone='%s'", n.DHCPv4Section.UseTimezone)
+			return fmt.Errorf("invalid UseTimezone='%s'", n.DHCPv4Section.UseTimezone)
+		}
 		m.SetKeySectionString("DHCPv4", "UseTimezone", n.DHCPv4Section.UseTimezone)
 	}
 
@@ -624,11 +897,19 @@ func (n *Network) buildDHCPv6Section(m *configfile.Meta) error {
 		m.SetKeySectionString("DHCPv6", "MUDURL", n.DHCPv6Section.MUDURL)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.IAID) && validator.IsUint32(n.DHCPv6Section.IAID) {
+	if !validator.IsEmpty(n.DHCPv6Section.IAID) {
+		if !validator.IsUint32(n.DHCPv6Section.IAID) {
+			log.Errorf("Failed to parse IAID='%s'", n.DHCPv6Section.IAID)
+			return fmt.Errorf("invalid IAID='%s'", n.DHCPv6Section.IAID)
+		}
 		m.SetKeySectionString("DHCPv6", "IAID", n.DHCPv6Section.IAID)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.DUIDType) && validator.IsDHCPDUIDType(n.DHCPv6Section.DUIDType) {
+	if !validator.IsEmpty(n.DHCPv6Section.DUIDType) {
+		if !validator.IsDHCPDUIDType(n.DHCPv6Section.DUIDType) {
+			log.Errorf("Failed to parse DUIDType='%s'", n.DHCPv6Section.DUIDType)
+			return fmt.Errorf("invalid DUIDType='%s'", n.DHCPv6Section.DUIDType)
+		}
 		m.SetKeySectionString("DHCPv6", "DUIDType", n.DHCPv6Section.DUIDType)
 	}
 
@@ -646,11 +927,19 @@ func (n *Network) buildDHCPv6Section(m *configfile.Meta) error {
 		m.SetKeySectionString("DHCPv6", "RequestOptions", strings.Join(n.DHCPv6Section.RequestOptions, " "))
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.SendOption) && validator.IsUint16(n.DHCPv6Section.SendOption) {
+	if !validator.IsEmpty(n.DHCPv6Section.SendOption) {
+		if !validator.IsUint16(n.DHCPv6Section.SendOption) {
+			log.Errorf("Failed to parse SendOption='%s'", n.DHCPv6Section.SendOption)
+			return fmt.Errorf("invalid SendOption='%s'", n.DHCPv6Section.SendOption)
+		}
 		m.SetKeySectionString("DHCPv6", "SendOption", n.DHCPv6Section.SendOption)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.SendVendorOption) && validator.IsDHCPv6SendVendorOption(n.DHCPv6Section.SendVendorOption) {
+	if !validator.IsEmpty(n.DHCPv6Section.SendVendorOption) {
+		if !validator.IsDHCPv6SendVendorOption(n.DHCPv6Section.SendVendorOption) {
+			log.Errorf("Failed to parse SendVendorOption='%s'", n.DHCPv6Section.SendVendorOption)
+			return fmt.Errorf("invalid SendVendorOption='%s'", n.DHCPv6Section.SendVendorOption)
+		}
 		m.SetKeySectionString("DHCPv6", "SendVendorOption", strings.Replace(n.DHCPv6Section.SendVendorOption, ",", ":", -1))
 	}
 
@@ -662,35 +951,67 @@ func (n *Network) buildDHCPv6Section(m *configfile.Meta) error {
 		m.SetKeySectionString("DHCPv6", "VendorClass", strings.Join(n.DHCPv6Section.VendorClass, " "))
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.PrefixDelegationHint) && validator.IsIP(n.DHCPv6Section.PrefixDelegationHint) {
+	if !validator.IsEmpty(n.DHCPv6Section.PrefixDelegationHint) {
+		if !validator.IsIP(n.DHCPv6Section.PrefixDelegationHint) {
+			log.Errorf("Failed to parse PrefixDelegationHint='%s'", n.DHCPv6Section.PrefixDelegationHint)
+			return fmt.Errorf("invalid PrefixDelegationHint='%s'", n.DHCPv6Section.PrefixDelegationHint)
+		}
 		m.SetKeySectionString("DHCPv6", "PrefixDelegationHint", n.DHCPv6Section.PrefixDelegationHint)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.UseAddress) && validator.IsBool(n.DHCPv6Section.UseAddress) {
+	if !validator.IsEmpty(n.DHCPv6Section.UseAddress) {
+		if !validator.IsBool(n.DHCPv6Section.UseAddress) {
+			log.Errorf("Failed to parse UseAddress='%s'", n.DHCPv6Section.UseAddress)
+			return fmt.Errorf("invalid UseAddress='%s'", n.DHCPv6Section.UseAddress)
+		}
 		m.SetKeySectionString("DHCPv6", "UseAddress", n.DHCPv6Section.UseAddress)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.UseDelegatedPrefix) && validator.IsBool(n.DHCPv6Section.UseDelegatedPrefix) {
+	if !validator.IsEmpty(n.DHCPv6Section.UseDelegatedPrefix) {
+		if !validator.IsBool(n.DHCPv6Section.UseDelegatedPrefix) {
+			log.Errorf("Failed to parse UseDelegatedPrefix='%s'", n.DHCPv6Section.UseDelegatedPrefix)
+			return fmt.Errorf("invalid UseDelegatedPrefix='%s'", n.DHCPv6Section.UseDelegatedPrefix)
+		}
 		m.SetKeySectionString("DHCPv6", "UseDelegatedPrefix", n.DHCPv6Section.UseDelegatedPrefix)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.UseDNS) && validator.IsBool(n.DHCPv6Section.UseDNS) {
+	if !validator.IsEmpty(n.DHCPv6Section.UseDNS) {
+		if !validator.IsBool(n.DHCPv6Section.UseDNS) {
+			log.Errorf("Failed to parse UseDNS='%s'", n.DHCPv6Section.UseDNS)
+			return fmt.Errorf("invalid UseDNS='%s'", n.DHCPv6Section.UseDNS)
+		}
 		m.SetKeySectionString("DHCPv6", "UseDNS", n.DHCPv6Section.UseDNS)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.UseNTP) && validator.IsBool(n.DHCPv6Section.UseNTP) {
+	if !validator.IsEmpty(n.DHCPv6Section.UseNTP) {
+		if !validator.IsBool(n.DHCPv6Section.UseNTP) {
+			log.Errorf("Failed to parse UseNTP='%s'", n.DHCPv6Section.UseNTP)
+			return fmt.Errorf("invalid UseNTP='%s'", n.DHCPv6Section.UseNTP)
+		}
 		m.SetKeySectionString("DHCPv6", "UseNTP", n.DHCPv6Section.UseNTP)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.UseHostname) && validator.IsBool(n.DHCPv6Section.UseHostname) {
+	if !validator.IsEmpty(n.DHCPv6Section.UseHostname) {
+		if !validator.IsBool(n.DHCPv6Section.UseHostname) {
+			log.Errorf("Failed to parse UseHostname='%s'", n.DHCPv6Section.UseHostname)
+			return fmt.Errorf("invalid UseHostname='%s'", n.DHCPv6Section.UseHostname)
+		}
 		m.SetKeySectionString("DHCPv6", "UseHostname", n.DHCPv6Section.UseHostname)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.UseDomains) && validator.IsBool(n.DHCPv6Section.UseDomains) {
+	if !validator.IsEmpty(n.DHCPv6Section.UseDomains) {
+		if !validator.IsBool(n.DHCPv6Section.UseDomains) {
+			log.Errorf("Failed to parse UseDomains='%s'", n.DHCPv6Section.UseDomains)
+			return fmt.Errorf("invalid UseDomains='%s'", n.DHCPv6Section.UseDomains)
+		}
 		m.SetKeySectionString("DHCPv6", "UseDomains", n.DHCPv6Section.UseDomains)
 	}
 
-	if !validator.IsEmpty(n.DHCPv6Section.WithoutRA) && validator.IsDHCPv6WithoutRA(n.DHCPv6Section.WithoutRA) {
+	if !validator.IsEmpty(n.DHCPv6Section.WithoutRA) {
+		if !validator.IsDHCPv6WithoutRA(n.DHCPv6Section.WithoutRA) {
+			log.Errorf("Failed to parse WithoutRA='%s'", n.DHCPv6Section.WithoutRA)
+			return fmt.Errorf("invalid WithoutRA='%s'", n.DHCPv6Section.WithoutRA)
+		}
 		m.SetKeySectionString("DHCPv6", "WithoutRA", n.DHCPv6Section.WithoutRA)
 	}
 
@@ -698,19 +1019,35 @@ func (n *Network) buildDHCPv6Section(m *configfile.Meta) error {
 }
 
 func (n *Network) buildDHCPv4ServerSection(m *configfile.Meta) error {
-	if !validator.IsEmpty(n.DHCPv4ServerSection.PoolOffset) && validator.IsUint32(n.DHCPv4ServerSection.PoolOffset) {
+	if !validator.IsEmpty(n.DHCPv4ServerSection.PoolOffset) {
+		if !validator.IsUint32(n.DHCPv4ServerSection.PoolOffset) {
+			log.Errorf("Failed to parse PoolOffset='%s'", n.DHCPv4ServerSection.PoolOffset)
+			return fmt.Errorf("invalid PoolOffset='%s'", n.DHCPv4ServerSection.PoolOffset)
+		}
 		m.SetKeySectionString("DHCPServer", "PoolOffset", n.DHCPv4ServerSection.PoolOffset)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4ServerSection.PoolSize) && validator.IsUint32(n.DHCPv4ServerSection.PoolSize) {
+	if !validator.IsEmpty(n.DHCPv4ServerSection.PoolSize) {
+		if !validator.IsUint32(n.DHCPv4ServerSection.PoolSize) {
+			log.Errorf("Failed to parse PoolSize='%s'", n.DHCPv4ServerSection.PoolSize)
+			return fmt.Errorf("invalid PoolSize='%s'", n.DHCPv4ServerSection.PoolSize)
+		}
 		m.SetKeySectionString("DHCPServer", "PoolSize", n.DHCPv4ServerSection.PoolSize)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4ServerSection.DefaultLeaseTimeSec) && validator.IsUint32(n.DHCPv4ServerSection.DefaultLeaseTimeSec) {
+	if !validator.IsEmpty(n.DHCPv4ServerSection.DefaultLeaseTimeSec) {
+		if !validator.IsUint32(n.DHCPv4ServerSection.DefaultLeaseTimeSec) {
+			log.Errorf("Failed to parse DefaultLeaseTimeSec='%s'", n.DHCPv4ServerSection.DefaultLeaseTimeSec)
+			return fmt.Errorf("invalid DefaultLeaseTimeSec='%s'", n.DHCPv4ServerSection.DefaultLeaseTimeSec)
+		}
 		m.SetKeySectionString("DHCPServer", "DefaultLeaseTimeSec", n.DHCPv4ServerSection.DefaultLeaseTimeSec)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4ServerSection.MaxLeaseTimeSec) && validator.IsUint32(n.DHCPv4ServerSection.MaxLeaseTimeSec) {
+	if !validator.IsEmpty(n.DHCPv4ServerSection.MaxLeaseTimeSec) {
+		if !validator.IsUint32(n.DHCPv4ServerSection.MaxLeaseTimeSec) {
+			log.Errorf("Failed to parse MaxLeaseTimeSec='%s'", n.DHCPv4ServerSection.MaxLeaseTimeSec)
+			return fmt.Errorf("invalid MaxLeaseTimeSec='%s'", n.DHCPv4ServerSection.MaxLeaseTimeSec)
+		}
 		m.SetKeySectionString("DHCPServer", "MaxLeaseTimeSec", n.DHCPv4ServerSection.MaxLeaseTimeSec)
 	}
 
@@ -724,18 +1061,140 @@ func (n *Network) buildDHCPv4ServerSection(m *configfile.Meta) error {
 		m.SetKeySectionString("DHCPServer", "DNS", strings.Join(n.DHCPv4ServerSection.DNS, " "))
 	}
 
-	if !validator.IsEmpty(n.DHCPv4ServerSection.EmitDNS) && validator.IsBool(n.DHCPv4ServerSection.EmitDNS) {
+	if !validator.IsEmpty(n.DHCPv4ServerSection.EmitDNS) {
+		if !validator.IsBool(n.DHCPv4ServerSection.EmitDNS) {
+			log.Errorf("Failed to parse EmitDNS='%s'", n.DHCPv4ServerSection.EmitDNS)
+			return fmt.Errorf("invalid EmitDNS='%s'", n.DHCPv4ServerSection.EmitDNS)
+		}
 		m.SetKeySectionString("DHCPServer", "EmitDNS", n.DHCPv4ServerSection.EmitDNS)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4ServerSection.EmitNTP) && validator.IsBool(n.DHCPv4ServerSection.EmitNTP) {
+	if !validator.IsEmpty(n.DHCPv4ServerSection.EmitNTP) {
+		if !validator.IsBool(n.DHCPv4ServerSection.EmitNTP) {
+			log.Errorf("Failed to parse EmitNTP='%s'", n.DHCPv4ServerSection.EmitNTP)
+			return fmt.Errorf("invalid EmitNTP='%s'", n.DHCPv4ServerSection.EmitNTP)
+		}
 		m.SetKeySectionString("DHCPServer", "EmitNTP", n.DHCPv4ServerSection.EmitNTP)
 	}
 
-	if !validator.IsEmpty(n.DHCPv4ServerSection.EmitRouter) && validator.IsBool(n.DHCPv4ServerSection.EmitRouter) {
+	if !validator.IsEmpty(n.DHCPv4ServerSection.EmitRouter) {
+		if !validator.IsBool(n.DHCPv4ServerSection.EmitRouter) {
+			log.Errorf("Failed to parse EmitRouter='%s'", n.DHCPv4ServerSection.EmitRouter)
+			return fmt.Errorf("invalid EmitRouter='%s'", n.DHCPv4ServerSection.EmitRouter)
+		}
 		m.SetKeySectionString("DHCPServer", "EmitRouter", n.DHCPv4ServerSection.EmitRouter)
 	}
 
+	if !validator.IsEmpty(n.DHCPv4ServerSection.ServerAddress) {
+		if !validator.IsIP(n.DHCPv4ServerSection.ServerAddress) {
+			log.Errorf("Failed to create DHCPServer. Invalid ServerAddress='%s'", n.DHCPv4ServerSection.ServerAddress)
+			return fmt.Errorf("invalid serveraddress='%s'", n.DHCPv4ServerSection.ServerAddress)
+		}
+		m.SetKeySectionString("DHCPServer", "ServerAddress", n.DHCPv4ServerSection.ServerAddress)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.UplinkInterface) {
+		if n.DHCPv4ServerSection.UplinkInterface != ":none" && n.DHCPv4ServerSection.UplinkInterface != ":auto" && !validator.IsInterfaceName(n.DHCPv4ServerSection.UplinkInterface) {
+			log.Errorf("Failed to create DHCPServer. Invalid UplinkInterface='%s'", n.DHCPv4ServerSection.UplinkInterface)
+			return fmt.Errorf("invalid uplinkinterface='%s'", n.DHCPv4ServerSection.UplinkInterface)
+		}
+		m.SetKeySectionString("DHCPServer", "UplinkInterface", n.DHCPv4ServerSection.UplinkInterface)
+	}
+
+	if !validator.IsArrayEmpty(n.DHCPv4ServerSection.NTP) {
+		for _, d := range n.DHCPv4ServerSection.NTP {
+			if !validator.IsIP(d) {
+				log.Errorf("Failed to create DHCPServer. Invalid NTP='%s'", d)
+				return fmt.Errorf("invalid ntp='%s'", d)
+			}
+		}
+		m.SetKeySectionString("DHCPServer", "NTP", strings.Join(n.DHCPv4ServerSection.NTP, " "))
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.EmitTimezone) {
+		if !validator.IsBool(n.DHCPv4ServerSection.EmitTimezone) {
+			log.Errorf("Failed to parse EmitTimezone='%s'", n.DHCPv4ServerSection.EmitTimezone)
+			return fmt.Errorf("invalid EmitTimezone='%s'", n.DHCPv4ServerSection.EmitTimezone)
+		}
+		m.SetKeySectionString("DHCPServer", "EmitTimezone", n.DHCPv4ServerSection.EmitTimezone)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.Timezone) {
+		m.SetKeySectionString("DHCPServer", "Timezone", n.DHCPv4ServerSection.Timezone)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.BootServerAddress) {
+		if !validator.IsIPv4(n.DHCPv4ServerSection.BootServerAddress) {
+			log.Errorf("Failed to create DHCPServer. Invalid BootServerAddress='%s'", n.DHCPv4ServerSection.BootServerAddress)
+			return fmt.Errorf("invalid bootserveraddress='%s'", n.DHCPv4ServerSection.BootServerAddress)
+		}
+		m.SetKeySectionString("DHCPServer", "BootServerAddress", n.DHCPv4ServerSection.BootServerAddress)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.BootServerName) {
+		m.SetKeySectionString("DHCPServer", "BootServerName", n.DHCPv4ServerSection.BootServerName)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.BootFilename) {
+		m.SetKeySectionString("DHCPServer", "BootFilename", n.DHCPv4ServerSection.BootFilename)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.RelayTarget) {
+		if !validator.IsIPv4(n.DHCPv4ServerSection.RelayTarget) {
+			log.Errorf("Failed to create DHCPServer. Invalid RelayTarget='%s'", n.DHCPv4ServerSection.RelayTarget)
+			return fmt.Errorf("invalid relaytarget='%s'", n.DHCPv4ServerSection.RelayTarget)
+		}
+		m.SetKeySectionString("DHCPServer", "RelayTarget", n.DHCPv4ServerSection.RelayTarget)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.RelayAgentCircuitId) {
+		m.SetKeySectionString("DHCPServer", "RelayAgentCircuitId", n.DHCPv4ServerSection.RelayAgentCircuitId)
+	}
+
+	if !validator.IsEmpty(n.DHCPv4ServerSection.RelayAgentRemoteId) {
+		m.SetKeySectionString("DHCPServer", "RelayAgentRemoteId", n.DHCPv4ServerSection.RelayAgentRemoteId)
+	}
+
+	return nil
+}
+
+func (n *Network) buildDHCPServerStaticLeaseSection(m *configfile.Meta) error {
+	for _, l := range n.DHCPServerStaticLeases {
+		if validator.IsNotMAC(l.MACAddress) {
+			log.Errorf("Failed to parse static lease MACAddress='%s'", l.MACAddress)
+			return fmt.Errorf("invalid macaddress='%s'", l.MACAddress)
+		}
+		if !validator.IsValidIP(l.Address) {
+			log.Errorf("Failed to parse static lease Address='%s'", l.Address)
+			return fmt.Errorf("invalid address='%s'", l.Address)
+		}
+
+		// A reservation for the same MACAddress or Address is replaced
+		var matches []int
+		for i, s := range m.Sections("DHCPServerStaticLease") {
+			if strings.EqualFold(s.Key("MACAddress").String(), l.MACAddress) || net.ParseIP(s.Key("Address").String()).Equal(net.ParseIP(l.Address)) {
+				matches = append(matches, i)
+			}
+		}
+
+		if len(matches) == 0 {
+			if err := m.NewSection("DHCPServerStaticLease"); err != nil {
+				return err
+			}
+
+			m.SetKeyToNewSectionString("MACAddress", l.MACAddress)
+			m.SetKeyToNewSectionString("Address", l.Address)
+			continue
+		}
+
+		s := m.Sections("DHCPServerStaticLease")[matches[0]]
+		s.Key("MACAddress").SetValue(l.MACAddress)
+		s.Key("Address").SetValue(l.Address)
+		for i := len(matches) - 1; i > 0; i-- {
+			m.Cfg.DeleteSectionWithIndex("DHCPServerStaticLease", matches[i])
+		}
+	}
+
 	return nil
 }
 
@@ -767,9 +1226,68 @@ func (n *Network) buildAddressSection(m *configfile.Meta) error {
 			m.SetKeyToNewSectionString("Label", a.Label)
 		}
 
-		if !validator.IsEmpty(a.Scope) && validator.IsScope(a.Scope) {
+		if !validator.IsEmpty(a.Scope) {
+			if !validator.IsScope(a.Scope) {
+				log.Errorf("Failed to parse Scope='%s'", a.Scope)
+				return fmt.Errorf("invalid Scope='%s'", a.Scope)
+			}
 			m.SetKeyToNewSectionString("Scope", a.Scope)
 		}
+
+		if !validator.IsEmpty(a.Broadcast) {
+			if !validator.IsBool(a.Broadcast) && !validator.IsValidIP(a.Broadcast) {
+				log.Errorf("Failed to parse Broadcast='%s'", a.Broadcast)
+				return fmt.Errorf("invalid Broadcast='%s'", a.Broadcast)
+			}
+			m.SetKeyToNewSectionString("Broadcast", a.Broadcast)
+		}
+
+		if !validator.IsEmpty(a.PreferredLifetime) {
+			if !validator.IsAddressPreferredLifetime(a.PreferredLifetime) {
+				log.Errorf("Failed to parse PreferredLifetime='%s'", a.PreferredLifetime)
+				return fmt.Errorf("invalid PreferredLifetime='%s'", a.PreferredLifetime)
+			}
+			m.SetKeyToNewSectionString("PreferredLifetime", a.PreferredLifetime)
+		}
+
+		if !validator.IsEmpty(a.DuplicateAddressDetection) {
+			if !validator.IsAddressDuplicateAddressDetection(a.DuplicateAddressDetection) {
+				log.Errorf("Failed to parse DuplicateAddressDetection='%s'", a.DuplicateAddressDetection)
+				return fmt.Errorf("invalid DuplicateAddressDetection='%s'", a.DuplicateAddressDetection)
+			}
+			m.SetKeyToNewSectionString("DuplicateAddressDetection", a.DuplicateAddressDetection)
+		}
+
+		for _, kv := range []struct {
+			key   string
+			value string
+		}{
+			{"HomeAddress", a.HomeAddress},
+			{"ManageTemporaryAddress", a.ManageTemporaryAddress},
+			{"AddPrefixRoute", a.AddPrefixRoute},
+			{"AutoJoin", a.AutoJoin},
+		} {
+			if validator.IsEmpty(kv.value) {
+				continue
+			}
+			if !validator.IsBool(kv.value) {
+				log.Errorf("Failed to parse %s='%s'", kv.key, kv.value)
+				return fmt.Errorf("invalid %s='%s'", kv.key, kv.value)
+			}
+			m.SetKeyToNewSectionString(kv.key, validator.BoolToString(kv.value))
+		}
+
+		if !validator.IsEmpty(a.RouteMetric) {
+			if !validator.IsUint32(a.RouteMetric) {
+				log.Errorf("Failed to parse RouteMetric='%s'", a.RouteMetric)
+				return fmt.Errorf("invalid RouteMetric='%s'", a.RouteMetric)
+			}
+			m.SetKeyToNewSectionString("RouteMetric", a.RouteMetric)
+		}
+
+		if !validator.IsEmpty(a.NetLabel) {
+			m.SetKeyToNewSectionString("NetLabel", a.NetLabel)
+		}
 	}
 
 	return nil
@@ -829,9 +1347,168 @@ func (n *Network) buildRouteSection(m *configfile.Meta) error {
 			m.SetKeyToNewSectionString("Table", rt.Table)
 		}
 
-		if !validator.IsEmpty(rt.Scope) && validator.IsScope(rt.Scope) {
+		if !validator.IsEmpty(rt.Scope) {
+			if !validator.IsScope(rt.Scope) {
+				log.Errorf("Failed to parse Scope='%s'", rt.Scope)
+				return fmt.Errorf("invalid Scope='%s'", rt.Scope)
+			}
 			m.SetKeyToNewSectionString("Scope", rt.Scope)
 		}
+
+		if !validator.IsEmpty(rt.Metric) {
+			if !validator.IsUint32(rt.Metric) {
+				log.Errorf("Failed to parse Metric='%s'", rt.Metric)
+				return fmt.Errorf("invalid Metric='%s'", rt.Metric)
+			}
+			m.SetKeyToNewSectionString("Metric", rt.Metric)
+		}
+
+		if !validator.IsEmpty(rt.Protocol) {
+			if !validator.IsRouteProtocol(rt.Protocol) {
+				log.Errorf("Failed to parse Protocol='%s'", rt.Protocol)
+				return fmt.Errorf("invalid Protocol='%s'", rt.Protocol)
+			}
+			m.SetKeyToNewSectionString("Protocol", rt.Protocol)
+		}
+
+		if !validator.IsEmpty(rt.Type) {
+			if !validator.IsRouteType(rt.Type) {
+				log.Errorf("Failed to parse Type='%s'", rt.Type)
+				return fmt.Errorf("invalid Type='%s'", rt.Type)
+			}
+			m.SetKeyToNewSectionString("Type", rt.Type)
+		}
+
+		if !validator.IsEmpty(rt.IPv6Preference) {
+			if !validator.IsRouteIPv6Preference(rt.IPv6Preference) {
+				log.Errorf("Failed to parse IPv6Preference='%s'", rt.IPv6Preference)
+				return fmt.Errorf("invalid IPv6Preference='%s'", rt.IPv6Preference)
+			}
+			m.SetKeyToNewSectionString("IPv6Preference", rt.IPv6Preference)
+		}
+
+		if !validator.IsEmpty(rt.MTUBytes) {
+			if !validator.IsLinkMtu(rt.MTUBytes) {
+				log.Errorf("Failed to parse MTUBytes='%s'", rt.MTUBytes)
+				return fmt.Errorf("invalid MTUBytes='%s'", rt.MTUBytes)
+			}
+			m.SetKeyToNewSectionString("MTUBytes", rt.MTUBytes)
+		}
+
+		for _, kv := range []struct {
+			key   string
+			value string
+		}{
+			{"InitialCongestionWindow", rt.InitialCongestionWindow},
+			{"InitialAdvertisedReceiveWindow", rt.InitialAdvertisedReceiveWindow},
+		} {
+			if validator.IsEmpty(kv.value) {
+				continue
+			}
+			if !validator.IsUint32(kv.value) {
+				log.Errorf("Failed to parse %s='%s'", kv.key, kv.value)
+				return fmt.Errorf("invalid %s='%s'", kv.key, kv.value)
+			}
+			m.SetKeyToNewSectionString(kv.key, kv.value)
+		}
+
+		for _, kv := range []struct {
+			key   string
+			value string
+		}{
+			{"QuickAck", rt.QuickAck},
+			{"FastOpenNoCookie", rt.FastOpenNoCookie},
+		} {
+			if validator.IsEmpty(kv.value) {
+				continue
+			}
+			if !validator.IsBool(kv.value) {
+				log.Errorf("Failed to parse %s='%s'", kv.key, kv.value)
+				return fmt.Errorf("invalid %s='%s'", kv.key, kv.value)
+			}
+			m.SetKeyToNewSectionString(kv.key, validator.BoolToString(kv.value))
+		}
+
+		for _, mp := range rt.MultiPathRoute {
+			if !validator.IsRouteMultiPath(mp) {
+				log.Errorf("Failed to parse MultiPathRoute='%s'", mp)
+				return fmt.Errorf("invalid MultiPathRoute='%s'", mp)
+			}
+			m.SetKeyToNewSectionString("MultiPathRoute", mp)
+		}
+
+		if !validator.IsEmpty(rt.NextHop) {
+			if !validator.IsNextHopId(rt.NextHop) {
+				log.Errorf("Failed to parse NextHop='%s'", rt.NextHop)
+				return fmt.Errorf("invalid NextHop='%s'", rt.NextHop)
+			}
+			m.SetKeyToNewSectionString("NextHop", rt.NextHop)
+		}
+	}
+
+	return nil
+}
+
+func (n *Network) buildNextHopSection(m *configfile.Meta) error {
+	for _, nh := range n.NextHopSections {
+		if !validator.IsNextHopId(nh.Id) {
+			log.Errorf("Failed to parse NextHop Id='%s'", nh.Id)
+			return fmt.Errorf("invalid Id='%s'", nh.Id)
+		}
+
+		if (!validator.IsEmpty(nh.Gateway) || !validator.IsArrayEmpty(nh.Group)) && validator.BoolToString(nh.Blackhole) == "yes" {
+			log.Errorf("Failed to configure NextHop Id='%s': Blackhole can not be combined with Gateway or Group", nh.Id)
+			return fmt.Errorf("nexthop id='%s' blackhole can not have a gateway or group", nh.Id)
+		}
+
+		if err := m.NewSection("NextHop"); err != nil {
+			return err
+		}
+
+		m.SetKeyToNewSectionString("Id", nh.Id)
+
+		if !validator.IsEmpty(nh.Gateway) {
+			if !validator.IsValidIP(nh.Gateway) {
+				log.Errorf("Failed to parse NextHop Gateway='%s'", nh.Gateway)
+				return fmt.Errorf("invalid Gateway='%s'", nh.Gateway)
+			}
+			m.SetKeyToNewSectionString("Gateway", nh.Gateway)
+		}
+
+		if !validator.IsEmpty(nh.Family) {
+			if nh.Family != "ipv4" && nh.Family != "ipv6" {
+				log.Errorf("Failed to parse NextHop Family='%s'", nh.Family)
+				return fmt.Errorf("invalid Family='%s'", nh.Family)
+			}
+			m.SetKeyToNewSectionString("Family", nh.Family)
+		}
+
+		for _, kv := range []struct {
+			key   string
+			value string
+		}{
+			{"OnLink", nh.OnLink},
+			{"Blackhole", nh.Blackhole},
+		} {
+			if validator.IsEmpty(kv.value) {
+				continue
+			}
+			if !validator.IsBool(kv.value) {
+				log.Errorf("Failed to parse NextHop %s='%s'", kv.key, kv.value)
+				return fmt.Errorf("invalid %s='%s'", kv.key, kv.value)
+			}
+			m.SetKeyToNewSectionString(kv.key, validator.BoolToString(kv.value))
+		}
+
+		if !validator.IsArrayEmpty(nh.Group) {
+			for _, g := range nh.Group {
+				if !validator.IsNextHopGroup(g) {
+					log.Errorf("Failed to parse NextHop Group='%s'", g)
+					return fmt.Errorf("invalid Group='%s'", g)
+				}
+			}
+			m.SetKeyToNewSectionString("Group", strings.Join(nh.Group, " "))
+		}
 	}
 
 	return nil
@@ -985,7 +1662,11 @@ func (n *Network) buildIPv6SendRASection(m *configfile.Meta) error {
 		m.SetKeySectionString("IPv6SendRA", "RouterPreference", n.IPv6SendRASection.RouterPreference)
 	}
 
-	if !validator.IsEmpty(n.IPv6SendRASection.EmitDNS) && validator.IsBool(n.IPv6SendRASection.EmitDNS) {
+	if !validator.IsEmpty(n.IPv6SendRASection.EmitDNS) {
+		if !validator.IsBool(n.IPv6SendRASection.EmitDNS) {
+			log.Errorf("Failed to parse EmitDNS='%s'", n.IPv6SendRASection.EmitDNS)
+			return fmt.Errorf("invalid EmitDNS='%s'", n.IPv6SendRASection.EmitDNS)
+		}
 		m.SetKeySectionString("IPv6SendRA", "EmitDNS", n.IPv6SendRASection.EmitDNS)
 	}
 
@@ -999,7 +1680,11 @@ func (n *Network) buildIPv6SendRASection(m *configfile.Meta) error {
 		m.SetKeySectionString("IPv6SendRA", "DNS", strings.Join(n.IPv6SendRASection.DNS, " "))
 	}
 
-	if !validator.IsEmpty(n.IPv6SendRASection.EmitDomains) && validator.IsBool(n.IPv6SendRASection.EmitDomains) {
+	if !validator.IsEmpty(n.IPv6SendRASection.EmitDomains) {
+		if !validator.IsBool(n.IPv6SendRASection.EmitDomains) {
+			log.Errorf("Failed to parse EmitDomains='%s'", n.IPv6SendRASection.EmitDomains)
+			return fmt.Errorf("invalid EmitDomains='%s'", n.IPv6SendRASection.EmitDomains)
+		}
 		m.SetKeySectionString("IPv6SendRA", "EmitDomains", n.IPv6SendRASection.EmitDomains)
 	}
 
@@ -1048,7 +1733,11 @@ func (n *Network) buildIPv6PrefixSection(m *configfile.Meta) error {
 			m.SetKeyToNewSectionString("ValidLifetimeSec", p.ValidLifetimeSec)
 		}
 
-		if !validator.IsEmpty(p.Assign) && validator.IsBool(p.Assign) {
+		if !validator.IsEmpty(p.Assign) {
+			if !validator.IsBool(p.Assign) {
+				log.Errorf("Failed to parse Assign='%s'", p.Assign)
+				return fmt.Errorf("invalid Assign='%s'", p.Assign)
+			}
 			m.SetKeyToNewSectionString("Assign", p.Assign)
 		}
 	}
@@ -1124,15 +1813,27 @@ func (n *Network) buildSRIOVSection(m *configfile.Meta) error {
 			m.SetKeyToNewSectionString("VLANProtocol", s.VLANProtocol)
 		}
 
-		if !validator.IsEmpty(s.MACSpoofCheck) && validator.IsBool(s.MACSpoofCheck) {
+		if !validator.IsEmpty(s.MACSpoofCheck) {
+			if !validator.IsBool(s.MACSpoofCheck) {
+				log.Errorf("Failed to parse MACSpoofCheck='%s'", s.MACSpoofCheck)
+				return fmt.Errorf("invalid MACSpoofCheck='%s'", s.MACSpoofCheck)
+			}
 			m.SetKeyToNewSectionString("MACSpoofCheck", s.MACSpoofCheck)
 		}
 
-		if !validator.IsEmpty(s.QueryReceiveSideScaling) && validator.IsBool(s.QueryReceiveSideScaling) {
+		if !validator.IsEmpty(s.QueryReceiveSideScaling) {
+			if !validator.IsBool(s.QueryReceiveSideScaling) {
+				log.Errorf("Failed to parse QueryReceiveSideScaling='%s'", s.QueryReceiveSideScaling)
+				return fmt.Errorf("invalid QueryReceiveSideScaling='%s'", s.QueryReceiveSideScaling)
+			}
 			m.SetKeyToNewSectionString("QueryReceiveSideScaling", s.QueryReceiveSideScaling)
 		}
 
-		if !validator.IsEmpty(s.Trust) && validator.IsBool(s.Trust) {
+		if !validator.IsEmpty(s.Trust) {
+			if !validator.IsBool(s.Trust) {
+				log.Errorf("Failed to parse Trust='%s'", s.Trust)
+				return fmt.Errorf("invalid Trust='%s'", s.Trust)
+			}
 			m.SetKeyToNewSectionString("Trust", s.Trust)
 		}
 
@@ -1157,6 +1858,77 @@ func (n *Network) buildSRIOVSection(m *configfile.Meta) error {
 	return nil
 }
 
+func (n *Network) buildBridgePortSection(m *configfile.Meta) error {
+	if !validator.IsEmpty(n.BridgePortSection.Cost) {
+		if !validator.IsBridgePortCost(n.BridgePortSection.Cost) {
+			log.Errorf("Failed to parse Cost='%s'", n.BridgePortSection.Cost)
+			return fmt.Errorf("invalid cost='%s'", n.BridgePortSection.Cost)
+		}
+		m.SetKeySectionString("Bridge", "Cost", n.BridgePortSection.Cost)
+	}
+
+	if !validator.IsEmpty(n.BridgePortSection.Priority) {
+		if !validator.IsBridgePortPriority(n.BridgePortSection.Priority) {
+			log.Errorf("Failed to parse Priority='%s'", n.BridgePortSection.Priority)
+			return fmt.Errorf("invalid priority='%s'", n.BridgePortSection.Priority)
+		}
+		m.SetKeySectionString("Bridge", "Priority", n.BridgePortSection.Priority)
+	}
+
+	for _, kv := range []struct{ key, value string }{
+		{"HairPin", n.BridgePortSection.HairPin},
+		{"Isolated", n.BridgePortSection.Isolated},
+		{"UseBPDU", n.BridgePortSection.UseBPDU},
+		{"Learning", n.BridgePortSection.Learning},
+	} {
+		if validator.IsEmpty(kv.value) {
+			continue
+		}
+
+		if !validator.IsBool(kv.value) {
+			log.Errorf("Failed to parse %s='%s'", kv.key, kv.value)
+			return fmt.Errorf("invalid %s='%s'", strings.ToLower(kv.key), kv.value)
+		}
+		m.SetKeySectionString("Bridge", kv.key, validator.BoolToString(kv.value))
+	}
+
+	return nil
+}
+
+func (n *Network) buildBridgeVLANSection(m *configfile.Meta) error {
+	for _, v := range n.BridgeVLANSections {
+		if err := m.NewSection("BridgeVLAN"); err != nil {
+			return err
+		}
+
+		if !validator.IsEmpty(v.VLAN) {
+			if !validator.IsVLANIdRange(v.VLAN) {
+				log.Errorf("Failed to parse VLAN='%s'", v.VLAN)
+				return fmt.Errorf("invalid vlan='%s'", v.VLAN)
+			}
+			m.SetKeyToNewSectionString("VLAN", v.VLAN)
+		}
+
+		if !validator.IsEmpty(v.PVID) {
+			if !validator.IsVLANId(v.PVID) {
+				log.Errorf("Failed to parse PVID='%s'", v.PVID)
+				return fmt.Errorf("invalid pvid='%s'", v.PVID)
+			}
+			m.SetKeyToNewSectionString("PVID", v.PVID)
+		}
+
+		if !validator.IsEmpty(v.EgressUntagged) {
+			if !validator.IsVLANIdRange(v.EgressUntagged) {
+				log.Errorf("Failed to parse EgressUntagged='%s'", v.EgressUntagged)
+				return fmt.Errorf("invalid egressuntagged='%s'", v.EgressUntagged)
+			}
+			m.SetKeyToNewSectionString("EgressUntagged", v.EgressUntagged)
+		}
+	}
+
+	return nil
+}
+
 func (n *Network) removeAddressSection(m *configfile.Meta) error {
 	for _, a := range n.AddressSections {
 		if !validator.IsEmpty(a.Address) {
@@ -1190,6 +1962,26 @@ func (n *Network) removeRouteSection(m *configfile.Meta) error {
 	return nil
 }
 
+func (n *Network) removeNextHopSection(m *configfile.Meta) error {
+	for _, nh := range n.NextHopSections {
+		found := false
+		for i, s := range m.Sections("NextHop") {
+			if s.Key("Id").String() == nh.Id {
+				m.Cfg.DeleteSectionWithIndex("NextHop", i)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			log.Errorf("Failed to remove NextHop Id='%s': not found", nh.Id)
+			return fmt.Errorf("nexthop id='%s' not found", nh.Id)
+		}
+	}
+
+	return nil
+}
+
 func (n *Network) removeRoutingPolicyRuleSection(m *configfile.Meta) error {
 	for _, rtpr := range n.RoutingPolicyRuleSections {
 		if !validator.IsEmpty(rtpr.TypeOfService) {
@@ -1319,6 +2111,27 @@ func (n *Network) removeDHCPv4ServerSection(m *configfile.Meta) error {
 			log.Errorf("Failed to remove DHCPServer: %v", err)
 			return err
 		}
+		m.Cfg.DeleteSection("DHCPServerStaticLease")
+	}
+
+	return nil
+}
+
+func (n *Network) removeDHCPServerStaticLeaseSection(m *configfile.Meta) error {
+	for _, l := range n.DHCPServerStaticLeases {
+		found := false
+		for i, s := range m.Sections("DHCPServerStaticLease") {
+			if strings.EqualFold(s.Key("MACAddress").String(), l.MACAddress) {
+				m.Cfg.DeleteSectionWithIndex("DHCPServerStaticLease", i)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			log.Errorf("Failed to remove static lease MACAddress='%s': not found", l.MACAddress)
+			return fmt.Errorf("static lease macaddress='%s' not found", l.MACAddress)
+		}
 	}
 
 	return nil
@@ -1357,13 +2170,7 @@ func (n *Network) removeIPv6RoutePrefixSection(m *configfile.Meta) error {
 	return nil
 }
 
-func (n *Network) ConfigureNetwork(ctx context.Context, w http.ResponseWriter) error {
-	m, err := CreateOrParseNetworkFile(n.Link)
-	if err != nil {
-		log.Errorf("Failed to parse network file for link='%s': %v", n.Link, err)
-		return err
-	}
-
+func (n *Network) buildSections(m *configfile.Meta) error {
 	if err := n.buildNetworkSection(m); err != nil {
 		return err
 	}
@@ -1376,9 +2183,6 @@ func (n *Network) ConfigureNetwork(ctx context.Context, w http.ResponseWriter) e
 	if err := n.buildDHCPv4ServerSection(m); err != nil {
 		return err
 	}
-	if err := n.buildDHCPv4Section(m); err != nil {
-		return err
-	}
 	if err := n.buildDHCPv6Section(m); err != nil {
 		return err
 	}
@@ -1388,6 +2192,9 @@ func (n *Network) ConfigureNetwork(ctx context.Context, w http.ResponseWriter) e
 	if err := n.buildRouteSection(m); err != nil {
 		return err
 	}
+	if err := n.buildNextHopSection(m); err != nil {
+		return err
+	}
 	if err := n.buildRoutingPolicyRuleSection(m); err != nil {
 		return err
 	}
@@ -1403,6 +2210,167 @@ func (n *Network) ConfigureNetwork(ctx context.Context, w http.ResponseWriter) e
 	if err := n.buildSRIOVSection(m); err != nil {
 		return err
 	}
+	if err := n.buildBridgePortSection(m); err != nil {
+		return err
+	}
+	if err := n.buildBridgeVLANSection(m); err != nil {
+		return err
+	}
+	if err := n.buildDHCPServerStaticLeaseSection(m); err != nil {
+		return err
+	}
+	if err := n.buildTrafficControlSections(m); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// networkComments holds the comments of the sections and keys dropped by
+// clearNetworkSections, keyed by section name. Sections of the same name
+// share them.
+type networkComments struct {
+	sections map[string]string
+	keys     map[string]map[string]string
+}
+
+// clearNetworkSections drops every section except [Match] and returns the
+// comments of the removed ones.
+func clearNetworkSections(m *configfile.Meta) *networkComments {
+	c := networkComments{
+		sections: make(map[string]string),
+		keys:     make(map[string]map[string]string),
+	}
+
+	for _, s := range m.Cfg.Sections() {
+		if s.Name() == ini.DefaultSection || s.Name() == "Match" {
+			continue
+		}
+
+		if _, ok := c.sections[s.Name()]; !ok {
+			c.sections[s.Name()] = s.Comment
+		}
+
+		if c.keys[s.Name()] == nil {
+			c.keys[s.Name()] = make(map[string]string)
+		}
+		for _, k := range s.Keys() {
+			if _, ok := c.keys[s.Name()][k.Name()]; !ok && k.Comment != "" {
+				c.keys[s.Name()][k.Name()] = k.Comment
+			}
+		}
+
+		m.Cfg.DeleteSection(s.Name())
+	}
+
+	return &c
+}
+
+// restore puts the comments back on the sections and keys which were
+// written again
+func (c *networkComments) restore(m *configfile.Meta) {
+	for _, s := range m.Cfg.Sections() {
+		if comment, ok := c.sections[s.Name()]; ok && s.Comment == "" {
+			s.Comment = comment
+		}
+
+		for _, k := range s.Keys() {
+			if comment, ok := c.keys[s.Name()][k.Name()]; ok && k.Comment == "" {
+				k.Comment = comment
+			}
+		}
+	}
+}
+
+func (n *Network) removeBridgeVLANSection(m *configfile.Meta) error {
+	for _, v := range n.BridgeVLANSections {
+		if validator.IsEmpty(v.VLAN) {
+			continue
+		}
+
+		found := false
+		for i, s := range m.Sections("BridgeVLAN") {
+			if s.Key("VLAN").String() == v.VLAN {
+				m.Cfg.DeleteSectionWithIndex("BridgeVLAN", i)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			log.Errorf("Failed to remove VLAN='%s': not found", v.VLAN)
+			return fmt.Errorf("vlan='%s' not found", v.VLAN)
+		}
+	}
+
+	return nil
+}
+
+func (n *Network) createOrParseNetworkFile() (*configfile.Meta, error) {
+	if n.DropIn {
+		if !n.MatchSection.isEmpty() {
+			return nil, errors.New("match section can not be set in a drop-in")
+		}
+
+		return CreateOrParseNetworkDropinFile(n.Link)
+	}
+
+	return CreateOrParseNetworkFile(n.Link)
+}
+
+// isNetworkFileShared tells whether networkd applies the file to links other
+// than link as well
+func isNetworkFileShared(link string, file string) bool {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return true
+	}
+
+	for _, l := range links {
+		if l.Attrs().Name == link {
+			continue
+		}
+
+		if f, err := ParseLinkNetworkFile(l.Attrs().Index); err == nil && f == file {
+			return true
+		}
+	}
+
+	return false
+}
+
+// buildMatchSection replaces [Match] of the .network file. Only files in
+// /etc/systemd/network applied to this link alone are edited, rewriting a
+// vendor file or one shared with other links would change their matching too.
+func (n *Network) buildMatchSection(m *configfile.Meta) error {
+	if n.MatchSection.isEmpty() {
+		return nil
+	}
+
+	if path.Dir(m.Path) != "/etc/systemd/network" {
+		log.Errorf("Failed to update match section of link='%s'. File='%s' is not in /etc/systemd/network", n.Link, m.Path)
+		return fmt.Errorf("network file='%s' is not in /etc/systemd/network", m.Path)
+	}
+
+	if isNetworkFileShared(n.Link, m.Path) {
+		log.Errorf("Failed to update match section of link='%s'. File='%s' applies to other links", n.Link, m.Path)
+		return fmt.Errorf("network file='%s' applies to other links", m.Path)
+	}
+
+	return n.MatchSection.BuildMatchSection(m)
+}
+
+// saveAndApply writes the file and reloads networkd. With Wait set the reply
+// is held back until the link reached the requested state.
+func (n *Network) saveAndApply(ctx context.Context, m *configfile.Meta, w http.ResponseWriter) error {
+	var snapshot *networkFileSnapshot
+	if !n.Wait.isEmpty() {
+		if err := n.Wait.validate(); err != nil {
+			log.Errorf("Failed to configure link='%s': %v", n.Link, err)
+			return err
+		}
+		snapshot = takeNetworkFileSnapshot(m)
+	}
 
 	if err := m.Save(); err != nil {
 		log.Errorf("Failed to update config file='%s': %v", m.Path, err)
@@ -1412,19 +2380,60 @@ func (n *Network) ConfigureNetwork(ctx context.Context, w http.ResponseWriter) e
 	c, err := NewSDConnection()
 	if err != nil {
 		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		n.revertOnError(ctx, nil, snapshot)
 		return err
 	}
 	defer c.Close()
 
+	since := time.Now()
 	if err := c.DBusNetworkReload(ctx); err != nil {
+		n.revertOnError(ctx, c, snapshot)
+		return err
+	}
+
+	if n.Wait.isEmpty() {
+		return web.JSONResponse("configured", w)
+	}
+
+	// A reload skips links whose file did not change, make sure the state
+	// waited for is the one of this configuration
+	if l, err := netlink.LinkByName(n.Link); err == nil {
+		if err := c.DBusNetworkReconfigureLink(ctx, l.Attrs().Index); err != nil {
+			log.Errorf("Failed to reconfigure link='%s': %v", n.Link, err)
+			n.revertOnError(ctx, c, snapshot)
+			return err
+		}
+	}
+
+	// The client going away must not leave a bad configuration applied
+	s, err := n.waitAndRevert(context.WithoutCancel(ctx), c, snapshot, since)
+	if err != nil {
+		n.revertOnError(ctx, c, snapshot)
+		return err
+	}
+
+	return web.JSONResponse(s, w)
+}
+
+func (n *Network) ConfigureNetwork(ctx context.Context, w http.ResponseWriter) error {
+	m, err := n.createOrParseNetworkFile()
+	if err != nil {
+		log.Errorf("Failed to parse network file for link='%s': %v", n.Link, err)
 		return err
 	}
 
-	return web.JSONResponse("configured", w)
+	if err := n.buildMatchSection(m); err != nil {
+		return err
+	}
+	if err := n.buildSections(m); err != nil {
+		return err
+	}
+
+	return n.saveAndApply(ctx, m, w)
 }
 
 func (n *Network) RemoveNetwork(ctx context.Context, w http.ResponseWriter) error {
-	m, err := CreateOrParseNetworkFile(n.Link)
+	m, err := n.createOrParseNetworkFile()
 	if err != nil {
 		log.Errorf("Failed to parse network file for link='%s': %v", n.Link, err)
 		return err
@@ -1445,6 +2454,11 @@ func (n *Network) RemoveNetwork(ctx context.Context, w http.ResponseWriter) erro
 		return err
 	}
 
+	if err := n.removeNextHopSection(m); err != nil {
+		log.Errorf("Failed to remove NextHop section: %v", err)
+		return err
+	}
+
 	if err := n.removeRoutingPolicyRuleSection(m); err != nil {
 		log.Errorf("Failed to remove routing Policy rule section: %v", err)
 		return err
@@ -1470,6 +2484,21 @@ func (n *Network) RemoveNetwork(ctx context.Context, w http.ResponseWriter) erro
 		return err
 	}
 
+	if err := n.removeBridgeVLANSection(m); err != nil {
+		log.Errorf("Failed to remove BridgeVLAN section: %v", err)
+		return err
+	}
+
+	if err := n.removeDHCPServerStaticLeaseSection(m); err != nil {
+		log.Errorf("Failed to remove DHCPServerStaticLease section: %v", err)
+		return err
+	}
+
+	if err := n.removeTrafficControlSections(m); err != nil {
+		log.Errorf("Failed to remove traffic control sections: %v", err)
+		return err
+	}
+
 	if err := m.Save(); err != nil {
 		log.Errorf("Failed to update config file='%s': %v", m.Path, err)
 		return err
@@ -1488,3 +2517,45 @@ func (n *Network) RemoveNetwork(ctx context.Context, w http.ResponseWriter) erro
 
 	return web.JSONResponse("removed", w)
 }
+
+func ResetNetworkDropin(ctx context.Context, link string, w http.ResponseWriter) error {
+	if err := RemoveNetworkDropinFile(link); err != nil {
+		log.Errorf("Failed to remove network drop-in of link='%s': %v", link, err)
+		return err
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	if err := c.DBusNetworkReload(ctx); err != nil {
+		return err
+	}
+
+	return web.JSONResponse("reset", w)
+}
+
+func (n *Network) ReplaceNetwork(ctx context.Context, w http.ResponseWriter) error {
+	m, err := n.createOrParseNetworkFile()
+	if err != nil {
+		log.Errorf("Failed to parse network file for link='%s': %v", n.Link, err)
+		return err
+	}
+
+	comments := clearNetworkSections(m)
+
+	// Nothing is written unless the whole document is valid
+	if err := n.buildMatchSection(m); err != nil {
+		return err
+	}
+	if err := n.buildSections(m); err != nil {
+		return err
+	}
+
+	comments.restore(m)
+
+	return n.saveAndApply(ctx, m, w)
+}
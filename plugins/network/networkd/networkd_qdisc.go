@@ -0,0 +1,450 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package networkd
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/go-ini/ini"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/pmd-next-gen/pkg/configfile"
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+)
+
+type QDiscSection struct {
+	Parent string `json:"Parent"`
+	Handle string `json:"Handle"`
+}
+
+type FairQueueingSection struct {
+	Parent              string `json:"Parent"`
+	Handle              string `json:"Handle"`
+	PacketLimit         string `json:"PacketLimit"`
+	FlowLimit           string `json:"FlowLimit"`
+	QuantumBytes        string `json:"QuantumBytes"`
+	InitialQuantumBytes string `json:"InitialQuantumBytes"`
+	MaximumRate         string `json:"MaximumRate"`
+	Buckets             string `json:"Buckets"`
+	OrphanMask          string `json:"OrphanMask"`
+	Pacing              string `json:"Pacing"`
+	CEThresholdSec      string `json:"CEThresholdSec"`
+}
+
+type FairQueueingControlledDelaySection struct {
+	Parent           string `json:"Parent"`
+	Handle           string `json:"Handle"`
+	PacketLimit      string `json:"PacketLimit"`
+	MemoryLimitBytes string `json:"MemoryLimitBytes"`
+	Flows            string `json:"Flows"`
+	TargetSec        string `json:"TargetSec"`
+	IntervalSec      string `json:"IntervalSec"`
+	QuantumBytes     string `json:"QuantumBytes"`
+	ECN              string `json:"ECN"`
+	CEThresholdSec   string `json:"CEThresholdSec"`
+}
+
+type TokenBucketFilterSection struct {
+	Parent     string `json:"Parent"`
+	Handle     string `json:"Handle"`
+	LatencySec string `json:"LatencySec"`
+	LimitBytes string `json:"LimitBytes"`
+	BurstBytes string `json:"BurstBytes"`
+	Rate       string `json:"Rate"`
+	MPUBytes   string `json:"MPUBytes"`
+	PeakRate   string `json:"PeakRate"`
+	MTUBytes   string `json:"MTUBytes"`
+}
+
+type CAKESection struct {
+	Parent                 string `json:"Parent"`
+	Handle                 string `json:"Handle"`
+	Bandwidth              string `json:"Bandwidth"`
+	AutoRateIngress        string `json:"AutoRateIngress"`
+	OverheadBytes          string `json:"OverheadBytes"`
+	MPUBytes               string `json:"MPUBytes"`
+	CompensationMode       string `json:"CompensationMode"`
+	UseRawPacketSize       string `json:"UseRawPacketSize"`
+	FlowIsolationMode      string `json:"FlowIsolationMode"`
+	NAT                    string `json:"NAT"`
+	PriorityQueueingPreset string `json:"PriorityQueueingPreset"`
+	FirewallMark           string `json:"FirewallMark"`
+	Wash                   string `json:"Wash"`
+	SplitGSO               string `json:"SplitGSO"`
+	RTTSec                 string `json:"RTTSec"`
+	AckFilter              string `json:"AckFilter"`
+}
+
+type NetworkEmulatorSection struct {
+	Parent         string `json:"Parent"`
+	Handle         string `json:"Handle"`
+	DelaySec       string `json:"DelaySec"`
+	DelayJitterSec string `json:"DelayJitterSec"`
+	PacketLimit    string `json:"PacketLimit"`
+	LossRate       string `json:"LossRate"`
+	DuplicateRate  string `json:"DuplicateRate"`
+}
+
+type HierarchyTokenBucketSection struct {
+	Parent        string `json:"Parent"`
+	Handle        string `json:"Handle"`
+	DefaultClass  string `json:"DefaultClass"`
+	RateToQuantum string `json:"RateToQuantum"`
+}
+
+type HierarchyTokenBucketClassSection struct {
+	Parent          string `json:"Parent"`
+	ClassId         string `json:"ClassId"`
+	Priority        string `json:"Priority"`
+	QuantumBytes    string `json:"QuantumBytes"`
+	MTUBytes        string `json:"MTUBytes"`
+	OverheadBytes   string `json:"OverheadBytes"`
+	Rate            string `json:"Rate"`
+	CeilRate        string `json:"CeilRate"`
+	BufferBytes     string `json:"BufferBytes"`
+	CeilBufferBytes string `json:"CeilBufferBytes"`
+}
+
+// TrafficControlSections holds the qdisc and class sections of a .network
+// file. A link has a single root qdisc, children hang off its classes.
+type TrafficControlSections struct {
+	QDiscSections                       []QDiscSection                       `json:"QDiscSections"`
+	FairQueueingSections                []FairQueueingSection                `json:"FairQueueingSections"`
+	FairQueueingControlledDelaySections []FairQueueingControlledDelaySection `json:"FairQueueingControlledDelaySections"`
+	TokenBucketFilterSections           []TokenBucketFilterSection           `json:"TokenBucketFilterSections"`
+	CAKESections                        []CAKESection                        `json:"CAKESections"`
+	NetworkEmulatorSections             []NetworkEmulatorSection             `json:"NetworkEmulatorSections"`
+	HierarchyTokenBucketSections        []HierarchyTokenBucketSection        `json:"HierarchyTokenBucketSections"`
+	HierarchyTokenBucketClassSections   []HierarchyTokenBucketClassSection   `json:"HierarchyTokenBucketClassSections"`
+}
+
+// Section name of every slice in TrafficControlSections, in field order
+var trafficControlSectionNames = []string{
+	"QDisc",
+	"FairQueueing",
+	"FairQueueingControlledDelay",
+	"TokenBucketFilter",
+	"CAKE",
+	"NetworkEmulator",
+	"HierarchyTokenBucket",
+	"HierarchyTokenBucketClass",
+}
+
+type tcKey struct {
+	key   string
+	value string
+	valid func(string) bool
+}
+
+func isCAKECompensationMode(s string) bool {
+	return s == "none" || s == "atm" || s == "ptm"
+}
+
+func isCAKEFlowIsolationMode(s string) bool {
+	switch s {
+	case "none", "src-host", "dst-host", "hosts", "flows", "dual-src-host", "dual-dst-host", "triple":
+		return true
+	}
+
+	return false
+}
+
+func isCAKEPriorityQueueingPreset(s string) bool {
+	switch s {
+	case "besteffort", "precedence", "diffserv8", "diffserv4", "diffserv3":
+		return true
+	}
+
+	return false
+}
+
+func isCAKEAckFilter(s string) bool {
+	return s == "aggressive" || validator.IsBool(s)
+}
+
+func isCAKEOverhead(s string) bool {
+	_, err := validator.IsInt(s)
+	return err == nil
+}
+
+func validateTrafficControlKeys(section string, keys []tcKey) error {
+	for _, k := range keys {
+		if validator.IsEmpty(k.value) {
+			continue
+		}
+
+		if !k.valid(k.value) {
+			log.Errorf("Failed to parse %s %s='%s'", section, k.key, k.value)
+			return fmt.Errorf("invalid %s %s='%s'", section, k.key, k.value)
+		}
+	}
+
+	return nil
+}
+
+// validate checks every typed section before anything is written. Qdiscs
+// already in the .network file count towards the single root qdisc, classes
+// default to Parent=root and are not counted.
+func (tc *TrafficControlSections) validate(m *configfile.Meta) error {
+	roots := 0
+	for _, name := range trafficControlSectionNames {
+		if name == "HierarchyTokenBucketClass" {
+			continue
+		}
+
+		for _, s := range m.Sections(name) {
+			if s.Key("Parent").String() == "root" {
+				roots++
+			}
+		}
+	}
+
+	rv := reflect.ValueOf(tc).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		if trafficControlSectionNames[i] == "HierarchyTokenBucketClass" {
+			continue
+		}
+
+		for j := 0; j < rv.Field(i).Len(); j++ {
+			if rv.Field(i).Index(j).FieldByName("Parent").String() == "root" {
+				roots++
+			}
+		}
+	}
+	if roots > 1 {
+		return fmt.Errorf("only one qdisc can be attached to root")
+	}
+
+	for _, q := range tc.QDiscSections {
+		if q.Parent != "clsact" && q.Parent != "ingress" {
+			log.Errorf("Failed to parse QDisc Parent='%s'", q.Parent)
+			return fmt.Errorf("invalid QDisc Parent='%s', must be clsact or ingress", q.Parent)
+		}
+		if err := validateTrafficControlKeys("QDisc", []tcKey{
+			{"Handle", q.Handle, validator.IsTrafficControlHandle},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range tc.FairQueueingSections {
+		if err := validateTrafficControlKeys("FairQueueing", []tcKey{
+			{"Parent", q.Parent, validator.IsTrafficControlParent},
+			{"Handle", q.Handle, validator.IsTrafficControlHandle},
+			{"PacketLimit", q.PacketLimit, validator.IsUint32},
+			{"FlowLimit", q.FlowLimit, validator.IsUint32},
+			{"QuantumBytes", q.QuantumBytes, validator.IsByteSize},
+			{"InitialQuantumBytes", q.InitialQuantumBytes, validator.IsByteSize},
+			{"MaximumRate", q.MaximumRate, validator.IsLinkBitsPerSecond},
+			{"Buckets", q.Buckets, validator.IsUint32},
+			{"OrphanMask", q.OrphanMask, validator.IsUint32},
+			{"Pacing", q.Pacing, validator.IsBool},
+			{"CEThresholdSec", q.CEThresholdSec, validator.IsTimeSpan},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range tc.FairQueueingControlledDelaySections {
+		if err := validateTrafficControlKeys("FairQueueingControlledDelay", []tcKey{
+			{"Parent", q.Parent, validator.IsTrafficControlParent},
+			{"Handle", q.Handle, validator.IsTrafficControlHandle},
+			{"PacketLimit", q.PacketLimit, validator.IsUint32},
+			{"MemoryLimitBytes", q.MemoryLimitBytes, validator.IsByteSize},
+			{"Flows", q.Flows, validator.IsUint32},
+			{"TargetSec", q.TargetSec, validator.IsTimeSpan},
+			{"IntervalSec", q.IntervalSec, validator.IsTimeSpan},
+			{"QuantumBytes", q.QuantumBytes, validator.IsByteSize},
+			{"ECN", q.ECN, validator.IsBool},
+			{"CEThresholdSec", q.CEThresholdSec, validator.IsTimeSpan},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range tc.TokenBucketFilterSections {
+		if validator.IsEmpty(q.Rate) {
+			return fmt.Errorf("missing TokenBucketFilter Rate")
+		}
+		if err := validateTrafficControlKeys("TokenBucketFilter", []tcKey{
+			{"Parent", q.Parent, validator.IsTrafficControlParent},
+			{"Handle", q.Handle, validator.IsTrafficControlHandle},
+			{"LatencySec", q.LatencySec, validator.IsTimeSpan},
+			{"LimitBytes", q.LimitBytes, validator.IsByteSize},
+			{"BurstBytes", q.BurstBytes, validator.IsByteSize},
+			{"Rate", q.Rate, validator.IsLinkBitsPerSecond},
+			{"MPUBytes", q.MPUBytes, validator.IsByteSize},
+			{"PeakRate", q.PeakRate, validator.IsLinkBitsPerSecond},
+			{"MTUBytes", q.MTUBytes, validator.IsByteSize},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range tc.CAKESections {
+		if err := validateTrafficControlKeys("CAKE", []tcKey{
+			{"Parent", q.Parent, validator.IsTrafficControlParent},
+			{"Handle", q.Handle, validator.IsTrafficControlHandle},
+			{"Bandwidth", q.Bandwidth, validator.IsLinkBitsPerSecond},
+			{"AutoRateIngress", q.AutoRateIngress, validator.IsBool},
+			{"OverheadBytes", q.OverheadBytes, isCAKEOverhead},
+			{"MPUBytes", q.MPUBytes, validator.IsByteSize},
+			{"CompensationMode", q.CompensationMode, isCAKECompensationMode},
+			{"UseRawPacketSize", q.UseRawPacketSize, validator.IsBool},
+			{"FlowIsolationMode", q.FlowIsolationMode, isCAKEFlowIsolationMode},
+			{"NAT", q.NAT, validator.IsBool},
+			{"PriorityQueueingPreset", q.PriorityQueueingPreset, isCAKEPriorityQueueingPreset},
+			{"FirewallMark", q.FirewallMark, validator.IsUint32},
+			{"Wash", q.Wash, validator.IsBool},
+			{"SplitGSO", q.SplitGSO, validator.IsBool},
+			{"RTTSec", q.RTTSec, validator.IsTimeSpan},
+			{"AckFilter", q.AckFilter, isCAKEAckFilter},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range tc.NetworkEmulatorSections {
+		if err := validateTrafficControlKeys("NetworkEmulator", []tcKey{
+			{"Parent", q.Parent, validator.IsTrafficControlParent},
+			{"Handle", q.Handle, validator.IsTrafficControlHandle},
+			{"DelaySec", q.DelaySec, validator.IsTimeSpan},
+			{"DelayJitterSec", q.DelayJitterSec, validator.IsTimeSpan},
+			{"PacketLimit", q.PacketLimit, validator.IsUint32},
+			{"LossRate", q.LossRate, validator.IsPercent},
+			{"DuplicateRate", q.DuplicateRate, validator.IsPercent},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, q := range tc.HierarchyTokenBucketSections {
+		if err := validateTrafficControlKeys("HierarchyTokenBucket", []tcKey{
+			{"Parent", q.Parent, validator.IsTrafficControlParent},
+			{"Handle", q.Handle, validator.IsTrafficControlHandle},
+			{"DefaultClass", q.DefaultClass, validator.IsUint32},
+			{"RateToQuantum", q.RateToQuantum, validator.IsUint32},
+		}); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range tc.HierarchyTokenBucketClassSections {
+		if validator.IsEmpty(c.Rate) {
+			return fmt.Errorf("missing HierarchyTokenBucketClass Rate")
+		}
+		if err := validateTrafficControlKeys("HierarchyTokenBucketClass", []tcKey{
+			{"Parent", c.Parent, validator.IsTrafficControlParent},
+			{"ClassId", c.ClassId, validator.IsTrafficControlClassId},
+			{"Priority", c.Priority, validator.IsUint32},
+			{"QuantumBytes", c.QuantumBytes, validator.IsByteSize},
+			{"MTUBytes", c.MTUBytes, validator.IsByteSize},
+			{"OverheadBytes", c.OverheadBytes, validator.IsByteSize},
+			{"Rate", c.Rate, validator.IsLinkBitsPerSecond},
+			{"CeilRate", c.CeilRate, validator.IsLinkBitsPerSecond},
+			{"BufferBytes", c.BufferBytes, validator.IsByteSize},
+			{"CeilBufferBytes", c.CeilBufferBytes, validator.IsByteSize},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (tc *TrafficControlSections) isEmpty() bool {
+	rv := reflect.ValueOf(tc).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Field(i).Len() > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildTrafficControlSections writes one section per entry, keys in field order
+func (n *Network) buildTrafficControlSections(m *configfile.Meta) error {
+	if n.TrafficControl.isEmpty() {
+		return nil
+	}
+
+	if err := n.TrafficControl.validate(m); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(&n.TrafficControl).Elem()
+	for i, name := range trafficControlSectionNames {
+		s := rv.Field(i)
+		for j := 0; j < s.Len(); j++ {
+			if err := m.NewSection(name); err != nil {
+				return err
+			}
+
+			e := s.Index(j)
+			for k := 0; k < e.NumField(); k++ {
+				if v := e.Field(k).String(); !validator.IsEmpty(v) {
+					m.SetKeyToNewSectionString(e.Type().Field(k).Name, v)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseTrafficControlSections(m *configfile.Meta) (TrafficControlSections, error) {
+	tc := TrafficControlSections{}
+
+	var errs []error
+	rv := reflect.ValueOf(&tc).Elem()
+	for i, name := range trafficControlSectionNames {
+		s := rv.Field(i)
+		for _, sec := range m.Sections(name) {
+			e := reflect.New(s.Type().Elem())
+			errs = append(errs, configfile.MapSectionsTo([]*ini.Section{sec}, e.Interface()))
+			s.Set(reflect.Append(s, e.Elem()))
+		}
+	}
+
+	return tc, errors.Join(errs...)
+}
+
+// removeTrafficControlSections drops qdiscs by Parent and classes by ClassId
+func (n *Network) removeTrafficControlSections(m *configfile.Meta) error {
+	if n.TrafficControl.isEmpty() {
+		return nil
+	}
+
+	rv := reflect.ValueOf(&n.TrafficControl).Elem()
+	for i, name := range trafficControlSectionNames {
+		key := "Parent"
+		if name == "HierarchyTokenBucketClass" {
+			key = "ClassId"
+		}
+
+		s := rv.Field(i)
+		for j := 0; j < s.Len(); j++ {
+			value := s.Index(j).FieldByName(key).String()
+
+			found := false
+			for idx, sec := range m.Sections(name) {
+				if sec.Key(key).String() == value {
+					m.Cfg.DeleteSectionWithIndex(name, idx)
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				log.Errorf("Failed to remove %s %s='%s': not found", name, key, value)
+				return fmt.Errorf("%s %s='%s' not found", name, key, value)
+			}
+		}
+	}
+
+	return nil
+}
@@ -35,6 +35,31 @@ func routerRemoveNetwork(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func routerReplaceNetwork(w http.ResponseWriter, r *http.Request) {
+	n, err := decodeNetworkJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+	n.Link = mux.Vars(r)["link"]
+
+	if err := n.ReplaceNetwork(r.Context(), w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerAcquireNetworkConfig(w http.ResponseWriter, r *http.Request) {
+	if err := AcquireNetworkConfig(r.Context(), mux.Vars(r)["link"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerResetNetworkDropin(w http.ResponseWriter, r *http.Request) {
+	if err := ResetNetworkDropin(r.Context(), mux.Vars(r)["link"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
 func routerAcquireLinks(w http.ResponseWriter, r *http.Request) {
 	l, err := AcquireLinks(r.Context())
 	if err != nil {
@@ -77,6 +102,55 @@ func routerRemoveNetDev(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func routerAcquireNetDev(w http.ResponseWriter, r *http.Request) {
+	if err := AcquireNetDev(r.Context(), mux.Vars(r)["name"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerPatchNetDev(w http.ResponseWriter, r *http.Request) {
+	p, err := decodeNetDevPatchJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+	p.Name = mux.Vars(r)["name"]
+
+	if err := p.PatchNetDev(r.Context(), w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerAcquireWireGuard(w http.ResponseWriter, r *http.Request) {
+	if err := AcquireWireGuard(mux.Vars(r)["name"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerAddWireGuardPeer(w http.ResponseWriter, r *http.Request) {
+	p, err := decodeWireGuardPeerJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := AddWireGuardPeer(r.Context(), mux.Vars(r)["name"], p, w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerRemoveWireGuardPeer(w http.ResponseWriter, r *http.Request) {
+	p, err := decodeWireGuardPeerJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := RemoveWireGuardPeer(r.Context(), mux.Vars(r)["name"], p, w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
 func routerConfigureLink(w http.ResponseWriter, r *http.Request) {
 	n, err := decodeLinkJSONRequest(r)
 	if err != nil {
@@ -89,6 +163,48 @@ func routerConfigureLink(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func routerAcquireLinkConfigs(w http.ResponseWriter, r *http.Request) {
+	if err := AcquireLinkConfigs(r.Context(), w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerAcquireLinkConfig(w http.ResponseWriter, r *http.Request) {
+	if err := AcquireLinkConfig(r.Context(), mux.Vars(r)["name"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerRemoveLinkConfig(w http.ResponseWriter, r *http.Request) {
+	if err := RemoveLinkConfig(r.Context(), mux.Vars(r)["name"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerAcquireDHCPLease(w http.ResponseWriter, r *http.Request) {
+	if err := AcquireDHCPLease(r.Context(), mux.Vars(r)["link"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerRenewDHCPLease(w http.ResponseWriter, r *http.Request) {
+	if err := RenewDHCPLease(r.Context(), mux.Vars(r)["link"], false, w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerForceRenewDHCPLease(w http.ResponseWriter, r *http.Request) {
+	if err := RenewDHCPLease(r.Context(), mux.Vars(r)["link"], true, w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
+func routerAcquireDHCPServerLeases(w http.ResponseWriter, r *http.Request) {
+	if err := AcquireDHCPServerLeases(r.Context(), mux.Vars(r)["link"], w); err != nil {
+		web.JSONResponseError(err, w)
+	}
+}
+
 func RegisterRouterNetworkd(router *mux.Router) {
 	n := router.PathPrefix("/networkd").Subrouter().StrictSlash(false)
 
@@ -96,9 +212,25 @@ func RegisterRouterNetworkd(router *mux.Router) {
 	n.HandleFunc("/network/describelinks", routerAcquireLinks).Methods("GET")
 	n.HandleFunc("/network/configure", routerConfigureNetwork).Methods("POST")
 	n.HandleFunc("/network/remove", routerRemoveNetwork).Methods("DELETE")
+	n.HandleFunc("/network/{link}", routerAcquireNetworkConfig).Methods("GET")
+	n.HandleFunc("/network/{link}", routerReplaceNetwork).Methods("PUT")
+	n.HandleFunc("/network/{link}/dropin", routerResetNetworkDropin).Methods("DELETE")
 
 	n.HandleFunc("/netdev/configure", routerConfigureNetDev).Methods("POST")
 	n.HandleFunc("/netdev/remove", routerRemoveNetDev).Methods("DELETE")
+	n.HandleFunc("/netdev/{name}", routerAcquireNetDev).Methods("GET")
+	n.HandleFunc("/netdev/{name}", routerPatchNetDev).Methods("PATCH")
+	n.HandleFunc("/netdev/{name}/wireguard", routerAcquireWireGuard).Methods("GET")
+	n.HandleFunc("/netdev/{name}/wireguard/peer", routerAddWireGuardPeer).Methods("POST")
+	n.HandleFunc("/netdev/{name}/wireguard/peer", routerRemoveWireGuardPeer).Methods("DELETE")
 
+	n.HandleFunc("/link", routerAcquireLinkConfigs).Methods("GET")
 	n.HandleFunc("/link/configure", routerConfigureLink).Methods("POST")
+	n.HandleFunc("/link/{name}", routerAcquireLinkConfig).Methods("GET")
+	n.HandleFunc("/link/{name}", routerRemoveLinkConfig).Methods("DELETE")
+
+	n.HandleFunc("/{link}/lease", routerAcquireDHCPLease).Methods("GET")
+	n.HandleFunc("/{link}/lease/renew", routerRenewDHCPLease).Methods("POST")
+	n.HandleFunc("/{link}/lease/force-renew", routerForceRenewDHCPLease).Methods("POST")
+	n.HandleFunc("/{link}/dhcpserver/leases", routerAcquireDHCPServerLeases).Methods("GET")
 }
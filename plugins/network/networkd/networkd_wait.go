@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package networkd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/vmware/pmd-next-gen/pkg/configfile"
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+)
+
+const (
+	defaultWaitTimeoutSec = 30
+	maxWaitTimeoutSec     = 300
+	waitPollInterval      = 500 * time.Millisecond
+)
+
+// Ordered the way networkd-wait-online compares them
+var operationalStates = []string{"off", "no-carrier", "dormant", "degraded-carrier", "carrier", "degraded", "enslaved", "routable"}
+var addressStates = []string{"off", "degraded", "routable"}
+
+type WaitSection struct {
+	OperationalState string `json:"OperationalState"`
+	AddressState     string `json:"AddressState"`
+	TimeoutSec       uint   `json:"TimeoutSec"`
+	AutoRevert       bool   `json:"AutoRevert"`
+	ManagementLink   string `json:"ManagementLink"`
+}
+
+type LinkWaitState struct {
+	Link             string `json:"Link"`
+	SetupState       string `json:"SetupState"`
+	OperationalState string `json:"OperationalState"`
+	AddressState     string `json:"AddressState"`
+	Reached          bool   `json:"Reached"`
+	Reverted         bool   `json:"Reverted"`
+}
+
+// networkFileSnapshot keeps the content, mode and owner of a file before it is rewritten
+type networkFileSnapshot struct {
+	path string
+	data []byte
+	mode os.FileMode
+	uid  int
+	gid  int
+}
+
+func (w *WaitSection) isEmpty() bool {
+	return validator.IsEmpty(w.OperationalState) && validator.IsEmpty(w.AddressState) && w.TimeoutSec == 0 && !w.AutoRevert
+}
+
+func stateIndex(states []string, state string) int {
+	for i, s := range states {
+		if s == state {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (w *WaitSection) validate() error {
+	if !validator.IsEmpty(w.OperationalState) && stateIndex(operationalStates, w.OperationalState) < 0 {
+		return fmt.Errorf("invalid operational state='%s'", w.OperationalState)
+	}
+	if !validator.IsEmpty(w.AddressState) && stateIndex(addressStates, w.AddressState) < 0 {
+		return fmt.Errorf("invalid address state='%s'", w.AddressState)
+	}
+	if w.TimeoutSec > maxWaitTimeoutSec {
+		return fmt.Errorf("invalid timeout='%d', at most %d seconds", w.TimeoutSec, maxWaitTimeoutSec)
+	}
+	if !validator.IsEmpty(w.ManagementLink) && !validator.LinkExists(w.ManagementLink) {
+		return fmt.Errorf("invalid management link='%s'", w.ManagementLink)
+	}
+
+	return nil
+}
+
+func takeNetworkFileSnapshot(m *configfile.Meta) *networkFileSnapshot {
+	s := networkFileSnapshot{
+		path: m.Path,
+		mode: 0644,
+		uid:  -1,
+		gid:  -1,
+	}
+
+	if fi, err := os.Stat(m.Path); err == nil {
+		s.data, _ = os.ReadFile(m.Path)
+		s.mode = fi.Mode().Perm()
+		if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+			s.uid = int(st.Uid)
+			s.gid = int(st.Gid)
+		}
+	}
+
+	return &s
+}
+
+// A file we created from scratch is removed rather than left empty
+func (s *networkFileSnapshot) restore() error {
+	if len(s.data) == 0 {
+		return os.Remove(s.path)
+	}
+
+	if err := os.WriteFile(s.path, s.data, s.mode); err != nil {
+		return err
+	}
+	if err := os.Chmod(s.path, s.mode); err != nil {
+		return err
+	}
+
+	return os.Chown(s.path, s.uid, s.gid)
+}
+
+// linkStateChangedSince tells whether networkd rewrote the state file of the
+// link after the given time. It does so on every change of the setup state.
+func linkStateChangedSince(link string, since time.Time) bool {
+	l, err := netlink.LinkByName(link)
+	if err != nil {
+		return false
+	}
+
+	fi, err := os.Stat("/run/systemd/netif/links/" + strconv.Itoa(l.Attrs().Index))
+	if err != nil {
+		return false
+	}
+
+	return fi.ModTime().After(since)
+}
+
+func acquireLinkWaitState(link string) (*LinkWaitState, error) {
+	l, err := netlink.LinkByName(link)
+	if err != nil {
+		return nil, err
+	}
+
+	s := LinkWaitState{
+		Link: link,
+	}
+	s.SetupState, _ = ParseLinkSetupState(l.Attrs().Index)
+	s.OperationalState, _ = ParseLinkOperationalState(l.Attrs().Index)
+	s.AddressState, _ = ParseLinkAddressState(l.Attrs().Index)
+
+	return &s, nil
+}
+
+func (w *WaitSection) reached(s *LinkWaitState) bool {
+	if s.SetupState != "configured" {
+		return false
+	}
+	if stateIndex(operationalStates, s.OperationalState) < stateIndex(operationalStates, w.OperationalState) {
+		return false
+	}
+	if stateIndex(addressStates, s.AddressState) < stateIndex(addressStates, w.AddressState) {
+		return false
+	}
+
+	return true
+}
+
+// managementReachable tells whether the link the daemon is reached through kept its routes
+func (w *WaitSection) managementReachable() bool {
+	if validator.IsEmpty(w.ManagementLink) {
+		return true
+	}
+
+	s, err := acquireLinkWaitState(w.ManagementLink)
+	if err != nil {
+		return false
+	}
+
+	return s.OperationalState == "routable"
+}
+
+// WaitLinkState polls networkd's link state until the requested states are
+// reached, the timeout expires or the context is cancelled. States read before
+// networkd picked up the configuration applied at since are not taken into
+// account.
+func (w *WaitSection) WaitLinkState(ctx context.Context, link string, since time.Time) (*LinkWaitState, error) {
+	if validator.IsEmpty(w.OperationalState) && validator.IsEmpty(w.AddressState) {
+		w.OperationalState = "routable"
+	}
+
+	timeout := time.Duration(w.TimeoutSec) * time.Second
+	if w.TimeoutSec == 0 {
+		timeout = defaultWaitTimeoutSec * time.Second
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(waitPollInterval)
+	defer ticker.Stop()
+
+	applied := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			return acquireLinkWaitState(link)
+		case <-ticker.C:
+		}
+
+		s, err := acquireLinkWaitState(link)
+		if err != nil {
+			return nil, err
+		}
+
+		// Until networkd reconfigured the link its state is the one of the
+		// previous configuration
+		if !applied {
+			applied = s.SetupState != "configured" || linkStateChangedSince(link, since)
+			if !applied {
+				continue
+			}
+		}
+
+		if w.reached(s) && w.managementReachable() {
+			s.Reached = true
+			return s, nil
+		}
+	}
+}
+
+// waitAndRevert waits for the link when asked to and puts the previous
+// configuration back if the link, or the management link, did not come up.
+func (n *Network) waitAndRevert(ctx context.Context, c *SDConnection, snapshot *networkFileSnapshot, since time.Time) (*LinkWaitState, error) {
+	s, err := n.Wait.WaitLinkState(ctx, n.Link, since)
+	if err != nil {
+		log.Errorf("Failed to wait for link='%s': %v", n.Link, err)
+		return nil, err
+	}
+
+	if s.Reached || !n.Wait.AutoRevert {
+		return s, nil
+	}
+
+	log.Warnf("Link='%s' did not reach the requested state (operational='%s' address='%s'), reverting configuration", n.Link, s.OperationalState, s.AddressState)
+
+	if err := n.revert(ctx, c, snapshot); err != nil {
+		return nil, err
+	}
+
+	s.Reverted = true
+	return s, nil
+}
+
+// revert restores the previous configuration and has networkd apply it
+func (n *Network) revert(ctx context.Context, c *SDConnection, snapshot *networkFileSnapshot) error {
+	if err := snapshot.restore(); err != nil {
+		log.Errorf("Failed to revert config file='%s': %v", snapshot.path, err)
+		return err
+	}
+
+	if err := c.DBusNetworkReload(ctx); err != nil {
+		return err
+	}
+
+	if l, err := netlink.LinkByName(n.Link); err == nil {
+		c.DBusNetworkReconfigureLink(ctx, l.Attrs().Index)
+	}
+
+	return nil
+}
+
+// revertOnError puts the previous configuration back when applying the saved
+// one failed before the wait could tell
+func (n *Network) revertOnError(ctx context.Context, c *SDConnection, snapshot *networkFileSnapshot) {
+	if snapshot == nil || !n.Wait.AutoRevert {
+		return
+	}
+
+	log.Warnf("Failed to apply configuration of link='%s', reverting configuration", n.Link)
+
+	if c == nil {
+		if err := snapshot.restore(); err != nil {
+			log.Errorf("Failed to revert config file='%s': %v", snapshot.path, err)
+		}
+		return
+	}
+
+	n.revert(context.WithoutCancel(ctx), c, snapshot)
+}
@@ -0,0 +1,548 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package networkd
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/vmware/pmd-next-gen/pkg/configfile"
+	"github.com/vmware/pmd-next-gen/pkg/system"
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+	"github.com/vmware/pmd-next-gen/pkg/web"
+)
+
+// Generic netlink interface of the wireguard kernel module, see
+// include/uapi/linux/wireguard.h
+const (
+	wgGenlName    = "wireguard"
+	wgGenlVersion = 1
+
+	wgCmdGetDevice = 0
+
+	wgDeviceAIfindex    = 1
+	wgDeviceAIfname     = 2
+	wgDeviceAPublicKey  = 4
+	wgDeviceAListenPort = 6
+	wgDeviceAFwmark     = 7
+	wgDeviceAPeers      = 8
+
+	wgPeerAPublicKey                   = 1
+	wgPeerAEndpoint                    = 4
+	wgPeerAPersistentKeepaliveInterval = 5
+	wgPeerALastHandshakeTime           = 6
+	wgPeerARxBytes                     = 7
+	wgPeerATxBytes                     = 8
+	wgPeerAAllowedIPs                  = 9
+
+	wgAllowedIPAFamily   = 1
+	wgAllowedIPAIPAddr   = 2
+	wgAllowedIPACidrMask = 3
+)
+
+type WireGuardPeerStatus struct {
+	PublicKey           string    `json:"PublicKey"`
+	Endpoint            string    `json:"Endpoint"`
+	AllowedIPs          []string  `json:"AllowedIPs"`
+	PersistentKeepalive uint16    `json:"PersistentKeepalive"`
+	LastHandshakeTime   time.Time `json:"LastHandshakeTime"`
+	RxBytes             uint64    `json:"RxBytes"`
+	TxBytes             uint64    `json:"TxBytes"`
+}
+
+// WireGuardPresharedKey is a preshared key generated for a peer. The remote
+// side has to be configured with the same key.
+type WireGuardPresharedKey struct {
+	PublicKey    string `json:"PublicKey"`
+	PresharedKey string `json:"PresharedKey"`
+}
+
+// WireGuardPublicKey is handed back when a WireGuard netdev is configured
+type WireGuardPublicKey struct {
+	Name          string                  `json:"Name"`
+	PublicKey     string                  `json:"PublicKey"`
+	PresharedKeys []WireGuardPresharedKey `json:"PresharedKeys"`
+}
+
+type WireGuardStatus struct {
+	Name         string                `json:"Name"`
+	Ifindex      int                   `json:"Ifindex"`
+	PublicKey    string                `json:"PublicKey"`
+	ListenPort   uint16                `json:"ListenPort"`
+	FirewallMark uint32                `json:"FirewallMark"`
+	Peers        []WireGuardPeerStatus `json:"Peers"`
+}
+
+func decodeWireGuardPeerJSONRequest(r *http.Request) (*WireGuardPeer, error) {
+	p := WireGuardPeer{}
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// GenerateWireGuardKey returns a base64 encoded Curve25519 private key and its public key.
+func GenerateWireGuardKey() (string, string, error) {
+	k, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(k.Bytes()), base64.StdEncoding.EncodeToString(k.PublicKey().Bytes()), nil
+}
+
+func GenerateWireGuardPresharedKey() (string, error) {
+	k := make([]byte, 32)
+	if _, err := rand.Read(k); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(k), nil
+}
+
+func buildWireGuardKeyFilePath(link string) string {
+	return path.Join("/etc/systemd/network", link+".key")
+}
+
+func buildWireGuardPresharedKeyFilePath(link string, publicKey string) string {
+	k, _ := base64.StdEncoding.DecodeString(publicKey)
+	return path.Join("/etc/systemd/network", link+"-"+hex.EncodeToString(k)[:16]+".psk")
+}
+
+// Secrets are readable by systemd-networkd only
+func writeWireGuardKeyFile(file string, key string) error {
+	if err := os.WriteFile(file, []byte(key+"\n"), 0600); err != nil {
+		return err
+	}
+
+	return system.ChangeGroupPermission("systemd-network", file, 0640)
+}
+
+func readWireGuardKeyFile(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	key := strings.TrimSpace(string(b))
+	if !validator.IsWireGuardKey(key) {
+		return "", fmt.Errorf("invalid key in '%s'", file)
+	}
+
+	return key, nil
+}
+
+// WireGuardPublicKeyFromPrivate derives the public key from a base64 encoded private key
+func WireGuardPublicKeyFromPrivate(private string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(private)
+	if err != nil {
+		return "", err
+	}
+
+	k, err := ecdh.X25519().NewPrivateKey(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(k.PublicKey().Bytes()), nil
+}
+
+func (n *NetDev) addKeyFile(file string, key string) {
+	if n.keyFiles == nil {
+		n.keyFiles = make(map[string]string)
+	}
+
+	n.keyFiles[file] = key
+}
+
+func (n *NetDev) writeKeyFiles() error {
+	for file, key := range n.keyFiles {
+		if err := writeWireGuardKeyFile(file, key); err != nil {
+			log.Errorf("Failed to write key file='%s' of netdev='%s': %v", file, n.Name, err)
+			n.removeKeyFiles()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *NetDev) removeKeyFiles() {
+	for file := range n.keyFiles {
+		os.Remove(file)
+	}
+}
+
+// wireGuardPublicKey returns the public key of the configured private key,
+// empty when it can not be read
+func (n *NetDev) wireGuardPublicKey() string {
+	private := n.WireGuardSection.PrivateKey
+	if validator.IsEmpty(private) {
+		if k, ok := n.keyFiles[n.WireGuardSection.PrivateKeyFile]; ok {
+			private = k
+		} else if k, err := readWireGuardKeyFile(n.WireGuardSection.PrivateKeyFile); err == nil {
+			private = k
+		}
+	}
+
+	public, err := WireGuardPublicKeyFromPrivate(private)
+	if err != nil {
+		return ""
+	}
+
+	return public
+}
+
+// wireGuardPresharedKeys returns the preshared keys generated for the peers
+func (n *NetDev) wireGuardPresharedKeys() []WireGuardPresharedKey {
+	keys := []WireGuardPresharedKey{}
+	for _, p := range n.WireGuardPeerSections {
+		if !p.GeneratePresharedKey || !validator.IsEmpty(p.PresharedKey) || !validator.IsEmpty(p.PresharedKeyFile) {
+			continue
+		}
+
+		file := buildWireGuardPresharedKeyFilePath(n.Name, p.PublicKey)
+		psk, ok := n.keyFiles[file]
+		if !ok {
+			var err error
+			if psk, err = readWireGuardKeyFile(file); err != nil {
+				continue
+			}
+		}
+
+		keys = append(keys, WireGuardPresharedKey{
+			PublicKey:    p.PublicKey,
+			PresharedKey: psk,
+		})
+	}
+
+	return keys
+}
+
+// removeWireGuardKeyFiles drops the private and preshared keys generated for
+// the netdev. Key files given by the user are left alone.
+func removeWireGuardKeyFiles(m *configfile.Meta, link string) {
+	if m.GetKeySectionString("WireGuard", "PrivateKeyFile") == buildWireGuardKeyFilePath(link) {
+		os.Remove(buildWireGuardKeyFilePath(link))
+	}
+
+	for _, s := range m.Sections("WireGuardPeer") {
+		if s.Key("PresharedKeyFile").String() == buildWireGuardPresharedKeyFilePath(link, s.Key("PublicKey").String()) {
+			os.Remove(s.Key("PresharedKeyFile").String())
+		}
+	}
+}
+
+func AddWireGuardPeer(ctx context.Context, link string, p *WireGuardPeer, w http.ResponseWriter) error {
+	m, err := ParseNetDevFile(link, "wireguard")
+	if err != nil {
+		log.Errorf("Failed to parse netdev file for link='%s': %v", link, err)
+		return err
+	}
+
+	// A peer with the same key is replaced
+	for i, s := range m.Sections("WireGuardPeer") {
+		if s.Key("PublicKey").String() == p.PublicKey {
+			m.Cfg.DeleteSectionWithIndex("WireGuardPeer", i)
+			break
+		}
+	}
+
+	n := NetDev{
+		Name:                  link,
+		Kind:                  "wireguard",
+		WireGuardPeerSections: []WireGuardPeer{*p},
+	}
+	if err := n.buildWireGuardPeerSection(m); err != nil {
+		return err
+	}
+
+	if err := n.writeKeyFiles(); err != nil {
+		return err
+	}
+
+	if err := m.Save(); err != nil {
+		log.Errorf("Failed to update config file='%s': %v", m.Path, err)
+		n.removeKeyFiles()
+		return err
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	if err := c.DBusNetworkReload(ctx); err != nil {
+		return err
+	}
+
+	// A generated preshared key is needed to configure the remote side
+	if keys := n.wireGuardPresharedKeys(); len(keys) > 0 {
+		return web.JSONResponse(keys[0], w)
+	}
+
+	return web.JSONResponse("configured", w)
+}
+
+func RemoveWireGuardPeer(ctx context.Context, link string, p *WireGuardPeer, w http.ResponseWriter) error {
+	m, err := ParseNetDevFile(link, "wireguard")
+	if err != nil {
+		log.Errorf("Failed to parse netdev file for link='%s': %v", link, err)
+		return err
+	}
+
+	found := false
+	for i, s := range m.Sections("WireGuardPeer") {
+		if s.Key("PublicKey").String() != p.PublicKey {
+			continue
+		}
+
+		// Drop the preshared key we generated for the peer
+		if s.Key("PresharedKeyFile").String() == buildWireGuardPresharedKeyFilePath(link, p.PublicKey) {
+			os.Remove(s.Key("PresharedKeyFile").String())
+		}
+
+		m.Cfg.DeleteSectionWithIndex("WireGuardPeer", i)
+		found = true
+		break
+	}
+
+	if !found {
+		log.Errorf("Failed to remove WireGuardPeer='%s' from link='%s': not found", p.PublicKey, link)
+		return fmt.Errorf("wireguard peer '%s' not found", p.PublicKey)
+	}
+
+	if err := m.Save(); err != nil {
+		log.Errorf("Failed to update config file='%s': %v", m.Path, err)
+		return err
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection with the system bus: %v", err)
+		return err
+	}
+	defer c.Close()
+
+	if err := c.DBusNetworkReload(ctx); err != nil {
+		return err
+	}
+
+	return web.JSONResponse("removed", w)
+}
+
+func parseWireGuardEndpoint(b []byte) string {
+	if len(b) < 4 {
+		return ""
+	}
+
+	port := int(binary.BigEndian.Uint16(b[2:4]))
+	switch nl.NativeEndian().Uint16(b[0:2]) {
+	case unix.AF_INET:
+		if len(b) < 8 {
+			return ""
+		}
+		return net.JoinHostPort(net.IP(b[4:8]).String(), strconv.Itoa(port))
+	case unix.AF_INET6:
+		if len(b) < 24 {
+			return ""
+		}
+		return net.JoinHostPort(net.IP(b[8:24]).String(), strconv.Itoa(port))
+	}
+
+	return ""
+}
+
+func parseWireGuardAllowedIPs(b []byte) []string {
+	var ips []string
+
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil
+	}
+
+	for _, a := range attrs {
+		nested, err := nl.ParseRouteAttr(a.Value)
+		if err != nil {
+			continue
+		}
+
+		var ip net.IP
+		mask := 0
+		for _, attr := range nested {
+			switch attr.Attr.Type &^ nl.NLA_F_NESTED {
+			case wgAllowedIPAIPAddr:
+				ip = net.IP(attr.Value)
+			case wgAllowedIPACidrMask:
+				mask = int(attr.Value[0])
+			}
+		}
+
+		if ip != nil {
+			ips = append(ips, ip.String()+"/"+strconv.Itoa(mask))
+		}
+	}
+
+	return ips
+}
+
+func parseWireGuardPeer(b []byte) (*WireGuardPeerStatus, error) {
+	attrs, err := nl.ParseRouteAttr(b)
+	if err != nil {
+		return nil, err
+	}
+
+	p := WireGuardPeerStatus{}
+	for _, a := range attrs {
+		switch a.Attr.Type &^ nl.NLA_F_NESTED {
+		case wgPeerAPublicKey:
+			p.PublicKey = base64.StdEncoding.EncodeToString(a.Value)
+		case wgPeerAEndpoint:
+			p.Endpoint = parseWireGuardEndpoint(a.Value)
+		case wgPeerAPersistentKeepaliveInterval:
+			p.PersistentKeepalive = nl.NativeEndian().Uint16(a.Value)
+		case wgPeerALastHandshakeTime:
+			if len(a.Value) >= 16 {
+				sec := int64(nl.NativeEndian().Uint64(a.Value[0:8]))
+				nsec := int64(nl.NativeEndian().Uint64(a.Value[8:16]))
+				if sec != 0 || nsec != 0 {
+					p.LastHandshakeTime = time.Unix(sec, nsec)
+				}
+			}
+		case wgPeerARxBytes:
+			p.RxBytes = nl.NativeEndian().Uint64(a.Value)
+		case wgPeerATxBytes:
+			p.TxBytes = nl.NativeEndian().Uint64(a.Value)
+		case wgPeerAAllowedIPs:
+			p.AllowedIPs = parseWireGuardAllowedIPs(a.Value)
+		}
+	}
+
+	return &p, nil
+}
+
+func AcquireWireGuardStatus(link string) (*WireGuardStatus, error) {
+	l, err := netlink.LinkByName(link)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Type() != "wireguard" {
+		return nil, fmt.Errorf("link='%s' is not a wireguard device", link)
+	}
+
+	f, err := netlink.GenlFamilyGet(wgGenlName)
+	if err != nil {
+		log.Errorf("Failed to resolve generic netlink family '%s': %v", wgGenlName, err)
+		return nil, err
+	}
+
+	req := nl.NewNetlinkRequest(int(f.ID), syscall.NLM_F_DUMP)
+	req.AddData(&nl.Genlmsg{
+		Command: wgCmdGetDevice,
+		Version: wgGenlVersion,
+	})
+	req.AddData(nl.NewRtAttr(wgDeviceAIfindex, nl.Uint32Attr(uint32(l.Attrs().Index))))
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, f.ID)
+	if err != nil {
+		log.Errorf("Failed to acquire wireguard device='%s': %v", link, err)
+		return nil, err
+	}
+
+	s := WireGuardStatus{
+		Name:    link,
+		Ifindex: l.Attrs().Index,
+	}
+
+	// Large peer lists are split over several messages
+	peers := make(map[string]*WireGuardPeerStatus)
+	var order []string
+
+	for _, m := range msgs {
+		if len(m) < nl.SizeofGenlmsg {
+			continue
+		}
+
+		attrs, err := nl.ParseRouteAttr(m[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range attrs {
+			switch a.Attr.Type &^ nl.NLA_F_NESTED {
+			case wgDeviceAIfname:
+				s.Name = string(a.Value[:len(a.Value)-1])
+			case wgDeviceAPublicKey:
+				s.PublicKey = base64.StdEncoding.EncodeToString(a.Value)
+			case wgDeviceAListenPort:
+				s.ListenPort = nl.NativeEndian().Uint16(a.Value)
+			case wgDeviceAFwmark:
+				s.FirewallMark = nl.NativeEndian().Uint32(a.Value)
+			case wgDeviceAPeers:
+				list, err := nl.ParseRouteAttr(a.Value)
+				if err != nil {
+					return nil, err
+				}
+
+				for _, pa := range list {
+					p, err := parseWireGuardPeer(pa.Value)
+					if err != nil {
+						return nil, err
+					}
+
+					if e, ok := peers[p.PublicKey]; ok {
+						e.AllowedIPs = append(e.AllowedIPs, p.AllowedIPs...)
+						continue
+					}
+
+					peers[p.PublicKey] = p
+					order = append(order, p.PublicKey)
+				}
+			}
+		}
+	}
+
+	for _, k := range order {
+		s.Peers = append(s.Peers, *peers[k])
+	}
+
+	return &s, nil
+}
+
+func AcquireWireGuard(link string, w http.ResponseWriter) error {
+	if validator.IsEmpty(link) {
+		return errors.New("missing link")
+	}
+
+	s, err := AcquireWireGuardStatus(link)
+	if err != nil {
+		return err
+	}
+
+	return web.JSONResponse(s, w)
+}
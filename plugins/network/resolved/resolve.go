@@ -203,10 +203,12 @@ func DescribeDns(ctx context.Context) (*Describe, error) {
 	return &d, nil
 }
 
-func restartResolved(ctx context.Context) error {
+// reloadResolved has systemd-resolved reread its configuration. Versions of
+// resolved which can not reload are restarted instead.
+func reloadResolved(ctx context.Context) error {
 	u := systemd.UnitRequest{
 		Unit: "systemd-resolved.service",
-		Verb: "restart",
+		Verb: "reload-or-restart",
 	}
 
 	if err := u.UnitCommands(ctx); err != nil {
@@ -217,7 +219,7 @@ func restartResolved(ctx context.Context) error {
 }
 
 func (d *GlobalDns) AddDns(ctx context.Context, w http.ResponseWriter) error {
-	m, err := configfile.Load("/etc/systemd/resolved.conf")
+	m, err := configfile.Load(resolvedConfFile)
 	if err != nil {
 		return err
 	}
@@ -242,8 +244,8 @@ func (d *GlobalDns) AddDns(ctx context.Context, w http.ResponseWriter) error {
 		return err
 	}
 
-	if err := restartResolved(ctx); err != nil {
-		log.Errorf("Failed to restart systemd-resolved: %v", err)
+	if err := reloadResolved(ctx); err != nil {
+		log.Errorf("Failed to reload systemd-resolved: %v", err)
 		return err
 	}
 
@@ -251,7 +253,7 @@ func (d *GlobalDns) AddDns(ctx context.Context, w http.ResponseWriter) error {
 }
 
 func (d *GlobalDns) RemoveDns(ctx context.Context, w http.ResponseWriter) error {
-	m, err := configfile.Load("/etc/systemd/resolved.conf")
+	m, err := configfile.Load(resolvedConfFile)
 	if err != nil {
 		return err
 	}
@@ -278,8 +280,8 @@ func (d *GlobalDns) RemoveDns(ctx context.Context, w http.ResponseWriter) error
 		return err
 	}
 
-	if err := restartResolved(ctx); err != nil {
-		log.Errorf("Failed to restart systemd-resolved: %v", err)
+	if err := reloadResolved(ctx); err != nil {
+		log.Errorf("Failed to reload systemd-resolved: %v", err)
 		return err
 	}
 
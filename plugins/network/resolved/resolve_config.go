@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package resolved
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/vmware/pmd-next-gen/pkg/configfile"
+	"github.com/vmware/pmd-next-gen/pkg/share"
+	"github.com/vmware/pmd-next-gen/pkg/system"
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+)
+
+const (
+	resolvedConfFile       = "/etc/systemd/resolved.conf"
+	resolvedConfDropinFile = "/etc/systemd/resolved.conf.d/50-photon-mgmt.conf"
+)
+
+// Drop-ins are looked up like systemd-resolved does, the ones in /etc override
+// those with the same name in /run and /lib.
+var resolvedConfDropinDirs = []string{"/etc/systemd/resolved.conf.d", "/run/systemd/resolved.conf.d", "/usr/local/lib/systemd/resolved.conf.d", "/usr/lib/systemd/resolved.conf.d"}
+
+// ResolvedConfig holds the [Resolve] options besides DNS= and Domains=. Nil
+// lists and empty strings are left alone, an empty list clears the option.
+type ResolvedConfig struct {
+	FallbackDNS               []string `json:"FallbackDNS"`
+	DNSSEC                    string   `json:"DNSSEC"`
+	DNSOverTLS                string   `json:"DNSOverTLS"`
+	LLMNR                     string   `json:"LLMNR"`
+	MulticastDNS              string   `json:"MulticastDNS"`
+	Cache                     string   `json:"Cache"`
+	DNSStubListener           string   `json:"DNSStubListener"`
+	DNSStubListenerExtra      []string `json:"DNSStubListenerExtra"`
+	ReadEtcHosts              string   `json:"ReadEtcHosts"`
+	ResolveUnicastSingleLabel string   `json:"ResolveUnicastSingleLabel"`
+}
+
+// ResolvedConfigDescribe reports the options as merged from resolved.conf and
+// its drop-ins along with the values systemd-resolved is running with. Only
+// the options resolved exposes on the bus are filled in Effective.
+type ResolvedConfigDescribe struct {
+	Configured ResolvedConfig `json:"Configured"`
+	Effective  ResolvedConfig `json:"Effective"`
+}
+
+func decodeResolvedConfigJSONRequest(r *http.Request) (*ResolvedConfig, error) {
+	c := ResolvedConfig{}
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// isStubListenerExtra accepts the DNSStubListenerExtra= forms: an address with
+// an optional port and an optional "udp:" or "tcp:" prefix.
+func isStubListenerExtra(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "udp:"), "tcp:")
+	if validator.IsIP(s) {
+		return true
+	}
+
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return false
+	}
+
+	return validator.IsIP(host) && validator.IsPort(port)
+}
+
+func (c *ResolvedConfig) validate() error {
+	if !validator.IsArrayEmpty(c.FallbackDNS) && !validator.IsIPs(c.FallbackDNS) {
+		return fmt.Errorf("invalid FallbackDNS='%v'", c.FallbackDNS)
+	}
+	for _, s := range c.DNSStubListenerExtra {
+		if !isStubListenerExtra(s) {
+			return fmt.Errorf("invalid DNSStubListenerExtra='%s'", s)
+		}
+	}
+	if !validator.IsEmpty(c.ReadEtcHosts) && !validator.IsBool(c.ReadEtcHosts) {
+		return fmt.Errorf("invalid ReadEtcHosts='%s'", c.ReadEtcHosts)
+	}
+	if !validator.IsEmpty(c.ResolveUnicastSingleLabel) && !validator.IsBool(c.ResolveUnicastSingleLabel) {
+		return fmt.Errorf("invalid ResolveUnicastSingleLabel='%s'", c.ResolveUnicastSingleLabel)
+	}
+
+	for _, m := range []struct {
+		name  string
+		value string
+		modes []string
+	}{
+		{"DNSSEC", c.DNSSEC, []string{"yes", "no", "allow-downgrade"}},
+		{"DNSOverTLS", c.DNSOverTLS, []string{"yes", "no", "opportunistic"}},
+		{"LLMNR", c.LLMNR, []string{"yes", "no", "resolve"}},
+		{"MulticastDNS", c.MulticastDNS, []string{"yes", "no", "resolve"}},
+		{"Cache", c.Cache, []string{"yes", "no", "no-negative"}},
+		{"DNSStubListener", c.DNSStubListener, []string{"yes", "no", "udp", "tcp"}},
+	} {
+		if !validator.IsEmpty(m.value) && !share.StringContains(m.modes, m.value) {
+			return fmt.Errorf("invalid %s='%s'", m.name, m.value)
+		}
+	}
+
+	return nil
+}
+
+func acquireResolvedConfDropinFiles() []string {
+	dropins := make(map[string]string)
+	for i := len(resolvedConfDropinDirs) - 1; i >= 0; i-- {
+		matches, _ := filepath.Glob(path.Join(resolvedConfDropinDirs[i], "*.conf"))
+		for _, f := range matches {
+			dropins[path.Base(f)] = f
+		}
+	}
+
+	names := make([]string, 0, len(dropins))
+	for n := range dropins {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	files := make([]string, 0, len(names))
+	for _, n := range names {
+		files = append(files, dropins[n])
+	}
+
+	return files
+}
+
+// parseResolvedConf merges resolved.conf and its drop-ins the way resolved
+// does: the last assignment of an option wins, list options accumulate and an
+// empty assignment resets them.
+func parseResolvedConf() *ResolvedConfig {
+	values := map[string]string{}
+	lists := map[string][]string{
+		"FallbackDNS":          nil,
+		"DNSStubListenerExtra": nil,
+	}
+
+	for _, p := range append([]string{resolvedConfFile}, acquireResolvedConfDropinFiles()...) {
+		m, err := configfile.Load(p)
+		if err != nil {
+			continue
+		}
+
+		sections, _ := m.Cfg.SectionsByName("Resolve")
+		for _, s := range sections {
+			for _, k := range s.Keys() {
+				// Empty assignments are not among the shadows
+				assignments := k.ValueWithShadows()
+				if k.Value() == "" {
+					assignments = append([]string{""}, assignments...)
+				}
+
+				for _, v := range assignments {
+					if _, ok := lists[k.Name()]; !ok {
+						values[k.Name()] = v
+						continue
+					}
+
+					if v == "" {
+						lists[k.Name()] = []string{}
+					} else {
+						lists[k.Name()] = append(lists[k.Name()], strings.Fields(v)...)
+					}
+				}
+			}
+		}
+	}
+
+	return &ResolvedConfig{
+		FallbackDNS:               lists["FallbackDNS"],
+		DNSSEC:                    values["DNSSEC"],
+		DNSOverTLS:                values["DNSOverTLS"],
+		LLMNR:                     values["LLMNR"],
+		MulticastDNS:              values["MulticastDNS"],
+		Cache:                     values["Cache"],
+		DNSStubListener:           values["DNSStubListener"],
+		DNSStubListenerExtra:      lists["DNSStubListenerExtra"],
+		ReadEtcHosts:              values["ReadEtcHosts"],
+		ResolveUnicastSingleLabel: values["ResolveUnicastSingleLabel"],
+	}
+}
+
+func createOrParseResolvedConfDropinFile() (*configfile.Meta, error) {
+	if !system.PathExists(resolvedConfDropinFile) {
+		if err := system.CreateDirectoryNested(path.Dir(resolvedConfDropinFile), 0755); err != nil {
+			return nil, err
+		}
+
+		f, err := os.Create(resolvedConfDropinFile)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+	}
+
+	return configfile.Load(resolvedConfDropinFile)
+}
+
+// setResolvedList replaces a list option. resolved adds up the assignments of
+// all files, so an empty one comes first to drop what earlier files set.
+// go-ini does not write empty shadow keys, the value goes into a later
+// [Resolve] section instead.
+func setResolvedList(m *configfile.Meta, key string, values []string) error {
+	for _, s := range m.Sections("Resolve") {
+		s.DeleteKey(key)
+	}
+
+	sections := m.Sections("Resolve")
+	for i := len(sections) - 1; i > 0; i-- {
+		if len(sections[i].Keys()) == 0 {
+			m.Cfg.DeleteSectionWithIndex("Resolve", i)
+		}
+	}
+
+	if _, err := m.Cfg.Section("Resolve").NewKey(key, ""); err != nil {
+		return err
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	sections = m.Sections("Resolve")
+	s := sections[len(sections)-1]
+	if len(sections) == 1 {
+		var err error
+		if s, err = m.Cfg.NewSection("Resolve"); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.NewKey(key, strings.Join(values, " "))
+	return err
+}
+
+// Configure writes the options into the managed drop-in and has
+// systemd-resolved pick them up.
+func (c *ResolvedConfig) Configure(ctx context.Context) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	m, err := createOrParseResolvedConfDropinFile()
+	if err != nil {
+		log.Errorf("Failed to create drop-in='%s': %v", resolvedConfDropinFile, err)
+		return err
+	}
+
+	if c.FallbackDNS != nil {
+		if err := setResolvedList(m, "FallbackDNS", c.FallbackDNS); err != nil {
+			return err
+		}
+	}
+	if c.DNSStubListenerExtra != nil {
+		if err := setResolvedList(m, "DNSStubListenerExtra", c.DNSStubListenerExtra); err != nil {
+			return err
+		}
+	}
+	if !validator.IsEmpty(c.ReadEtcHosts) {
+		m.SetKeySectionString("Resolve", "ReadEtcHosts", validator.BoolToString(c.ReadEtcHosts))
+	}
+	if !validator.IsEmpty(c.ResolveUnicastSingleLabel) {
+		m.SetKeySectionString("Resolve", "ResolveUnicastSingleLabel", validator.BoolToString(c.ResolveUnicastSingleLabel))
+	}
+
+	for _, o := range []struct {
+		key   string
+		value string
+	}{
+		{"DNSSEC", c.DNSSEC},
+		{"DNSOverTLS", c.DNSOverTLS},
+		{"LLMNR", c.LLMNR},
+		{"MulticastDNS", c.MulticastDNS},
+		{"Cache", c.Cache},
+		{"DNSStubListener", c.DNSStubListener},
+	} {
+		if !validator.IsEmpty(o.value) {
+			m.SetKeySectionString("Resolve", o.key, o.value)
+		}
+	}
+
+	if err := m.Save(); err != nil {
+		log.Errorf("Failed to update config file='%s': %v", m.Path, err)
+		return err
+	}
+
+	if err := reloadResolved(ctx); err != nil {
+		log.Errorf("Failed to reload systemd-resolved: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ResetResolvedConfig drops the managed drop-in
+func ResetResolvedConfig(ctx context.Context) error {
+	if !system.PathExists(resolvedConfDropinFile) {
+		return errors.New("drop-in does not exist")
+	}
+
+	if err := os.Remove(resolvedConfDropinFile); err != nil {
+		return err
+	}
+
+	if err := reloadResolved(ctx); err != nil {
+		log.Errorf("Failed to reload systemd-resolved: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func DescribeResolvedConfig(ctx context.Context) (*ResolvedConfigDescribe, error) {
+	d := ResolvedConfigDescribe{
+		Configured: *parseResolvedConf(),
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return nil, err
+	}
+	defer c.Close()
+
+	// Properties missing in older versions of resolved are left empty
+	if dns, err := c.DBusAcquireFallbackDnsFromResolveManager(ctx); err == nil {
+		d.Effective.FallbackDNS = []string{}
+		for _, s := range dns {
+			d.Effective.FallbackDNS = append(d.Effective.FallbackDNS, s.Dns)
+		}
+	}
+
+	for _, p := range []struct {
+		name  string
+		value *string
+	}{
+		{"DNSSEC", &d.Effective.DNSSEC},
+		{"DNSOverTLS", &d.Effective.DNSOverTLS},
+		{"LLMNR", &d.Effective.LLMNR},
+		{"MulticastDNS", &d.Effective.MulticastDNS},
+		{"DNSStubListener", &d.Effective.DNSStubListener},
+	} {
+		if v, err := c.DBusAcquireStringFromResolveManager(ctx, p.name); err == nil {
+			*p.value = v
+		}
+	}
+
+	return &d, nil
+}
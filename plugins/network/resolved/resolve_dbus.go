@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"strings"
 	"syscall"
 
 	"github.com/godbus/dbus/v5"
@@ -181,3 +182,225 @@ func (c *SDConnection) DBusAcquireDomainsFromResolveManager(ctx context.Context)
 
 	return buildDomainsMessage(variant)
 }
+
+func (c *SDConnection) DBusAcquireFallbackDnsFromResolveManager(ctx context.Context) ([]Dns, error) {
+	variant, err := c.object.GetProperty(dbusManagerinterface + ".FallbackDNS")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching FallbackDNS from resolved: %v", err)
+	}
+
+	return buildDnsMessage(variant, false)
+}
+
+// DBusAcquireStringFromResolveManager reads one of the mode properties like
+// DNSSEC or LLMNR of the manager
+func (c *SDConnection) DBusAcquireStringFromResolveManager(ctx context.Context, property string) (string, error) {
+	variant, err := c.object.GetProperty(dbusManagerinterface + "." + property)
+	if err != nil {
+		return "", fmt.Errorf("error fetching %s from resolved: %v", property, err)
+	}
+
+	s, ok := variant.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected type of %s: %s", property, variant.Signature())
+	}
+
+	return s, nil
+}
+
+// ResolvedAddress is one entry of the address array returned by ResolveHostname
+type ResolvedAddress struct {
+	Ifindex int32
+	Family  int32
+	Address []byte
+}
+
+// ResolvedRecord is one entry of the record array returned by ResolveRecord.
+// Data is the complete resource record in DNS wire format.
+type ResolvedRecord struct {
+	Ifindex int32
+	Class   uint16
+	Type    uint16
+	Data    []byte
+}
+
+func (c *SDConnection) DBusResolveHostname(ctx context.Context, index int, name string, family int) ([]ResolvedAddress, string, error) {
+	var addrs []ResolvedAddress
+	var canonical string
+	var flags uint64
+
+	if err := c.object.CallWithContext(ctx, dbusManagerinterface+".ResolveHostname", 0, int32(index), name, int32(family), uint64(0)).Store(&addrs, &canonical, &flags); err != nil {
+		return nil, "", err
+	}
+
+	return addrs, canonical, nil
+}
+
+func (c *SDConnection) DBusResolveRecord(ctx context.Context, index int, name string, class uint16, rrType uint16) ([]ResolvedRecord, error) {
+	var records []ResolvedRecord
+	var flags uint64
+
+	if err := c.object.CallWithContext(ctx, dbusManagerinterface+".ResolveRecord", 0, int32(index), name, class, rrType, uint64(0)).Store(&records, &flags); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+type linkDnsAddress struct {
+	Family  int32
+	Address []byte
+}
+
+type linkDomain struct {
+	Domain      string
+	RoutingOnly bool
+}
+
+func (c *SDConnection) DBusLinkSetDns(ctx context.Context, index int, dns []string) error {
+	addrs := []linkDnsAddress{}
+	for _, d := range dns {
+		ip := net.ParseIP(d)
+		if ip == nil {
+			return fmt.Errorf("invalid DNS server='%s'", d)
+		}
+
+		if ip.To4() != nil {
+			addrs = append(addrs, linkDnsAddress{Family: syscall.AF_INET, Address: ip.To4()})
+		} else {
+			addrs = append(addrs, linkDnsAddress{Family: syscall.AF_INET6, Address: ip.To16()})
+		}
+	}
+
+	return c.object.CallWithContext(ctx, dbusManagerinterface+".SetLinkDNS", 0, int32(index), addrs).Err
+}
+
+// DBusLinkSetDomains sets the search domains of a link. A leading "~" marks a
+// routing-only domain like in resolved.conf.
+func (c *SDConnection) DBusLinkSetDomains(ctx context.Context, index int, domains []string) error {
+	ds := []linkDomain{}
+	for _, d := range domains {
+		if strings.HasPrefix(d, "~") {
+			ds = append(ds, linkDomain{Domain: strings.TrimPrefix(d, "~"), RoutingOnly: true})
+		} else {
+			ds = append(ds, linkDomain{Domain: d})
+		}
+	}
+
+	return c.object.CallWithContext(ctx, dbusManagerinterface+".SetLinkDomains", 0, int32(index), ds).Err
+}
+
+func (c *SDConnection) DBusLinkSetDefaultRoute(ctx context.Context, index int, enable bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerinterface+".SetLinkDefaultRoute", 0, int32(index), enable).Err
+}
+
+// DBusLinkSetMode calls one of the SetLinkLLMNR, SetLinkMulticastDNS,
+// SetLinkDNSSEC or SetLinkDNSOverTLS methods which all take a mode string
+func (c *SDConnection) DBusLinkSetMode(ctx context.Context, method string, index int, mode string) error {
+	return c.object.CallWithContext(ctx, dbusManagerinterface+"."+method, 0, int32(index), mode).Err
+}
+
+func (c *SDConnection) DBusLinkRevert(ctx context.Context, index int) error {
+	return c.object.CallWithContext(ctx, dbusManagerinterface+".RevertLink", 0, int32(index)).Err
+}
+
+type TransactionStatistics struct {
+	CurrentTransactions uint64 `json:"CurrentTransactions"`
+	TotalTransactions   uint64 `json:"TotalTransactions"`
+}
+
+type CacheStatistics struct {
+	Size   uint64 `json:"Size"`
+	Hits   uint64 `json:"Hits"`
+	Misses uint64 `json:"Misses"`
+}
+
+type DNSSECStatistics struct {
+	Secure        uint64 `json:"Secure"`
+	Insecure      uint64 `json:"Insecure"`
+	Bogus         uint64 `json:"Bogus"`
+	Indeterminate uint64 `json:"Indeterminate"`
+}
+
+type currentDnsServer struct {
+	Ifindex int32
+	Family  int32
+	Address []byte
+}
+
+func (c *SDConnection) DBusAcquireTransactionStatistics(ctx context.Context) (*TransactionStatistics, error) {
+	s := TransactionStatistics{}
+	if err := c.object.StoreProperty(dbusManagerinterface+".TransactionStatistics", &s); err != nil {
+		return nil, fmt.Errorf("error fetching TransactionStatistics from resolved: %v", err)
+	}
+
+	return &s, nil
+}
+
+func (c *SDConnection) DBusAcquireCacheStatistics(ctx context.Context) (*CacheStatistics, error) {
+	s := CacheStatistics{}
+	if err := c.object.StoreProperty(dbusManagerinterface+".CacheStatistics", &s); err != nil {
+		return nil, fmt.Errorf("error fetching CacheStatistics from resolved: %v", err)
+	}
+
+	return &s, nil
+}
+
+func (c *SDConnection) DBusAcquireDNSSECStatistics(ctx context.Context) (*DNSSECStatistics, error) {
+	s := DNSSECStatistics{}
+	if err := c.object.StoreProperty(dbusManagerinterface+".DNSSECStatistics", &s); err != nil {
+		return nil, fmt.Errorf("error fetching DNSSECStatistics from resolved: %v", err)
+	}
+
+	return &s, nil
+}
+
+func (c *SDConnection) DBusAcquireDNSSECSupported(ctx context.Context) (bool, error) {
+	var supported bool
+	if err := c.object.StoreProperty(dbusManagerinterface+".DNSSECSupported", &supported); err != nil {
+		return false, fmt.Errorf("error fetching DNSSECSupported from resolved: %v", err)
+	}
+
+	return supported, nil
+}
+
+// DBusAcquireCurrentDnsServer returns the DNS server resolved currently talks
+// to for global lookups, an empty string when there is none
+func (c *SDConnection) DBusAcquireCurrentDnsServer(ctx context.Context) (string, error) {
+	s := currentDnsServer{}
+	if err := c.object.StoreProperty(dbusManagerinterface+".CurrentDNSServer", &s); err != nil {
+		return "", fmt.Errorf("error fetching CurrentDNSServer from resolved: %v", err)
+	}
+
+	if len(s.Address) == 0 {
+		return "", nil
+	}
+
+	return net.IP(s.Address).String(), nil
+}
+
+func (c *SDConnection) DBusAcquireDNSSECSupportedFromResolveLink(ctx context.Context, index int) (bool, error) {
+	var linkPath dbus.ObjectPath
+	if err := c.object.CallWithContext(ctx, dbusManagerinterface+".GetLink", 0, int32(index)).Store(&linkPath); err != nil {
+		return false, err
+	}
+
+	var supported bool
+	if err := c.conn.Object(dbusInterface, linkPath).StoreProperty("org.freedesktop.resolve1.Link.DNSSECSupported", &supported); err != nil {
+		return false, fmt.Errorf("error fetching DNSSECSupported from resolved: %v", err)
+	}
+
+	return supported, nil
+}
+
+func (c *SDConnection) DBusResetStatistics(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusManagerinterface+".ResetStatistics", 0).Err
+}
+
+func (c *SDConnection) DBusFlushCaches(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusManagerinterface+".FlushCaches", 0).Err
+}
+
+func (c *SDConnection) DBusResetServerFeatures(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusManagerinterface+".ResetServerFeatures", 0).Err
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package resolved
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+
+	"github.com/vmware/pmd-next-gen/pkg/share"
+	"github.com/vmware/pmd-next-gen/pkg/validator"
+)
+
+// LinkDns is the runtime DNS configuration of a link pushed to
+// systemd-resolved. Nil lists and empty strings are left alone, an empty list
+// clears the servers or domains. Domains starting with "~" are routing-only.
+// The configuration lasts until the link is reconfigured or reverted.
+type LinkDns struct {
+	Link         string   `json:"Link"`
+	DnsServers   []string `json:"DnsServers"`
+	Domains      []string `json:"Domains"`
+	DefaultRoute string   `json:"DefaultRoute"`
+	LLMNR        string   `json:"LLMNR"`
+	MulticastDNS string   `json:"MulticastDNS"`
+	DNSSEC       string   `json:"DNSSEC"`
+	DNSOverTLS   string   `json:"DNSOverTLS"`
+}
+
+func decodeLinkDnsJSONRequest(r *http.Request) (*LinkDns, error) {
+	d := LinkDns{}
+	if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+func (d *LinkDns) validate() error {
+	if !validator.IsArrayEmpty(d.DnsServers) && !validator.IsIPs(d.DnsServers) {
+		return fmt.Errorf("invalid DnsServers='%v'", d.DnsServers)
+	}
+	if !validator.IsEmpty(d.DefaultRoute) && !validator.IsBool(d.DefaultRoute) {
+		return fmt.Errorf("invalid DefaultRoute='%s'", d.DefaultRoute)
+	}
+
+	for _, m := range []struct {
+		name  string
+		value string
+		modes []string
+	}{
+		{"LLMNR", d.LLMNR, []string{"yes", "no", "resolve"}},
+		{"MulticastDNS", d.MulticastDNS, []string{"yes", "no", "resolve"}},
+		{"DNSSEC", d.DNSSEC, []string{"yes", "no", "allow-downgrade"}},
+		{"DNSOverTLS", d.DNSOverTLS, []string{"yes", "no", "opportunistic"}},
+	} {
+		if !validator.IsEmpty(m.value) && !share.StringContains(m.modes, m.value) {
+			return fmt.Errorf("invalid %s='%s'", m.name, m.value)
+		}
+	}
+
+	return nil
+}
+
+func (d *LinkDns) Configure(ctx context.Context) error {
+	if err := d.validate(); err != nil {
+		return err
+	}
+
+	l, err := netlink.LinkByName(d.Link)
+	if err != nil {
+		return err
+	}
+	index := l.Attrs().Index
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return err
+	}
+	defer c.Close()
+
+	if d.DnsServers != nil {
+		if err := c.DBusLinkSetDns(ctx, index, d.DnsServers); err != nil {
+			log.Errorf("Failed to set DNS servers of link='%s': %v", d.Link, err)
+			return err
+		}
+	}
+
+	if d.Domains != nil {
+		if err := c.DBusLinkSetDomains(ctx, index, d.Domains); err != nil {
+			log.Errorf("Failed to set domains of link='%s': %v", d.Link, err)
+			return err
+		}
+	}
+
+	if !validator.IsEmpty(d.DefaultRoute) {
+		if err := c.DBusLinkSetDefaultRoute(ctx, index, validator.BoolToString(d.DefaultRoute) == "yes"); err != nil {
+			log.Errorf("Failed to set default route of link='%s': %v", d.Link, err)
+			return err
+		}
+	}
+
+	for _, m := range []struct {
+		method string
+		value  string
+	}{
+		{"SetLinkLLMNR", d.LLMNR},
+		{"SetLinkMulticastDNS", d.MulticastDNS},
+		{"SetLinkDNSSEC", d.DNSSEC},
+		{"SetLinkDNSOverTLS", d.DNSOverTLS},
+	} {
+		if validator.IsEmpty(m.value) {
+			continue
+		}
+
+		if err := c.DBusLinkSetMode(ctx, m.method, index, m.value); err != nil {
+			log.Errorf("Failed to call %s on link='%s': %v", m.method, d.Link, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RevertLinkDns drops all runtime DNS configuration of the link
+func RevertLinkDns(ctx context.Context, link string) error {
+	l, err := netlink.LinkByName(link)
+	if err != nil {
+		return err
+	}
+
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return err
+	}
+	defer c.Close()
+
+	if err := c.DBusLinkRevert(ctx, l.Attrs().Index); err != nil {
+		log.Errorf("Failed to revert DNS configuration of link='%s': %v", link, err)
+		return err
+	}
+
+	return nil
+}
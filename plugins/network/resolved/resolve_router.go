@@ -80,6 +80,102 @@ func routerRemoveDns(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func routerConfigureLinkDns(w http.ResponseWriter, r *http.Request) {
+	d, err := decodeLinkDnsJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	d.Link = mux.Vars(r)["link"]
+	if err := d.Configure(r.Context()); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("configured", w)
+}
+
+func routerRevertLinkDns(w http.ResponseWriter, r *http.Request) {
+	if err := RevertLinkDns(r.Context(), mux.Vars(r)["link"]); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("reverted", w)
+}
+
+func routerDescribeResolvedConfig(w http.ResponseWriter, r *http.Request) {
+	d, err := DescribeResolvedConfig(r.Context())
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(d, w)
+}
+
+func routerConfigureResolvedConfig(w http.ResponseWriter, r *http.Request) {
+	c, err := decodeResolvedConfigJSONRequest(r)
+	if err != nil {
+		http.Error(w, "Error decoding request", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.Configure(r.Context()); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("configured", w)
+}
+
+func routerResetResolvedConfig(w http.ResponseWriter, r *http.Request) {
+	if err := ResetResolvedConfig(r.Context()); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("reset", w)
+}
+
+func routerAcquireStatistics(w http.ResponseWriter, r *http.Request) {
+	s, err := AcquireStatistics(r.Context())
+	if err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse(s, w)
+}
+
+func routerResetStatistics(w http.ResponseWriter, r *http.Request) {
+	if err := ResetStatistics(r.Context()); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("reset", w)
+}
+
+func routerFlushCaches(w http.ResponseWriter, r *http.Request) {
+	if err := FlushCaches(r.Context()); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("flushed", w)
+}
+
+func routerResetServerFeatures(w http.ResponseWriter, r *http.Request) {
+	if err := ResetServerFeatures(r.Context()); err != nil {
+		web.JSONResponseError(err, w)
+		return
+	}
+
+	web.JSONResponse("reset", w)
+}
+
 func RegisterRouterResolved(router *mux.Router) {
 	n := router.PathPrefix("/resolved").Subrouter().StrictSlash(false)
 
@@ -89,7 +185,18 @@ func RegisterRouterResolved(router *mux.Router) {
 	n.HandleFunc("/{link}/dns", routerAcquireLinkDns).Methods("GET")
 	n.HandleFunc("/{link}/domains", routerAcquireLinkDomains).Methods("GET")
 	n.HandleFunc("/{link}/currentdns", routerAcquireLinkCurrentDns).Methods("GET")
+	n.HandleFunc("/{link}/configure", routerConfigureLinkDns).Methods("POST")
+	n.HandleFunc("/{link}/revert", routerRevertLinkDns).Methods("POST")
 
 	n.HandleFunc("/add", routerAddDns).Methods("POST")
 	n.HandleFunc("/remove", routerRemoveDns).Methods("DELETE")
+
+	n.HandleFunc("/config", routerDescribeResolvedConfig).Methods("GET")
+	n.HandleFunc("/config", routerConfigureResolvedConfig).Methods("POST")
+	n.HandleFunc("/config", routerResetResolvedConfig).Methods("DELETE")
+
+	n.HandleFunc("/statistics", routerAcquireStatistics).Methods("GET")
+	n.HandleFunc("/statistics/reset", routerResetStatistics).Methods("POST")
+	n.HandleFunc("/flushcaches", routerFlushCaches).Methods("POST")
+	n.HandleFunc("/resetserverfeatures", routerResetServerFeatures).Methods("POST")
 }
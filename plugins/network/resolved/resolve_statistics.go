@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright 2023 VMware, Inc.
+
+package resolved
+
+import (
+	"context"
+	"net"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+type LinkStatistics struct {
+	Index            int32  `json:"Index"`
+	Link             string `json:"Link"`
+	CurrentDNSServer string `json:"CurrentDNSServer"`
+	DNSSECSupported  bool   `json:"DNSSECSupported"`
+}
+
+type Statistics struct {
+	Transactions     TransactionStatistics `json:"Transactions"`
+	Cache            CacheStatistics       `json:"Cache"`
+	DNSSEC           DNSSECStatistics      `json:"DNSSEC"`
+	DNSSECSupported  bool                  `json:"DNSSECSupported"`
+	CurrentDNSServer string                `json:"CurrentDNSServer"`
+	Links            []LinkStatistics      `json:"Links"`
+}
+
+func AcquireStatistics(ctx context.Context) (*Statistics, error) {
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return nil, err
+	}
+	defer c.Close()
+
+	s := Statistics{
+		Links: []LinkStatistics{},
+	}
+
+	t, err := c.DBusAcquireTransactionStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.Transactions = *t
+
+	cache, err := c.DBusAcquireCacheStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.Cache = *cache
+
+	dnssec, err := c.DBusAcquireDNSSECStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.DNSSEC = *dnssec
+
+	if s.DNSSECSupported, err = c.DBusAcquireDNSSECSupported(ctx); err != nil {
+		return nil, err
+	}
+	if s.CurrentDNSServer, err = c.DBusAcquireCurrentDnsServer(ctx); err != nil {
+		return nil, err
+	}
+
+	links, _ := netlink.LinkList()
+	for _, l := range links {
+		if l.Attrs().Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		ls := LinkStatistics{
+			Index: int32(l.Attrs().Index),
+			Link:  l.Attrs().Name,
+		}
+
+		// Links resolved does not track have no current server
+		if dns, err := c.DBusAcquireCurrentDnsFromResolveLink(ctx, l.Attrs().Index); err == nil && dns.Family != 0 {
+			ls.CurrentDNSServer = dns.Dns
+		}
+		if supported, err := c.DBusAcquireDNSSECSupportedFromResolveLink(ctx, l.Attrs().Index); err == nil {
+			ls.DNSSECSupported = supported
+		}
+
+		s.Links = append(s.Links, ls)
+	}
+
+	return &s, nil
+}
+
+func ResetStatistics(ctx context.Context) error {
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return err
+	}
+	defer c.Close()
+
+	if err := c.DBusResetStatistics(ctx); err != nil {
+		log.Errorf("Failed to reset resolved statistics: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func FlushCaches(ctx context.Context) error {
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return err
+	}
+	defer c.Close()
+
+	if err := c.DBusFlushCaches(ctx); err != nil {
+		log.Errorf("Failed to flush resolved caches: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// ResetServerFeatures makes resolved forget the feature levels learnt about
+// the DNS servers and probe them again
+func ResetServerFeatures(ctx context.Context) error {
+	c, err := NewSDConnection()
+	if err != nil {
+		log.Errorf("Failed to establish connection to the system bus: %s", err)
+		return err
+	}
+	defer c.Close()
+
+	if err := c.DBusResetServerFeatures(ctx); err != nil {
+		log.Errorf("Failed to reset resolved server features: %v", err)
+		return err
+	}
+
+	return nil
+}